@@ -46,6 +46,7 @@ export const logoutUser = async () => {
 
   const res = await authFetch(apiUrl, {
     method: "PATCH",
+    credentials: "include",
   });
 
   if (!res.ok) {
@@ -57,7 +58,7 @@ export const logoutUser = async () => {
 };
 
 export const refreshAccessToken = async () => {
-  const apiUrl = `${env.data.VITE_API_URL}/api/v1/auth/refresh`;
+  const apiUrl = `${env.data.VITE_API_URL}/api/v1/users/refresh`;
 
   const res = await fetch(apiUrl, {
     method: "POST",
@@ -4,22 +4,43 @@ import (
 	"conecta-mare-server/internal/config"
 	"conecta-mare-server/internal/databases/clickhouse"
 	"conecta-mare-server/internal/databases/postgres"
+	"conecta-mare-server/internal/databases/redis"
+	"conecta-mare-server/internal/modules/accounts/auditevents"
 	"conecta-mare-server/internal/modules/accounts/categories"
 	"conecta-mare-server/internal/modules/accounts/certifications"
 	"conecta-mare-server/internal/modules/accounts/communities"
+	"conecta-mare-server/internal/modules/accounts/dataexports"
+	"conecta-mare-server/internal/modules/accounts/emailverifications"
+	"conecta-mare-server/internal/modules/accounts/identities"
+	"conecta-mare-server/internal/modules/accounts/jwks"
 	"conecta-mare-server/internal/modules/accounts/locations"
 	"conecta-mare-server/internal/modules/accounts/metrics"
+	"conecta-mare-server/internal/modules/accounts/oidcstates"
 	"conecta-mare-server/internal/modules/accounts/onboardings"
+	"conecta-mare-server/internal/modules/accounts/passwordresets"
+	"conecta-mare-server/internal/modules/accounts/phoneotps"
 	"conecta-mare-server/internal/modules/accounts/projectimages"
 	"conecta-mare-server/internal/modules/accounts/projects"
+	"conecta-mare-server/internal/modules/accounts/search"
+	"conecta-mare-server/internal/modules/accounts/searchterms"
+	"conecta-mare-server/internal/modules/accounts/serviceareas"
 	"conecta-mare-server/internal/modules/accounts/serviceimages"
 	"conecta-mare-server/internal/modules/accounts/services"
 	"conecta-mare-server/internal/modules/accounts/session"
 	"conecta-mare-server/internal/modules/accounts/subcategories"
+	"conecta-mare-server/internal/modules/accounts/twofactor"
 	"conecta-mare-server/internal/modules/accounts/userprofiles"
 	"conecta-mare-server/internal/modules/accounts/users"
 	"conecta-mare-server/internal/server"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/jobs"
 	"conecta-mare-server/pkg/jwt"
+	"conecta-mare-server/pkg/lockout"
+	"conecta-mare-server/pkg/mailer"
+	"conecta-mare-server/pkg/oidc"
+	"conecta-mare-server/pkg/ranking"
+	"conecta-mare-server/pkg/security"
+	"conecta-mare-server/pkg/sms"
 	"conecta-mare-server/pkg/storage"
 	"context"
 	"fmt"
@@ -27,6 +48,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -97,11 +119,92 @@ func main() {
 		cfg.Environment,
 	)
 
+	if cfg.StoragePrivateBucketName == "" || cfg.StoragePrivateBucketName == cfg.StorageBucketName {
+		logger.Error("a private storage bucket, distinct from the public one, is required")
+		os.Exit(1)
+	}
+	privateStorageClient := storage.NewStorageClient(
+		cfg.StorageURL,
+		cfg.StorageAccessKey,
+		cfg.StorageSecretKey,
+		cfg.StoragePrivateBucketName,
+		cfg.Environment,
+	)
+
 	logger.Info(fmt.Sprintf("Launching %s with the following settings:", cfg.AppName),
 		"port", cfg.Port,
 	)
 
-	tokenProvider := jwt.NewProvider(cfg.JWTAccessKey, cfg.JWTRefreshKey)
+	if err := security.SetPasswordParams(security.PasswordParams{
+		Algorithm:         cfg.PasswordHashAlgorithm,
+		BcryptCost:        cfg.PasswordBcryptCost,
+		Argon2Memory:      cfg.PasswordArgon2Memory,
+		Argon2Iterations:  cfg.PasswordArgon2Iterations,
+		Argon2Parallelism: cfg.PasswordArgon2Threads,
+	}); err != nil {
+		logger.Error("invalid password hashing settings", "err", err)
+		os.Exit(1)
+	}
+
+	accessKeys := jwt.NewHMACKeySet(cfg.JWTAccessKey)
+	if cfg.JWTAlgorithm != "" && cfg.JWTAlgorithm != jwt.AlgorithmHS256 {
+		keySet, err := jwt.LoadKeySet(cfg.JWTAlgorithm, cfg.JWTKeysDir, cfg.JWTActiveKeyID)
+		if err != nil {
+			logger.Error("failed to load jwt signing keys", "err", err)
+			os.Exit(1)
+		}
+		accessKeys = keySet
+	}
+
+	tokenProvider := jwt.NewProvider(accessKeys, cfg.JWTRefreshKey)
+
+	secretBox, err := security.NewSecretBox(cfg.TOTPEncryptionKey)
+	if err != nil {
+		logger.Error("invalid totp encryption key", "err", err)
+		os.Exit(1)
+	}
+
+	if cfg.AuditIdentifierKey == "" {
+		logger.Error("audit identifier key is required")
+		os.Exit(1)
+	}
+
+	var mailClient mailer.Mailer
+	switch cfg.MailDriver {
+	case "resend":
+		mailClient = mailer.NewResendMailer(cfg.ResendKey, cfg.MailFrom)
+	default:
+		mailClient = mailer.NewLogMailer(cfg.MailOutboxDir, logger)
+	}
+
+	// No SMS provider is integrated yet, codes are logged and written to the outbox.
+	var smsSender sms.SMSSender = sms.NewLogSender(cfg.SMSOutboxDir, logger)
+
+	var lockoutStore lockout.Store
+	switch cfg.LockoutStore {
+	case "redis":
+		logger.Info("Starting redis connection")
+		rdb := redis.New(cfg.RedisHost, cfg.RedisPort)
+		defer rdb.Close()
+		lockoutStore = lockout.NewRedisStore(rdb.Client())
+	default:
+		lockoutStore = lockout.NewMemoryStore()
+	}
+	loginGuard := lockout.NewLoginGuard(lockoutStore, lockout.DefaultEmailPolicy, lockout.DefaultIPPolicy)
+
+	var oidcProvider *oidc.Provider
+	if cfg.OIDCIssuer != "" {
+		redirectURL := cfg.OIDCRedirectURL
+		if redirectURL == "" {
+			redirectURL = strings.TrimRight(cfg.AppURL, "/") + "/auth/oidc/callback"
+		}
+		oidcProvider = oidc.NewProvider(oidc.Config{
+			Issuer:       cfg.OIDCIssuer,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  redirectURL,
+		})
+	}
 
 	subcategoriesRepo := subcategories.NewRepository(pg.DB())
 	categoriesRepo := categories.NewRepository(pg.DB())
@@ -115,17 +218,61 @@ func main() {
 	serviceImagesRepo := serviceimages.NewRepository(pg.DB())
 	locationsRepo := locations.NewRepository(pg.DB())
 	communitiesRepo := communities.NewRepository(pg.DB())
+	serviceAreasRepo := serviceareas.NewRepository(pg.DB())
+	searchRepo := search.NewRepository(pg.DB())
+	searchTermsRepo := searchterms.NewRepository(pg.DB())
+	passwordResetsRepo := passwordresets.NewRepository(pg.DB())
+	emailVerificationsRepo := emailverifications.NewRepository(pg.DB())
+	identitiesRepo := identities.NewRepository(pg.DB())
+	oidcStatesRepo := oidcstates.NewRepository(pg.DB())
+	twoFactorRepo := twofactor.NewRepository(pg.DB())
+	phoneOTPsRepo := phoneotps.NewRepository(pg.DB())
 	metricsRepo := metrics.NewRepository(ch.DB())
+	dataExportsRepo := dataexports.NewRepository(pg.DB())
+	auditEventsRepo := auditevents.NewRepository(pg.DB())
 
 	sessionsService := session.NewService(sessionsRepo, logger)
 	subcategoriesService := subcategories.NewService(subcategoriesRepo, logger)
+	twoFactorService := twofactor.NewService(pg.DB(), twoFactorRepo, secretBox, cfg.AppName, logger)
+	auditEventsService := auditevents.NewService(
+		auditEventsRepo,
+		cfg.AuditIdentifierKey,
+		time.Duration(cfg.AuditNetworkRetentionDays)*24*time.Hour,
+		logger,
+	)
 	usersService := users.NewService(
 		pg.DB(),
 		usersRepo,
 		userProfilesRepo,
 		sessionsService,
+		passwordResetsRepo,
+		emailVerificationsRepo,
+		identitiesRepo,
+		oidcStatesRepo,
+		phoneOTPsRepo,
 		storageClient,
+		privateStorageClient,
 		*tokenProvider,
+		loginGuard,
+		twoFactorService,
+		auditEventsService,
+		oidcProvider,
+		mailClient,
+		smsSender,
+		cfg.AppURL,
+		cfg.HideUnverifiedProfessionals,
+		time.Duration(cfg.AccountDeletionGraceDays)*24*time.Hour,
+		ranking.NewEngine(ranking.Config{
+			Weights: ranking.Weights{
+				ranking.SignalRating:       cfg.RankingRatingWeight,
+				ranking.SignalCompleteness: cfg.RankingCompletenessWeight,
+				ranking.SignalResponseRate: cfg.RankingResponseRateWeight,
+				ranking.SignalActivity:     cfg.RankingActivityWeight,
+				ranking.SignalProximity:    cfg.RankingProximityWeight,
+			},
+			RatingConfidence: cfg.RankingRatingConfidence,
+			ActivityHalfLife: time.Duration(cfg.RankingActivityHalfLifeDays) * 24 * time.Hour,
+		}),
 		logger,
 	)
 	categoriesService := categories.NewService(categoriesRepo, subcategoriesService, usersService, logger)
@@ -144,28 +291,101 @@ func main() {
 		logger,
 	)
 	communitiesService := communities.NewService(communitiesRepo, logger)
+	userProfilesService := userprofiles.NewService(pg.DB(), userProfilesRepo, subcategoriesRepo, storageClient, logger)
+	servicesService := services.NewService(pg.DB(), servicesRepo, serviceImagesRepo, userProfilesRepo, storageClient, logger)
+	searchService := search.NewService(searchRepo, cfg.HideUnverifiedProfessionals, logger)
+	searchTermsService := searchterms.NewService(pg.DB(), searchTermsRepo, logger)
+	serviceAreasService := serviceareas.NewService(pg.DB(), serviceAreasRepo, communitiesRepo, userProfilesRepo, logger)
+	certificationsService := certifications.NewService(certificationsRepo, userProfilesRepo, privateStorageClient, logger)
+	projectsService := projects.NewService(pg.DB(), projectsRepo, projectImagesRepo, servicesRepo, userProfilesRepo, storageClient, logger)
 	metricsService := metrics.NewService(metricsRepo, logger)
+	dataExportsService := dataexports.NewService(dataExportsRepo, metricsRepo, storageClient, privateStorageClient, cfg.AppURL, logger)
+
+	var verifiedEmailActions []string
+	for _, action := range strings.Split(cfg.EmailVerificationRequiredFor, ",") {
+		if action = strings.TrimSpace(action); action != "" {
+			verifiedEmailActions = append(verifiedEmailActions, action)
+		}
+	}
+
+	authMiddleware := middlewares.NewWithAuth(
+		tokenProvider,
+		sessionsService,
+		usersService,
+		verifiedEmailActions,
+		logger,
+	)
 
 	categoriesHandler := categories.NewHandler(categoriesService)
 	categoriesHandler.RegisterRoutes(router)
 
-	usersHandler := users.NewHandler(usersService, cfg.JWTAccessKey)
+	usersHandler := users.NewHandler(usersService, authMiddleware)
 	usersHandler.RegisterRoutes(router)
 
-	onboardingsHandler := onboardings.NewHandler(onboardingsService, cfg.JWTAccessKey)
+	onboardingsHandler := onboardings.NewHandler(onboardingsService, authMiddleware)
 	onboardingsHandler.RegisterRoutes(router)
 
+	userProfilesHandler := userprofiles.NewHandler(userProfilesService, authMiddleware)
+	userProfilesHandler.RegisterRoutes(router)
+
+	servicesHandler := services.NewHandler(servicesService, authMiddleware)
+	servicesHandler.RegisterRoutes(router)
+
+	projectsHandler := projects.NewHandler(projectsService, authMiddleware)
+	projectsHandler.RegisterRoutes(router)
+
+	certificationsHandler := certifications.NewHandler(certificationsService, authMiddleware)
+	certificationsHandler.RegisterRoutes(router)
+
+	serviceAreasHandler := serviceareas.NewHandler(serviceAreasService, authMiddleware)
+	serviceAreasHandler.RegisterRoutes(router)
+
+	searchHandler := search.NewHandler(searchService)
+	searchHandler.RegisterRoutes(router)
+
+	searchTermsHandler := searchterms.NewHandler(searchTermsService, authMiddleware)
+	searchTermsHandler.RegisterRoutes(router)
+
 	communitiesHandler := communities.NewHandler(communitiesService)
 	communitiesHandler.RegisterRoutes(router)
 
-	metricsHandler := metrics.NewHandler(metricsService, cfg.JWTAccessKey)
+	metricsHandler := metrics.NewHandler(metricsService, authMiddleware)
 	metricsHandler.RegisterRoutes(router)
 
+	twoFactorHandler := twofactor.NewHandler(twoFactorService, authMiddleware)
+	twoFactorHandler.RegisterRoutes(router)
+
+	dataExportsHandler := dataexports.NewHandler(dataExportsService, authMiddleware)
+	dataExportsHandler.RegisterRoutes(router)
+
+	auditEventsHandler := auditevents.NewHandler(auditEventsService, authMiddleware)
+	auditEventsHandler.RegisterRoutes(router)
+
+	jwksHandler := jwks.NewHandler(tokenProvider)
+	jwksHandler.RegisterRoutes(router)
+
+	jobsCtx, cancelJobs := context.WithCancel(context.Background())
+	defer cancelJobs()
+
+	go jobs.Every(jobsCtx, time.Hour, func(ctx context.Context) {
+		_ = usersService.AnonymizeDeletedUsers(ctx)
+		usersService.RemoveExpiredOIDCStates(ctx)
+		twoFactorService.RemoveExpiredChallenges(ctx)
+		auditEventsService.RedactExpiredNetworkData(ctx)
+	})
+	go jobs.Every(jobsCtx, time.Minute, func(ctx context.Context) {
+		dataExportsService.ProcessPending(ctx)
+		dataExportsService.RemoveExpired(ctx)
+	})
+	go jobs.Every(jobsCtx, 30*time.Second, func(ctx context.Context) {
+		searchService.RefreshSuggestions(ctx)
+	})
+
 	done := make(chan bool, 1)
 
 	go gracefulShutdown(server, done, logger)
 
-	err := server.ListenAndServe()
+	err = server.ListenAndServe()
 	if err != nil && err != http.ErrServerClosed {
 		panic(fmt.Sprintf("http server error: %s", err))
 	}
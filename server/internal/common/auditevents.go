@@ -0,0 +1,47 @@
+package common
+
+import "time"
+
+type (
+	// AuditEventInput describes a security event as seen by the code recording it.
+	// The client IP and user agent are taken from the request context. Identifier is
+	// the email or phone typed on a login that matched no account, it is only stored
+	// as a keyed hash.
+	AuditEventInput struct {
+		Type         string
+		Outcome      string
+		ActorUserID  string
+		TargetUserID string
+		Identifier   string
+		Metadata     map[string]any
+	}
+
+	AuditEventFilter struct {
+		UserID     string
+		Type       string
+		Outcome    string
+		IPAddress  string
+		Identifier string
+		From       string
+		To         string
+		Cursor     string
+		Limit      int
+	}
+
+	AuditEvent struct {
+		ID           string         `json:"id"`
+		Type         string         `json:"type"`
+		Outcome      string         `json:"outcome"`
+		ActorUserID  *string        `json:"actor_user_id"`
+		TargetUserID *string        `json:"target_user_id"`
+		IPAddress    *string        `json:"ip_address"`
+		UserAgent    *string        `json:"user_agent"`
+		Metadata     map[string]any `json:"metadata"`
+		CreatedAt    time.Time      `json:"created_at"`
+	}
+
+	AuditEventsPage struct {
+		Events     []AuditEvent `json:"events"`
+		NextCursor *string      `json:"next_cursor"`
+	}
+)
@@ -0,0 +1,33 @@
+package common
+
+import "time"
+
+type (
+	SaveCertificationRequest struct {
+		Institution string     `json:"institution"`
+		CourseName  string     `json:"course_name"`
+		StartDate   time.Time  `json:"start_date"`
+		EndDate     *time.Time `json:"end_date"`
+	}
+
+	ReviewCertificationRequest struct {
+		Status string  `json:"status"`
+		Note   *string `json:"note"`
+	}
+
+	// CertificationDetails is what the owner and the reviewers see, including the
+	// review outcome. The public profile only exposes Certification.
+	CertificationDetails struct {
+		ID            string     `json:"id"`
+		UserProfileID string     `json:"user_profile_id"`
+		Institution   string     `json:"institution"`
+		CourseName    string     `json:"course_name"`
+		StartDate     time.Time  `json:"start_date"`
+		EndDate       *time.Time `json:"end_date"`
+		Status        string     `json:"status"`
+		HasDocument   bool       `json:"has_document"`
+		ReviewedAt    *time.Time `json:"reviewed_at"`
+		ReviewNote    *string    `json:"review_note"`
+		CreatedAt     time.Time  `json:"created_at"`
+	}
+)
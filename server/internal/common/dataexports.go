@@ -0,0 +1,15 @@
+package common
+
+import "time"
+
+type (
+	DataExport struct {
+		ID                string     `json:"id"`
+		Status            string     `json:"status"`
+		CreatedAt         time.Time  `json:"created_at"`
+		CompletedAt       *time.Time `json:"completed_at"`
+		ExpiresAt         *time.Time `json:"expires_at"`
+		DownloadURL       *string    `json:"download_url,omitempty"`
+		DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty"`
+	}
+)
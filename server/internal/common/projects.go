@@ -0,0 +1,13 @@
+package common
+
+import "time"
+
+type (
+	SaveProjectRequest struct {
+		Name        string     `json:"name"`
+		Description string     `json:"description"`
+		CompletedAt *time.Time `json:"completed_at"`
+		Tags        []string   `json:"tags"`
+		ServiceID   *string    `json:"service_id"`
+	}
+)
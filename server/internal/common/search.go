@@ -0,0 +1,50 @@
+package common
+
+type (
+	SearchFilter struct {
+		Query         string
+		CategoryID    string
+		SubcategoryID string
+		CommunityID   string
+		Cursor        string
+		Limit         int
+	}
+
+	// SearchResult is a professional matching a search. Snippets are HTML escaped, with
+	// the matched terms wrapped in <mark> tags.
+	SearchResult struct {
+		UserID          string               `json:"user_id"`
+		FullName        string               `json:"full_name"`
+		ProfileImage    string               `json:"profile_image"`
+		JobDescription  string               `json:"job_description"`
+		Location        string               `json:"location"`
+		Category        string               `json:"category"`
+		Subcategory     string               `json:"subcategory"`
+		Rank            float64              `json:"rank"`
+		Snippet         string               `json:"snippet"`
+		MatchedServices []SearchServiceMatch `json:"matched_services"`
+	}
+
+	SearchServiceMatch struct {
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+		Snippet string `json:"snippet"`
+	}
+
+	SearchPage struct {
+		Results    []SearchResult `json:"results"`
+		NextCursor *string        `json:"next_cursor"`
+		TotalCount int            `json:"total_count"`
+	}
+)
+
+// SearchSuggestion is one autocomplete entry. ID is what the client filters or links
+// by: the category or subcategory id, the subcategory of a synonym, or the user id of
+// a professional. Service suggestions carry the service id and their professional.
+type SearchSuggestion struct {
+	Type           string `json:"type"`
+	ID             string `json:"id"`
+	Label          string `json:"label"`
+	Detail         string `json:"detail,omitempty"`
+	ProfessionalID string `json:"professional_id,omitempty"`
+}
@@ -0,0 +1,39 @@
+package common
+
+import "time"
+
+type (
+	// SaveSearchTermRequest links one local term to one or more subcategories.
+	SaveSearchTermRequest struct {
+		Term           string   `json:"term"`
+		SubcategoryIDs []string `json:"subcategory_ids"`
+	}
+
+	SearchTerm struct {
+		ID              string    `json:"id" db:"id"`
+		Term            string    `json:"term" db:"term"`
+		SubcategoryID   string    `json:"subcategory_id" db:"subcategory_id"`
+		SubcategoryName string    `json:"subcategory_name" db:"subcategory_name"`
+		CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	}
+
+	// SearchTermsImportReport tells how each row of a bulk import went. Rows already
+	// in the taxonomy are skipped, so the same file can be imported again.
+	SearchTermsImportReport struct {
+		Created int                      `json:"created"`
+		Skipped int                      `json:"skipped"`
+		Errors  []SearchTermsImportError `json:"errors"`
+	}
+
+	SearchTermsImportError struct {
+		Line    int    `json:"line"`
+		Message string `json:"message"`
+	}
+
+	SubcategorySuggestion struct {
+		ID           string `json:"id" db:"id"`
+		Name         string `json:"name" db:"name"`
+		CategoryID   string `json:"category_id" db:"category_id"`
+		CategoryName string `json:"category_name" db:"category_name"`
+	}
+)
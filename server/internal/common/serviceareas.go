@@ -0,0 +1,14 @@
+package common
+
+type (
+	// SaveServiceAreasRequest lists every community a professional serves, replacing the
+	// previous list.
+	SaveServiceAreasRequest struct {
+		Areas []ServiceAreaInput `json:"areas"`
+	}
+
+	ServiceAreaInput struct {
+		CommunityID     string `json:"community_id"`
+		TravelSurcharge *int   `json:"travel_surcharge"`
+	}
+)
@@ -0,0 +1,15 @@
+package common
+
+type (
+	SaveServiceRequest struct {
+		Name             string `json:"name"`
+		Description      string `json:"description"`
+		Price            int    `json:"price"`
+		OwnLocationPrice *int   `json:"own_location_price"`
+	}
+
+	// ReorderRequest lists every item of a collection in its new display order.
+	ReorderRequest struct {
+		IDs []string `json:"ids"`
+	}
+)
@@ -6,17 +6,20 @@ import (
 
 type (
 	Session struct {
-		ID         string    `json:"id"`
-		UserID     string    `json:"user_id"`
-		JTI        string    `json:"jti"`
-		Active     bool      `json:"active"`
-		CreatedAt  time.Time `json:"created_at"`
-		UupdatedAt time.Time `json:"updated_at"`
-		ExpiresAt  time.Time `json:"expires_at"`
+		ID         string     `json:"id"`
+		UserAgent  *string    `json:"user_agent"`
+		IPAddress  *string    `json:"ip_address"`
+		Active     bool       `json:"active"`
+		Current    bool       `json:"current"`
+		CreatedAt  time.Time  `json:"created_at"`
+		LastSeenAt *time.Time `json:"last_seen_at"`
+		ExpiresAt  time.Time  `json:"expires_at"`
 	}
 
 	CreateSessionRequest struct {
-		UserID string
-		JTI    string
+		UserID    string
+		JTI       string
+		UserAgent string
+		IPAddress string
 	}
 )
@@ -0,0 +1,25 @@
+package common
+
+type (
+	TOTPEnrollment struct {
+		Secret     string `json:"secret"`
+		OTPAuthURI string `json:"otpauth_uri"`
+	}
+
+	TOTPCodeRequest struct {
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recovery_code"`
+	}
+
+	RecoveryCodes struct {
+		Codes []string `json:"recovery_codes"`
+	}
+
+	TwoFactorLoginRequest struct {
+		ChallengeToken string `json:"challenge_token"`
+		Code           string `json:"code"`
+		RecoveryCode   string `json:"recovery_code"`
+		UserAgent      string `json:"-"`
+		IPAddress      string `json:"-"`
+	}
+)
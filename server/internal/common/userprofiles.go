@@ -0,0 +1,28 @@
+package common
+
+import "time"
+
+type (
+	UserProfile struct {
+		ID             string            `json:"id"`
+		UserID         string            `json:"user_id"`
+		FullName       string            `json:"full_name"`
+		SubcategoryID  *string           `json:"subcategory_id"`
+		ProfileImage   *string           `json:"profile_image"`
+		JobDescription *string           `json:"job_description"`
+		Phone          *string           `json:"phone"`
+		SocialLinks    map[string]string `json:"social_links"`
+		CreatedAt      time.Time         `json:"created_at"`
+		UpdatedAt      *time.Time        `json:"updated_at"`
+	}
+
+	// UpdateUserProfileRequest is a partial update, fields left out of the body are
+	// not changed. The profile image is sent as a separate multipart file.
+	UpdateUserProfileRequest struct {
+		FullName       *string           `json:"full_name"`
+		SubcategoryID  *string           `json:"subcategory_id"`
+		JobDescription *string           `json:"job_description"`
+		Phone          *string           `json:"phone"`
+		SocialLinks    map[string]string `json:"social_links"`
+	}
+)
@@ -1,20 +1,25 @@
 package common
 
 import (
+	"conecta-mare-server/pkg/ranking"
 	"conecta-mare-server/pkg/valueobjects"
 	"encoding/json"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type (
 	User struct {
 		ID              string            `json:"id" db:"id"`
 		Email           string            `json:"email" db:"email"`
+		Phone           *string           `json:"phone" db:"phone"`
 		Role            valueobjects.Role `json:"role" db:"role"`
 		FullName        *string           `json:"full_name" db:"full_name"`
 		ProfileImage    *string           `json:"profile_image" db:"profile_image"`
 		JobDescription  *string           `json:"job_description" db:"job_description"`
 		SubcategoryName *string           `json:"subcategory_name" db:"name"`
+		EmailVerifiedAt *time.Time        `json:"email_verified_at" db:"email_verified_at"`
 	}
 
 	RegisterUserRequest struct {
@@ -26,22 +31,132 @@ type (
 	}
 
 	LoginUserRequest struct {
-		Email    string `json:"email"`
+		Email     string `json:"email"`
+		Password  string `json:"password"`
+		UserAgent string `json:"-"`
+		IPAddress string `json:"-"`
+	}
+
+	OIDCAuthorizeRequest struct {
+		Role valueobjects.Role `json:"role"`
+	}
+
+	OIDCAuthorizeResponse struct {
+		AuthorizationURL string `json:"authorization_url"`
+	}
+
+	OIDCCallbackRequest struct {
+		Code      string `json:"code"`
+		State     string `json:"state"`
+		UserAgent string `json:"-"`
+		IPAddress string `json:"-"`
+	}
+
+	PhoneCodeRequest struct {
+		Phone string `json:"phone"`
+	}
+
+	PhoneRegisterRequest struct {
+		FullName  string            `json:"full_name"`
+		Phone     string            `json:"phone"`
+		Code      string            `json:"code"`
+		Role      valueobjects.Role `json:"role"`
+		UserAgent string            `json:"-"`
+		IPAddress string            `json:"-"`
+	}
+
+	PhoneLoginRequest struct {
+		Phone     string `json:"phone"`
+		Code      string `json:"code"`
+		UserAgent string `json:"-"`
+		IPAddress string `json:"-"`
+	}
+
+	PhoneVerifyRequest struct {
+		Phone string `json:"phone"`
+		Code  string `json:"code"`
+	}
+
+	ForgotPasswordRequest struct {
+		Email string `json:"email"`
+	}
+
+	ChangePasswordRequest struct {
+		CurrentPassword string `json:"current_password"`
+		Password        string `json:"password"`
+		ConfirmPassword string `json:"confirm_password"`
+	}
+
+	DeleteAccountRequest struct {
 		Password string `json:"password"`
 	}
 
+	ChangeRoleRequest struct {
+		Role valueobjects.Role `json:"role"`
+	}
+
+	ConfirmEmailRequest struct {
+		Token string `json:"token"`
+	}
+
+	ResetPasswordRequest struct {
+		Token           string `json:"token"`
+		Password        string `json:"password"`
+		ConfirmPassword string `json:"confirm_password"`
+	}
+
+	RefreshTokenRequest struct {
+		RefreshToken string
+		UserAgent    string
+		IPAddress    string
+	}
+
+	// LoginUserResponse carries either the token pair or, for accounts with two-factor
+	// enabled, the challenge token to send along with the code.
 	LoginUserResponse struct {
-		AccessToken  *string `json:"access_token"`
-		RefreshToken *string `json:"refresh_token"`
+		AccessToken       *string `json:"access_token"`
+		RefreshToken      *string `json:"refresh_token"`
+		TwoFactorRequired bool    `json:"two_factor_required"`
+		ChallengeToken    *string `json:"challenge_token,omitempty"`
 	}
 
 	GetProfessionalsResponse struct {
-		UserID         string `json:"user_id" db:"user_id"`
-		FullName       string `json:"full_name" db:"full_name"`
-		ProfileImage   string `json:"profile_image" db:"profile_image"`
-		JobDescription string `json:"job_description" db:"job_description"`
-		Rating         int    `json:"rating" db:"rating"`
-		Location       string `json:"location" db:"location"`
+		UserID         string         `json:"user_id" db:"user_id"`
+		FullName       string         `json:"full_name" db:"full_name"`
+		ProfileImage   string         `json:"profile_image" db:"profile_image"`
+		JobDescription string         `json:"job_description" db:"job_description"`
+		Rating         float64        `json:"rating" db:"rating"`
+		ReviewCount    int            `json:"review_count" db:"review_count"`
+		MinPrice       *int           `json:"min_price" db:"min_price"`
+		Location       string         `json:"location" db:"location"`
+		ServiceAreas   pq.StringArray `json:"service_areas" db:"service_areas"`
+		SortKey        string         `json:"-" db:"sort_key"`
+		// Ranking explains the position of the professional in a ranked listing. It is
+		// only filled in the explain mode.
+		Ranking *ranking.Explanation `json:"ranking,omitempty" db:"-"`
+	}
+
+	// ProfessionalsFilter narrows the public professionals listing. Prices use the same
+	// unit as service prices and MinRating goes from 1 to 5.
+	ProfessionalsFilter struct {
+		CategoryID    string
+		SubcategoryID string
+		CommunityID   string
+		MinPrice      *int
+		MaxPrice      *int
+		MinRating     *int
+		Sort          string
+		Cursor        string
+		Limit         int
+		// Explain adds the ranking explanation to every professional of a relevance
+		// listing.
+		Explain bool
+	}
+
+	ProfessionalsPage struct {
+		Professionals []*GetProfessionalsResponse `json:"professionals"`
+		NextCursor    *string                     `json:"next_cursor"`
+		TotalCount    int                         `json:"total_count"`
 	}
 
 	GetProfessionalByIDRaw struct {
@@ -56,9 +171,10 @@ type (
 		Subcategory        json.RawMessage `db:"subcategory"`
 		ProjectsJSON       json.RawMessage `db:"projects"`
 		CertificationsJSON json.RawMessage `db:"certifications"`
-		Rating             int             `db:"rating"`
+		Rating             float64         `db:"rating"`
 		Location           json.RawMessage `db:"location"`
 		ServicesJSON       json.RawMessage `db:"services"`
+		ServiceAreasJSON   json.RawMessage `db:"service_areas"`
 	}
 
 	GetProfessionalByIDResponse struct {
@@ -71,17 +187,21 @@ type (
 		SocialLinks    json.RawMessage `json:"social_links" db:"social_links"`
 		Category       json.RawMessage `json:"category" db:"category"`
 		Subcategory    json.RawMessage `json:"subcategory" db:"subcategory"`
-		Rating         int             `json:"rating" db:"location"`
+		Rating         float64         `json:"rating" db:"rating"`
 		Location       json.RawMessage `json:"location" db:"location"`
 		Projects       []Project       `json:"projects" db:"projects"`
 		Certifications []Certification `json:"certifications" db:"certifications"`
 		Services       []Service       `json:"services" db:"services"`
+		ServiceAreas   []ServiceArea   `json:"service_areas" db:"service_areas"`
 	}
 
 	Project struct {
 		ID          string         `json:"id"`
 		Name        string         `json:"name"`
 		Description string         `json:"description"`
+		CompletedAt *time.Time     `json:"completed_at"`
+		Tags        []string       `json:"tags"`
+		ServiceID   *string        `json:"service_id"`
 		Images      []ProjectImage `json:"images"`
 	}
 
@@ -97,6 +217,7 @@ type (
 		CourseName  string     `json:"course_name"`
 		StartDate   time.Time  `json:"start_date"`
 		EndDate     *time.Time `json:"end_date"`
+		Verified    bool       `json:"verified"`
 	}
 
 	Service struct {
@@ -120,4 +241,10 @@ type (
 		Complement  string `json:"complement" db:"complement"`
 		CommunityID string `json:"community_id" db:"community_id"`
 	}
+
+	ServiceArea struct {
+		CommunityID     string `json:"community_id"`
+		CommunityName   string `json:"community_name"`
+		TravelSurcharge *int   `json:"travel_surcharge"`
+	}
 )
@@ -33,15 +33,71 @@ type Config struct {
 	RedisHost string `mapstructure:"REDIS_HOST"`
 	RedisPort string `mapstructure:"REDIS_PORT"`
 
+	LockoutStore string `mapstructure:"LOCKOUT_STORE"`
+
 	StorageURL        string `mapstructure:"STORAGE_URL"`
 	StorageAccessKey  string `mapstructure:"STORAGE_ACCESS_KEY"`
 	StorageSecretKey  string `mapstructure:"STORAGE_SECRET_KEY"`
 	StorageBucketName string `mapstructure:"STORAGE_BUCKET_NAME"`
+	// StoragePrivateBucketName holds files only the server reads, such as data export
+	// archives and certification proofs. Unlike the main bucket it must never be made public.
+	StoragePrivateBucketName string `mapstructure:"STORAGE_PRIVATE_BUCKET_NAME"`
+
+	ResendKey     string `mapstructure:"RESEND_API_KEY"`
+	MailDriver    string `mapstructure:"MAIL_DRIVER"`
+	MailFrom      string `mapstructure:"MAIL_FROM"`
+	MailOutboxDir string `mapstructure:"MAIL_OUTBOX_DIR"`
+
+	SMSOutboxDir string `mapstructure:"SMS_OUTBOX_DIR"`
+
+	AppURL string `mapstructure:"APP_URL"`
+
+	EmailVerificationRequiredFor string `mapstructure:"EMAIL_VERIFICATION_REQUIRED_FOR"`
+	HideUnverifiedProfessionals  bool   `mapstructure:"HIDE_UNVERIFIED_PROFESSIONALS"`
+
+	AccountDeletionGraceDays int `mapstructure:"ACCOUNT_DELETION_GRACE_DAYS"`
+
+	// Ranking* tune the relevance order of the professionals listing. Weights only
+	// matter relative to each other; when none is set the defaults of pkg/ranking apply.
+	// Response rates are not recorded yet, so RankingResponseRateWeight has no effect.
+	RankingRatingWeight         float64 `mapstructure:"RANKING_RATING_WEIGHT"`
+	RankingCompletenessWeight   float64 `mapstructure:"RANKING_COMPLETENESS_WEIGHT"`
+	RankingResponseRateWeight   float64 `mapstructure:"RANKING_RESPONSE_RATE_WEIGHT"`
+	RankingActivityWeight       float64 `mapstructure:"RANKING_ACTIVITY_WEIGHT"`
+	RankingProximityWeight      float64 `mapstructure:"RANKING_PROXIMITY_WEIGHT"`
+	RankingRatingConfidence     float64 `mapstructure:"RANKING_RATING_CONFIDENCE"`
+	RankingActivityHalfLifeDays int     `mapstructure:"RANKING_ACTIVITY_HALF_LIFE_DAYS"`
+
+	PasswordHashAlgorithm    string `mapstructure:"PASSWORD_HASH_ALGORITHM"`
+	PasswordBcryptCost       int    `mapstructure:"PASSWORD_BCRYPT_COST"`
+	PasswordArgon2Memory     uint32 `mapstructure:"PASSWORD_ARGON2_MEMORY_KIB"`
+	PasswordArgon2Iterations uint32 `mapstructure:"PASSWORD_ARGON2_ITERATIONS"`
+	PasswordArgon2Threads    uint8  `mapstructure:"PASSWORD_ARGON2_PARALLELISM"`
+
+	// OIDCIssuer enables single sign-on with any OpenID Connect provider when set.
+	OIDCIssuer       string `mapstructure:"OIDC_ISSUER"`
+	OIDCClientID     string `mapstructure:"OIDC_CLIENT_ID"`
+	OIDCClientSecret string `mapstructure:"OIDC_CLIENT_SECRET"`
+	OIDCRedirectURL  string `mapstructure:"OIDC_REDIRECT_URL"`
+
+	// TOTPEncryptionKey encrypts the two-factor seeds at rest. Changing it disables
+	// every enrolled authenticator.
+	TOTPEncryptionKey string `mapstructure:"TOTP_ENCRYPTION_KEY"`
 
-	ResendKey string `mapstructure:"RESEND_API_KEY"`
+	// AuditIdentifierKey keys the hash of the emails and phones typed on failed logins.
+	// Changing it stops older events from matching identifier searches.
+	AuditIdentifierKey string `mapstructure:"AUDIT_IDENTIFIER_KEY"`
+	// AuditNetworkRetentionDays is how long audit events keep the client IP address and
+	// user agent before they are cleared.
+	AuditNetworkRetentionDays int `mapstructure:"AUDIT_NETWORK_RETENTION_DAYS"`
 
 	JWTAccessKey  string `mapstructure:"JWT_ACCESS_KEY"`
 	JWTRefreshKey string `mapstructure:"JWT_REFRESH_KEY"`
+	// JWTAlgorithm picks how access tokens are signed: HS256 with JWT_ACCESS_KEY,
+	// or RS256/EdDSA with the PEM keys found in JWTKeysDir.
+	JWTAlgorithm   string `mapstructure:"JWT_ALGORITHM"`
+	JWTKeysDir     string `mapstructure:"JWT_KEYS_DIR"`
+	JWTActiveKeyID string `mapstructure:"JWT_ACTIVE_KEY_ID"`
 }
 
 func GetConfig() *Config {
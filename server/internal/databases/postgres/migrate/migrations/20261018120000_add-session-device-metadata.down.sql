@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_sessions_user_id_active;
+
+ALTER TABLE sessions
+DROP COLUMN IF EXISTS last_seen_at,
+DROP COLUMN IF EXISTS ip_address,
+DROP COLUMN IF EXISTS user_agent;
@@ -0,0 +1,9 @@
+DELETE FROM reviews WHERE client_user_id IS NULL;
+
+ALTER TABLE reviews
+ALTER COLUMN client_user_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_users_pending_anonymization;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS anonymized_at;
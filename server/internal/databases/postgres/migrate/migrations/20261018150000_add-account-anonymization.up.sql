@@ -0,0 +1,9 @@
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_users_pending_anonymization ON users (deleted_at)
+WHERE deleted_at IS NOT NULL AND anonymized_at IS NULL;
+
+-- Reviews outlive their author: once the account is erased the review stays, without an author.
+ALTER TABLE reviews
+ALTER COLUMN client_user_id DROP NOT NULL;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS data_exports (
+    id VARCHAR(255) PRIMARY KEY DEFAULT new_id('dataexport'),
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    object_name TEXT,
+    download_token_hash VARCHAR(64),
+    download_expires_at TIMESTAMP,
+    failure_reason TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP,
+    expires_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id ON data_exports (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_data_exports_status ON data_exports (status, created_at);
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS phone_otps;
+
+DROP INDEX IF EXISTS idx_users_phone;
+
+-- Accounts created by phone keep a placeholder so the column can be required again.
+UPDATE users SET email = 'phone+' || id || '@phone.invalid' WHERE email IS NULL;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_or_phone;
+ALTER TABLE users DROP COLUMN IF EXISTS phone_verified_at;
+ALTER TABLE users DROP COLUMN IF EXISTS phone;
+ALTER TABLE users ALTER COLUMN email SET NOT NULL;
@@ -0,0 +1,34 @@
+-- Security records outlive the accounts they describe, so there are no foreign keys
+-- to users and anonymization leaves these rows in place.
+CREATE TABLE IF NOT EXISTS audit_events (
+    id VARCHAR(255) PRIMARY KEY DEFAULT new_id('auditevent'),
+    event_type VARCHAR(50) NOT NULL,
+    outcome VARCHAR(20) NOT NULL,
+    actor_user_id VARCHAR(255),
+    target_user_id VARCHAR(255),
+    ip_address VARCHAR(64),
+    user_agent TEXT,
+    metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_target_user_id ON audit_events (target_user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_user_id ON audit_events (actor_user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_event_type ON audit_events (event_type, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_ip_address ON audit_events (ip_address, created_at DESC);
+
+CREATE OR REPLACE FUNCTION audit_events_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_no_update_or_delete
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
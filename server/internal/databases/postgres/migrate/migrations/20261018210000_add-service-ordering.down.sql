@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_service_images_service_id;
+DROP INDEX IF EXISTS idx_services_user_profile_id;
+ALTER TABLE services DROP COLUMN IF EXISTS ordering;
@@ -0,0 +1,19 @@
+ALTER TABLE services ADD COLUMN IF NOT EXISTS ordering INTEGER NOT NULL DEFAULT 0;
+
+-- Existing catalogs keep the order in which they were created during onboarding.
+UPDATE services se SET ordering = ranked.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY user_profile_id ORDER BY created_at, id) - 1 AS position
+    FROM services
+) ranked
+WHERE ranked.id = se.id;
+
+UPDATE service_images sei SET ordering = ranked.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY service_id ORDER BY ordering, created_at, id) - 1 AS position
+    FROM service_images
+) ranked
+WHERE ranked.id = sei.id;
+
+CREATE INDEX IF NOT EXISTS idx_services_user_profile_id ON services (user_profile_id, ordering) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_service_images_service_id ON service_images (service_id, ordering);
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_project_images_project_id;
+DROP INDEX IF EXISTS idx_projects_user_profile_id;
+
+ALTER TABLE projects
+ALTER COLUMN description DROP NOT NULL,
+ALTER COLUMN description DROP DEFAULT;
+
+ALTER TABLE projects
+DROP COLUMN IF EXISTS updated_at,
+DROP COLUMN IF EXISTS service_id,
+DROP COLUMN IF EXISTS tags,
+DROP COLUMN IF EXISTS completed_at;
@@ -0,0 +1,14 @@
+ALTER TABLE projects
+ADD COLUMN IF NOT EXISTS completed_at DATE,
+ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}',
+ADD COLUMN IF NOT EXISTS service_id VARCHAR(255) REFERENCES services(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP;
+
+UPDATE projects SET description = '' WHERE description IS NULL;
+
+ALTER TABLE projects
+ALTER COLUMN description SET DEFAULT '',
+ALTER COLUMN description SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_projects_user_profile_id ON projects (user_profile_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_project_images_project_id ON project_images (project_id, ordering);
@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_certifications_status;
+DROP INDEX IF EXISTS idx_certifications_user_profile_id;
+
+ALTER TABLE certifications
+DROP CONSTRAINT IF EXISTS certifications_status_check;
+
+ALTER TABLE certifications
+DROP COLUMN IF EXISTS updated_at,
+DROP COLUMN IF EXISTS review_note,
+DROP COLUMN IF EXISTS reviewed_at,
+DROP COLUMN IF EXISTS reviewed_by,
+DROP COLUMN IF EXISTS status,
+DROP COLUMN IF EXISTS document_content_type,
+DROP COLUMN IF EXISTS document_object;
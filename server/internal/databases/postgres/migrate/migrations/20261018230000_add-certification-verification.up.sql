@@ -0,0 +1,14 @@
+ALTER TABLE certifications
+ADD COLUMN IF NOT EXISTS document_object TEXT,
+ADD COLUMN IF NOT EXISTS document_content_type VARCHAR(100),
+ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'pending',
+ADD COLUMN IF NOT EXISTS reviewed_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS review_note TEXT,
+ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP;
+
+ALTER TABLE certifications
+ADD CONSTRAINT certifications_status_check CHECK (status IN ('pending', 'verified', 'rejected'));
+
+CREATE INDEX IF NOT EXISTS idx_certifications_user_profile_id ON certifications (user_profile_id, start_date);
+CREATE INDEX IF NOT EXISTS idx_certifications_status ON certifications (status, created_at);
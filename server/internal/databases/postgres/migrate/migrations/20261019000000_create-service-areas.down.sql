@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_locations_community_id;
+DROP INDEX IF EXISTS idx_service_areas_community_id;
+
+DROP TABLE IF EXISTS service_areas;
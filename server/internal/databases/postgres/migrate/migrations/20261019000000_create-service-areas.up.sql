@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS service_areas (
+    id VARCHAR(255) PRIMARY KEY DEFAULT new_id('servicearea'),
+    user_profile_id VARCHAR(255) NOT NULL REFERENCES user_profiles(id) ON DELETE CASCADE,
+    community_id VARCHAR(255) NOT NULL REFERENCES communities(id),
+    travel_surcharge INTEGER CHECK (travel_surcharge >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (user_profile_id, community_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_areas_community_id ON service_areas (community_id);
+CREATE INDEX IF NOT EXISTS idx_locations_community_id ON locations (community_id);
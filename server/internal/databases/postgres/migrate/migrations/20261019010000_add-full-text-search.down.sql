@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_subcategories_search;
+DROP INDEX IF EXISTS idx_services_search;
+DROP INDEX IF EXISTS idx_user_profiles_search;
+
+DROP FUNCTION IF EXISTS search_subcategory_document(TEXT);
+DROP FUNCTION IF EXISTS search_service_document(TEXT, TEXT);
+DROP FUNCTION IF EXISTS search_profile_document(TEXT, TEXT);
+
+DROP TEXT SEARCH CONFIGURATION IF EXISTS portuguese_unaccent;
+DROP EXTENSION IF EXISTS unaccent;
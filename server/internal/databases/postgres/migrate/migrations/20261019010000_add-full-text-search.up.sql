@@ -0,0 +1,41 @@
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- Portuguese stemming that also ignores accents, so "tranca" finds "trança".
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'portuguese_unaccent') THEN
+        CREATE TEXT SEARCH CONFIGURATION portuguese_unaccent (COPY = portuguese);
+        ALTER TEXT SEARCH CONFIGURATION portuguese_unaccent
+            ALTER MAPPING FOR hword, hword_part, word WITH unaccent, portuguese_stem;
+    END IF;
+END
+$$;
+
+-- The documents are built by functions instead of stored columns, so the indexes below
+-- can be used by any query calling the same function.
+CREATE OR REPLACE FUNCTION search_profile_document(full_name TEXT, job_description TEXT)
+RETURNS TSVECTOR AS $$
+    SELECT setweight(to_tsvector('portuguese_unaccent', COALESCE(full_name, '')), 'A') ||
+        setweight(to_tsvector('portuguese_unaccent', COALESCE(job_description, '')), 'B');
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
+
+CREATE OR REPLACE FUNCTION search_service_document(name TEXT, description TEXT)
+RETURNS TSVECTOR AS $$
+    SELECT setweight(to_tsvector('portuguese_unaccent', COALESCE(name, '')), 'A') ||
+        setweight(to_tsvector('portuguese_unaccent', COALESCE(description, '')), 'C');
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
+
+CREATE OR REPLACE FUNCTION search_subcategory_document(name TEXT)
+RETURNS TSVECTOR AS $$
+    SELECT setweight(to_tsvector('portuguese_unaccent', COALESCE(name, '')), 'A');
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
+
+CREATE INDEX IF NOT EXISTS idx_user_profiles_search
+ON user_profiles USING GIN (search_profile_document(full_name, job_description));
+
+CREATE INDEX IF NOT EXISTS idx_services_search
+ON services USING GIN (search_service_document(name, description))
+WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_subcategories_search
+ON subcategories USING GIN (search_subcategory_document(name));
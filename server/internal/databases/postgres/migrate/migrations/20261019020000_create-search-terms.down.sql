@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_search_terms_subcategory_id;
+
+DROP TABLE IF EXISTS search_terms;
+
+DROP FUNCTION IF EXISTS search_normalize(TEXT);
@@ -0,0 +1,51 @@
+-- Lowercase, accent free and with punctuation turned into single spaces, so
+-- "Faz-Tudo" and "faz tudo" are the same term.
+CREATE OR REPLACE FUNCTION search_normalize(value TEXT)
+RETURNS TEXT AS $$
+    SELECT btrim(regexp_replace(lower(public.unaccent('public.unaccent', value)), '[^a-z0-9]+', ' ', 'g'));
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE STRICT;
+
+CREATE TABLE IF NOT EXISTS search_terms (
+    id VARCHAR(255) PRIMARY KEY DEFAULT new_id('searchterm'),
+    term VARCHAR(100) NOT NULL,
+    normalized_term VARCHAR(100) NOT NULL,
+    subcategory_id VARCHAR(255) NOT NULL REFERENCES subcategories(id) ON DELETE CASCADE,
+    created_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (normalized_term, subcategory_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_terms_subcategory_id ON search_terms (subcategory_id);
+
+INSERT INTO search_terms (term, normalized_term, subcategory_id)
+SELECT t.term, search_normalize(t.term), sc.id
+FROM (
+    VALUES
+        ('bombeiro hidráulico', 'Encanadores'),
+        ('bombeiro', 'Encanadores'),
+        ('vazamento', 'Encanadores'),
+        ('faz-tudo', 'Eletricistas'),
+        ('faz-tudo', 'Encanadores'),
+        ('faz-tudo', 'Pedreiros'),
+        ('faz-tudo', 'Pintores'),
+        ('faz-tudo', 'Marceneiros'),
+        ('marido de aluguel', 'Eletricistas'),
+        ('marido de aluguel', 'Encanadores'),
+        ('marido de aluguel', 'Pedreiros'),
+        ('marido de aluguel', 'Pintores'),
+        ('obra', 'Pedreiros'),
+        ('reboco', 'Pedreiros'),
+        ('unha', 'Manicures'),
+        ('pedicure', 'Manicures'),
+        ('trança', 'Cabeleireiros'),
+        ('escova', 'Cabeleireiros'),
+        ('faxina', 'Diaristas'),
+        ('faxineira', 'Diaristas'),
+        ('geladeira', 'Técnicos de eletrodomésticos'),
+        ('máquina de lavar', 'Técnicos de eletrodomésticos'),
+        ('explicadora', 'Monitores de reforço escolar'),
+        ('entrega', 'Motoboys'),
+        ('frete', 'Motoristas particulares')
+) AS t (term, subcategory)
+INNER JOIN subcategories sc ON sc.name = t.subcategory
+ON CONFLICT (normalized_term, subcategory_id) DO NOTHING;
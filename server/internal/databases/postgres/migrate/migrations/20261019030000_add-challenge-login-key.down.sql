@@ -0,0 +1,2 @@
+ALTER TABLE two_factor_challenges
+DROP COLUMN IF EXISTS login_key;
@@ -0,0 +1,4 @@
+-- The lockout key checked on the first factor, so the second one charges and clears
+-- the same counter.
+ALTER TABLE two_factor_challenges
+ADD COLUMN IF NOT EXISTS login_key VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE data_exports ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP;
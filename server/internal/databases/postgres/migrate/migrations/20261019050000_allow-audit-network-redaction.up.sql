@@ -0,0 +1,24 @@
+-- The table stays append-only, except for clearing the IP address and user agent,
+-- which are personal data kept only for a retention period and erased with the account.
+CREATE OR REPLACE FUNCTION audit_events_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND NEW.ip_address IS NULL
+        AND NEW.user_agent IS NULL
+        AND (NEW.id, NEW.event_type, NEW.outcome, NEW.actor_user_id, NEW.target_user_id, NEW.metadata, NEW.created_at)
+            IS NOT DISTINCT FROM
+            (OLD.id, OLD.event_type, OLD.outcome, OLD.actor_user_id, OLD.target_user_id, OLD.metadata, OLD.created_at)
+    THEN
+        RETURN NEW;
+    END IF;
+
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+-- Failed logins used to store the email or phone typed in clear. They are dropped,
+-- newer events only keep a keyed hash of it.
+ALTER TABLE audit_events DISABLE TRIGGER audit_events_no_update_or_delete;
+UPDATE audit_events SET metadata = metadata - 'identifier' WHERE metadata ? 'identifier';
+ALTER TABLE audit_events ENABLE TRIGGER audit_events_no_update_or_delete;
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type AuditEvent struct {
+	ID           string    `db:"id"`
+	EventType    string    `db:"event_type"`
+	Outcome      string    `db:"outcome"`
+	ActorUserID  *string   `db:"actor_user_id"`
+	TargetUserID *string   `db:"target_user_id"`
+	IPAddress    *string   `db:"ip_address"`
+	UserAgent    *string   `db:"user_agent"`
+	Metadata     []byte    `db:"metadata"`
+	CreatedAt    time.Time `db:"created_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type DataExport struct {
+	ID                string     `db:"id"`
+	UserID            string     `db:"user_id"`
+	Status            string     `db:"status"`
+	ObjectName        *string    `db:"object_name"`
+	DownloadTokenHash *string    `db:"download_token_hash"`
+	DownloadExpiresAt *time.Time `db:"download_expires_at"`
+	FailureReason     *string    `db:"failure_reason"`
+	CreatedAt         time.Time  `db:"created_at"`
+	ClaimedAt         *time.Time `db:"claimed_at"`
+	CompletedAt       *time.Time `db:"completed_at"`
+	ExpiresAt         *time.Time `db:"expires_at"`
+}
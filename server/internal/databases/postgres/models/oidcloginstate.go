@@ -0,0 +1,16 @@
+package models
+
+import (
+	"conecta-mare-server/pkg/valueobjects"
+	"time"
+)
+
+type OIDCLoginState struct {
+	ID           string            `db:"id"`
+	StateHash    string            `db:"state_hash"`
+	CodeVerifier string            `db:"code_verifier"`
+	Nonce        string            `db:"nonce"`
+	Role         valueobjects.Role `db:"role"`
+	ExpiresAt    time.Time         `db:"expires_at"`
+	CreatedAt    time.Time         `db:"created_at"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type PhoneOTP struct {
+	ID        string     `db:"id"`
+	Phone     string     `db:"phone"`
+	CodeHash  string     `db:"code_hash"`
+	Attempts  int        `db:"attempts"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
@@ -10,3 +10,12 @@ type Subcategory struct {
 	UpdatedAt  *time.Time `db:"updated_at"`
 	DeletedAt  *time.Time `db:"deleted_at"`
 }
+
+type SearchTerm struct {
+	ID             string    `db:"id"`
+	Term           string    `db:"term"`
+	NormalizedTerm string    `db:"normalized_term"`
+	SubcategoryID  string    `db:"subcategory_id"`
+	CreatedBy      *string   `db:"created_by"`
+	CreatedAt      time.Time `db:"created_at"`
+}
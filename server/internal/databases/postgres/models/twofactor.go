@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type TOTPFactor struct {
+	UserID          string     `db:"user_id"`
+	SecretEncrypted string     `db:"secret_encrypted"`
+	ConfirmedAt     *time.Time `db:"confirmed_at"`
+	LastUsedStep    int64      `db:"last_used_step"`
+	CreatedAt       time.Time  `db:"created_at"`
+}
+
+type TwoFactorChallenge struct {
+	ID        string    `db:"id"`
+	UserID    string    `db:"user_id"`
+	TokenHash string    `db:"token_hash"`
+	LoginKey  string    `db:"login_key"`
+	Attempts  int       `db:"attempts"`
+	ExpiresAt time.Time `db:"expires_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -6,11 +6,15 @@ import (
 )
 
 type User struct {
-	ID           string            `db:"id"`
-	Email        string            `db:"email"`
-	Role         valueobjects.Role `db:"role"`
-	PasswordHash string            `db:"password_hash"`
-	CreatedAt    time.Time         `db:"created_at"`
-	UpdatedAt    *time.Time        `db:"updated_at"`
-	DeletedAt    *time.Time        `db:"deleted_at"`
+	ID              string            `db:"id"`
+	Email           *string           `db:"email"`
+	Phone           *string           `db:"phone"`
+	Role            valueobjects.Role `db:"role"`
+	PasswordHash    string            `db:"password_hash"`
+	EmailVerifiedAt *time.Time        `db:"email_verified_at"`
+	PhoneVerifiedAt *time.Time        `db:"phone_verified_at"`
+	CreatedAt       time.Time         `db:"created_at"`
+	UpdatedAt       *time.Time        `db:"updated_at"`
+	DeletedAt       *time.Time        `db:"deleted_at"`
+	AnonymizedAt    *time.Time        `db:"anonymized_at"`
 }
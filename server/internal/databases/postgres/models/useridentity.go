@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type UserIdentity struct {
+	ID          string     `db:"id"`
+	UserID      string     `db:"user_id"`
+	Issuer      string     `db:"issuer"`
+	Subject     string     `db:"subject"`
+	Email       *string    `db:"email"`
+	CreatedAt   time.Time  `db:"created_at"`
+	LastLoginAt *time.Time `db:"last_login_at"`
+}
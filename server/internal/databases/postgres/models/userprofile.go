@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type UserProfile struct {
@@ -18,21 +20,32 @@ type UserProfile struct {
 }
 
 type Certification struct {
-	ID            string     `db:"id"`
-	UserProfileID string     `db:"user_profile_id"`
-	Institution   string     `db:"institution"`
-	CourseName    string     `db:"course_name"`
-	StartDate     time.Time  `db:"start_date"`
-	EndDate       *time.Time `db:"end_date"`
-	CreatedAt     time.Time  `db:"created_at"`
+	ID                  string     `db:"id"`
+	UserProfileID       string     `db:"user_profile_id"`
+	Institution         string     `db:"institution"`
+	CourseName          string     `db:"course_name"`
+	StartDate           time.Time  `db:"start_date"`
+	EndDate             *time.Time `db:"end_date"`
+	DocumentObject      *string    `db:"document_object"`
+	DocumentContentType *string    `db:"document_content_type"`
+	Status              string     `db:"status"`
+	ReviewedBy          *string    `db:"reviewed_by"`
+	ReviewedAt          *time.Time `db:"reviewed_at"`
+	ReviewNote          *string    `db:"review_note"`
+	CreatedAt           time.Time  `db:"created_at"`
+	UpdatedAt           *time.Time `db:"updated_at"`
 }
 
 type Project struct {
-	ID            string    `db:"id"`
-	UserProfileID string    `db:"user_profile_id"`
-	Name          string    `db:"name"`
-	Description   string    `db:"description"`
-	CreatedAt     time.Time `db:"created_at"`
+	ID            string         `db:"id"`
+	UserProfileID string         `db:"user_profile_id"`
+	ServiceID     *string        `db:"service_id"`
+	Name          string         `db:"name"`
+	Description   string         `db:"description"`
+	CompletedAt   *time.Time     `db:"completed_at"`
+	Tags          pq.StringArray `db:"tags"`
+	CreatedAt     time.Time      `db:"created_at"`
+	UpdatedAt     *time.Time     `db:"updated_at"`
 }
 
 type ProjectImage struct {
@@ -50,6 +63,7 @@ type Service struct {
 	Description      string     `db:"description"`
 	Price            int        `db:"price"`
 	OwnLocationPrice *int       `db:"own_location_price"`
+	Ordering         int        `db:"ordering"`
 	CreatedAt        time.Time  `db:"created_at"`
 	UpdatedAt        *time.Time `db:"updated_at"`
 	DeletedAt        *time.Time `db:"deleted_at"`
@@ -74,3 +88,11 @@ type Location struct {
 	UpdatedAt     *time.Time `db:"updated_at"`
 	DeletedAt     *time.Time `db:"deleted_at"`
 }
+
+type ServiceArea struct {
+	ID              string    `db:"id"`
+	UserProfileID   string    `db:"user_profile_id"`
+	CommunityID     string    `db:"community_id"`
+	TravelSurcharge *int      `db:"travel_surcharge"`
+	CreatedAt       time.Time `db:"created_at"`
+}
@@ -0,0 +1,39 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type Redis struct {
+	client *redis.Client
+}
+
+func New(host, port string) *Redis {
+	client := redis.NewClient(&redis.Options{
+		Addr: fmt.Sprintf("%s:%s", host, port),
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Fatalf("Error pinging Redis: %v", err)
+	}
+
+	return &Redis{client: client}
+}
+
+func (r *Redis) Close() error {
+	slog.Info("Disconnected from Redis")
+	return r.client.Close()
+}
+
+func (r *Redis) Client() *redis.Client {
+	return r.client
+}
@@ -0,0 +1,144 @@
+package auditevents
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/databases/postgres/models"
+	"conecta-mare-server/pkg/uid"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	TypeLogin          = "login"
+	TypeLogout         = "logout"
+	TypePasswordChange = "password_change"
+	TypePasswordReset  = "password_reset"
+	TypeRoleChange     = "role_change"
+
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+
+	// maxUserAgentLength keeps oversized headers from bloating the log.
+	maxUserAgentLength = 512
+)
+
+type AuditEvent struct {
+	id           string
+	eventType    string
+	outcome      string
+	actorUserID  *string
+	targetUserID *string
+	ipAddress    *string
+	userAgent    *string
+	metadata     map[string]any
+	createdAt    time.Time
+}
+
+func New(input common.AuditEventInput, ipAddress, userAgent string) (*AuditEvent, error) {
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	event := AuditEvent{
+		id:           uid.New("auditevent"),
+		eventType:    input.Type,
+		outcome:      input.Outcome,
+		actorUserID:  nilIfEmpty(input.ActorUserID),
+		targetUserID: nilIfEmpty(input.TargetUserID),
+		ipAddress:    nilIfEmpty(ipAddress),
+		userAgent:    nilIfEmpty(userAgent),
+		metadata:     input.Metadata,
+		createdAt:    time.Now().UTC(),
+	}
+
+	if err := event.validate(); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+func NewFromModel(m models.AuditEvent) *AuditEvent {
+	var metadata map[string]any
+	if len(m.Metadata) > 0 {
+		_ = json.Unmarshal(m.Metadata, &metadata)
+	}
+
+	return &AuditEvent{
+		id:           m.ID,
+		eventType:    m.EventType,
+		outcome:      m.Outcome,
+		actorUserID:  m.ActorUserID,
+		targetUserID: m.TargetUserID,
+		ipAddress:    m.IPAddress,
+		userAgent:    m.UserAgent,
+		metadata:     metadata,
+		createdAt:    m.CreatedAt,
+	}
+}
+
+func (e *AuditEvent) ToModel() (models.AuditEvent, error) {
+	metadata := e.metadata
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return models.AuditEvent{}, err
+	}
+
+	return models.AuditEvent{
+		ID:           e.id,
+		EventType:    e.eventType,
+		Outcome:      e.outcome,
+		ActorUserID:  e.actorUserID,
+		TargetUserID: e.targetUserID,
+		IPAddress:    e.ipAddress,
+		UserAgent:    e.userAgent,
+		Metadata:     raw,
+		CreatedAt:    e.createdAt,
+	}, nil
+}
+
+func (e *AuditEvent) ToResponse() common.AuditEvent {
+	metadata := e.metadata
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+
+	return common.AuditEvent{
+		ID:           e.id,
+		Type:         e.eventType,
+		Outcome:      e.outcome,
+		ActorUserID:  e.actorUserID,
+		TargetUserID: e.targetUserID,
+		IPAddress:    e.ipAddress,
+		UserAgent:    e.userAgent,
+		Metadata:     metadata,
+		CreatedAt:    e.createdAt,
+	}
+}
+
+func (e *AuditEvent) validate() error {
+	if e.eventType == "" {
+		return errors.New("event type is required")
+	}
+	if e.outcome != OutcomeSuccess && e.outcome != OutcomeFailure {
+		return errors.New("outcome must be success or failure")
+	}
+	return nil
+}
+
+func (e *AuditEvent) ID() string           { return e.id }
+func (e *AuditEvent) Type() string         { return e.eventType }
+func (e *AuditEvent) Outcome() string      { return e.outcome }
+func (e *AuditEvent) CreatedAt() time.Time { return e.createdAt }
+
+func nilIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,81 @@
+package auditevents
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/httphelpers"
+	"conecta-mare-server/pkg/valueobjects"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+var (
+	instance *auditEventsHandler
+	Once     sync.Once
+)
+
+func NewHandler(auditEventsService AuditEventsService, authMiddleware *middlewares.AuthMiddleware) *auditEventsHandler {
+	Once.Do(
+		func() {
+			instance = &auditEventsHandler{
+				auditEventsService: auditEventsService,
+				authMiddleware:     authMiddleware,
+			}
+		},
+	)
+
+	return instance
+}
+
+func (h auditEventsHandler) RegisterRoutes(r *chi.Mux) {
+	m := h.authMiddleware
+	r.Route(
+		"/api/v1/audit-events", func(r chi.Router) {
+			// Private
+			r.With(m.WithAuth, m.RequireRole(valueobjects.AnyRole...)).Get("/me", h.handleGetMyActivity)
+
+			// Admin
+			r.With(m.WithAuth, m.RequireRole(valueobjects.Admin)).Get("/", h.handleQuery)
+		},
+	)
+}
+
+func (h auditEventsHandler) handleGetMyActivity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	limit := httphelpers.ReadQueryInt(r.URL.Query(), "limit", defaultLimit)
+
+	page, err := h.auditEventsService.GetMyActivity(ctx, limit)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, page)
+}
+
+func (h auditEventsHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	filter := common.AuditEventFilter{
+		UserID:     query.Get("user_id"),
+		Type:       query.Get("type"),
+		Outcome:    query.Get("outcome"),
+		IPAddress:  query.Get("ip"),
+		Identifier: query.Get("identifier"),
+		From:       query.Get("from"),
+		To:         query.Get("to"),
+		Cursor:     query.Get("cursor"),
+		Limit:      httphelpers.ReadQueryInt(query, "limit", defaultLimit),
+	}
+
+	page, err := h.auditEventsService.Query(ctx, filter)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, page)
+}
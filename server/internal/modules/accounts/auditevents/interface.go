@@ -0,0 +1,52 @@
+package auditevents
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"context"
+	"log/slog"
+	"time"
+)
+
+type (
+	// ListFilter narrows a listing. UserID matches events where the user is either
+	// the actor or the target. AfterCreatedAt/AfterID continue from a previous page.
+	ListFilter struct {
+		UserID         string
+		EventType      string
+		Outcome        string
+		IPAddress      string
+		IdentifierHash string
+		From           *time.Time
+		To             *time.Time
+		AfterCreatedAt *time.Time
+		AfterID        string
+		Limit          int
+	}
+
+	AuditEventsRepository interface {
+		Create(ctx context.Context, event *AuditEvent) error
+		List(ctx context.Context, filter ListFilter) ([]*AuditEvent, error)
+		RedactNetworkBefore(ctx context.Context, before time.Time) (int64, error)
+	}
+	AuditEventsService interface {
+		Record(ctx context.Context, input common.AuditEventInput)
+		GetMyActivity(ctx context.Context, limit int) (*common.AuditEventsPage, *exceptions.ApiError[string])
+		Query(ctx context.Context, filter common.AuditEventFilter) (*common.AuditEventsPage, *exceptions.ApiError[string])
+		RedactExpiredNetworkData(ctx context.Context)
+	}
+	auditEventsService struct {
+		repository AuditEventsRepository
+		// identifierKey keys the hash of login identifiers, so the hashes cannot be
+		// matched against a list of emails or phones by whoever reads the table.
+		identifierKey string
+		// networkRetention is how long the IP address and user agent of an event are kept.
+		networkRetention time.Duration
+		logger           *slog.Logger
+	}
+	auditEventsHandler struct {
+		auditEventsService AuditEventsService
+		authMiddleware     *middlewares.AuthMiddleware
+	}
+)
@@ -0,0 +1,122 @@
+package auditevents
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) AuditEventsRepository {
+	return &repository{db}
+}
+
+func (r *repository) Create(ctx context.Context, event *AuditEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	model, err := event.ToModel()
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO audit_events (
+				id, event_type, outcome, actor_user_id, target_user_id,
+				ip_address, user_agent, metadata, created_at
+			) VALUES (
+				:id, :event_type, :outcome, :actor_user_id, :target_user_id,
+				:ip_address, :user_agent, :metadata, :created_at
+		)`
+
+	_, err = r.db.NamedExecContext(ctx, query, model)
+	return err
+}
+
+func (r *repository) List(ctx context.Context, filter ListFilter) ([]*AuditEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	conditions := []string{}
+	args := []any{}
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.UserID != "" {
+		placeholder := arg(filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("(target_user_id = %s OR actor_user_id = %s)", placeholder, placeholder))
+	}
+	if filter.EventType != "" {
+		conditions = append(conditions, "event_type = "+arg(filter.EventType))
+	}
+	if filter.Outcome != "" {
+		conditions = append(conditions, "outcome = "+arg(filter.Outcome))
+	}
+	if filter.IPAddress != "" {
+		conditions = append(conditions, "ip_address = "+arg(filter.IPAddress))
+	}
+	if filter.IdentifierHash != "" {
+		conditions = append(conditions, "metadata->>'identifier_hash' = "+arg(filter.IdentifierHash))
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= "+arg(*filter.From))
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at < "+arg(*filter.To))
+	}
+	if filter.AfterCreatedAt != nil {
+		conditions = append(
+			conditions,
+			fmt.Sprintf("(created_at, id) < (%s, %s)", arg(*filter.AfterCreatedAt), arg(filter.AfterID)),
+		)
+	}
+
+	query := "SELECT * FROM audit_events"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC LIMIT " + arg(filter.Limit)
+
+	var rows []models.AuditEvent
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	events := make([]*AuditEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, NewFromModel(row))
+	}
+
+	return events, nil
+}
+
+// RedactNetworkBefore clears the IP address and user agent of events created before
+// the given time. It is the only update the append-only trigger lets through.
+func (r *repository) RedactNetworkBefore(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		`
+		UPDATE audit_events SET ip_address = NULL, user_agent = NULL
+		WHERE created_at < $1
+			AND (ip_address IS NOT NULL OR user_agent IS NOT NULL)
+		`,
+		before,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,218 @@
+package auditevents
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/jwt"
+	"conecta-mare-server/pkg/security"
+	"context"
+	"encoding/base64"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// myActivityMaxLimit bounds how far back users can page through their own activity.
+	myActivityMaxLimit = 50
+	defaultLimit       = 20
+	maxLimit           = 200
+	// recordTimeout lets an event be stored even when the request that caused it
+	// was already cancelled.
+	recordTimeout = 2 * time.Second
+	// defaultNetworkRetention applies when no retention is configured.
+	defaultNetworkRetention = 90 * 24 * time.Hour
+)
+
+func NewService(
+	repository AuditEventsRepository,
+	identifierKey string,
+	networkRetention time.Duration,
+	logger *slog.Logger,
+) AuditEventsService {
+	if networkRetention <= 0 {
+		networkRetention = defaultNetworkRetention
+	}
+
+	return &auditEventsService{
+		repository:       repository,
+		identifierKey:    identifierKey,
+		networkRetention: networkRetention,
+		logger:           logger,
+	}
+}
+
+// Record stores a security event. Failures are only logged so that auditing never
+// blocks the action being audited.
+func (s *auditEventsService) Record(ctx context.Context, input common.AuditEventInput) {
+	client := middlewares.ClientInfoFromContext(ctx)
+
+	if input.Identifier != "" {
+		metadata := make(map[string]any, len(input.Metadata)+1)
+		for key, value := range input.Metadata {
+			metadata[key] = value
+		}
+		metadata["identifier_hash"] = s.hashIdentifier(input.Identifier)
+		input.Metadata = metadata
+	}
+
+	event, err := New(input, client.IPAddress, client.UserAgent)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to process audit event entity", "type", input.Type, "err", err)
+		return
+	}
+
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recordTimeout)
+	defer cancel()
+
+	if err := s.repository.Create(recordCtx, event); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to record audit event", "type", input.Type, "outcome", input.Outcome, "err", err)
+	}
+}
+
+func (s *auditEventsService) GetMyActivity(ctx context.Context, limit int) (*common.AuditEventsPage, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to get own security activity")
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	if limit <= 0 || limit > myActivityMaxLimit {
+		limit = defaultLimit
+	}
+
+	events, err := s.repository.List(ctx, ListFilter{UserID: c.UserID, Limit: limit})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to list audit events", "user_id", c.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	return &common.AuditEventsPage{Events: toResponses(events)}, nil
+}
+
+// Query lists events for incident response, newest first. Pages are chained through
+// next_cursor, which stays stable while new events are being appended.
+func (s *auditEventsService) Query(ctx context.Context, filter common.AuditEventFilter) (*common.AuditEventsPage, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to query audit events")
+
+	listFilter := ListFilter{
+		UserID:    filter.UserID,
+		EventType: filter.Type,
+		Outcome:   filter.Outcome,
+		IPAddress: filter.IPAddress,
+		Limit:     filter.Limit,
+	}
+	if filter.Identifier != "" {
+		listFilter.IdentifierHash = s.hashIdentifier(filter.Identifier)
+	}
+	if listFilter.Limit <= 0 || listFilter.Limit > maxLimit {
+		listFilter.Limit = defaultLimit
+	}
+
+	var err error
+	if listFilter.From, err = parseDate(filter.From); err != nil {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidDateFilter)
+	}
+	if listFilter.To, err = parseDate(filter.To); err != nil {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidDateFilter)
+	}
+
+	if filter.Cursor != "" {
+		createdAt, ID, ok := decodeCursor(filter.Cursor)
+		if !ok {
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidCursor)
+		}
+		listFilter.AfterCreatedAt = &createdAt
+		listFilter.AfterID = ID
+	}
+
+	// One extra row tells whether another page exists.
+	requested := listFilter.Limit
+	listFilter.Limit++
+
+	events, err := s.repository.List(ctx, listFilter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to list audit events", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	page := common.AuditEventsPage{}
+	if len(events) > requested {
+		events = events[:requested]
+		last := events[len(events)-1]
+		cursor := encodeCursor(last.CreatedAt(), last.ID())
+		page.NextCursor = &cursor
+	}
+	page.Events = toResponses(events)
+
+	return &page, nil
+}
+
+// RedactExpiredNetworkData clears the IP address and user agent of events older than
+// the retention. The events themselves are kept. It is run by a background job.
+func (s *auditEventsService) RedactExpiredNetworkData(ctx context.Context) {
+	redacted, err := s.repository.RedactNetworkBefore(ctx, time.Now().UTC().Add(-s.networkRetention))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to redact audit network data", "err", err)
+		return
+	}
+	if redacted > 0 {
+		s.logger.InfoContext(ctx, "audit network data redacted", "events", redacted)
+	}
+}
+
+// hashIdentifier normalizes the identifier so the same email typed with different
+// casing is still found by the same hash.
+func (s *auditEventsService) hashIdentifier(identifier string) string {
+	return security.KeyedHash(s.identifierKey, strings.ToLower(strings.TrimSpace(identifier)))
+}
+
+func toResponses(events []*AuditEvent) []common.AuditEvent {
+	responses := make([]common.AuditEvent, 0, len(events))
+	for _, event := range events {
+		responses = append(responses, event.ToResponse())
+	}
+	return responses
+}
+
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	// created_at is stored in UTC without a time zone.
+	t = t.UTC()
+	return &t, nil
+}
+
+func encodeCursor(createdAt time.Time, ID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt.Format(time.RFC3339Nano) + "|" + ID))
+}
+
+func decodeCursor(cursor string) (time.Time, string, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+
+	createdAtStr, ID, found := strings.Cut(string(raw), "|")
+	if !found || ID == "" {
+		return time.Time{}, "", false
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtStr)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+
+	return createdAt, ID, true
+}
@@ -4,17 +4,32 @@ import (
 	"conecta-mare-server/internal/databases/postgres/models"
 	"conecta-mare-server/pkg/exceptions"
 	"conecta-mare-server/pkg/uid"
+	"fmt"
+	"strings"
 	"time"
 )
 
+const (
+	StatusPending  = "pending"
+	StatusVerified = "verified"
+	StatusRejected = "rejected"
+)
+
 type Certification struct {
-	id            string
-	userProfileID string
-	institution   string
-	courseName    string
-	startDate     time.Time
-	endDate       *time.Time
-	createdAt     time.Time
+	id                  string
+	userProfileID       string
+	institution         string
+	courseName          string
+	startDate           time.Time
+	endDate             *time.Time
+	documentObject      *string
+	documentContentType *string
+	status              string
+	reviewedBy          *string
+	reviewedAt          *time.Time
+	reviewNote          *string
+	createdAt           time.Time
+	updatedAt           *time.Time
 }
 
 func New(
@@ -27,10 +42,11 @@ func New(
 	certification := Certification{
 		id:            uid.New("certification"),
 		userProfileID: userProfileID,
-		institution:   institution,
-		courseName:    courseName,
+		institution:   strings.TrimSpace(institution),
+		courseName:    strings.TrimSpace(courseName),
 		startDate:     startDate,
 		endDate:       endDate,
+		status:        StatusPending,
 		createdAt:     time.Now(),
 	}
 
@@ -43,37 +59,131 @@ func New(
 
 func NewFromModel(m models.Certification) *Certification {
 	return &Certification{
-		id:            m.ID,
-		userProfileID: m.UserProfileID,
-		institution:   m.Institution,
-		courseName:    m.CourseName,
-		startDate:     m.StartDate,
-		endDate:       m.EndDate,
-		createdAt:     m.CreatedAt,
+		id:                  m.ID,
+		userProfileID:       m.UserProfileID,
+		institution:         m.Institution,
+		courseName:          m.CourseName,
+		startDate:           m.StartDate,
+		endDate:             m.EndDate,
+		documentObject:      m.DocumentObject,
+		documentContentType: m.DocumentContentType,
+		status:              m.Status,
+		reviewedBy:          m.ReviewedBy,
+		reviewedAt:          m.ReviewedAt,
+		reviewNote:          m.ReviewNote,
+		createdAt:           m.CreatedAt,
+		updatedAt:           m.UpdatedAt,
 	}
 }
 
 func (c *Certification) ToModel() models.Certification {
 	return models.Certification{
-		ID:            c.id,
-		UserProfileID: c.userProfileID,
-		Institution:   c.institution,
-		CourseName:    c.courseName,
-		StartDate:     c.startDate,
-		EndDate:       c.endDate,
-		CreatedAt:     c.createdAt,
+		ID:                  c.id,
+		UserProfileID:       c.userProfileID,
+		Institution:         c.institution,
+		CourseName:          c.courseName,
+		StartDate:           c.startDate,
+		EndDate:             c.endDate,
+		DocumentObject:      c.documentObject,
+		DocumentContentType: c.documentContentType,
+		Status:              c.status,
+		ReviewedBy:          c.reviewedBy,
+		ReviewedAt:          c.reviewedAt,
+		ReviewNote:          c.reviewNote,
+		CreatedAt:           c.createdAt,
+		UpdatedAt:           c.updatedAt,
 	}
 }
 
+// Update changes the certification details. A reviewed certification goes back to
+// pending, since the verification covered the previous details.
+func (c *Certification) Update(institution, courseName string, startDate time.Time, endDate *time.Time) error {
+	c.institution = strings.TrimSpace(institution)
+	c.courseName = strings.TrimSpace(courseName)
+	c.startDate = startDate
+	c.endDate = endDate
+	c.resetReview()
+
+	return c.validate()
+}
+
+// AttachDocument replaces the proof document and sends the certification back to review.
+func (c *Certification) AttachDocument(objectName, contentType string) {
+	c.documentObject = &objectName
+	c.documentContentType = &contentType
+	c.resetReview()
+}
+
+// Review records the decision of an admin. Only verified and rejected are valid outcomes.
+func (c *Certification) Review(reviewerID, status string, note *string) error {
+	if status != StatusVerified && status != StatusRejected {
+		return fmt.Errorf("status must be %s or %s", StatusVerified, StatusRejected)
+	}
+
+	now := time.Now()
+	c.status = status
+	c.reviewedBy = &reviewerID
+	c.reviewedAt = &now
+	c.reviewNote = nilIfBlank(note)
+	c.updatedAt = &now
+
+	return nil
+}
+
+func (c *Certification) HasDocument() bool { return c.documentObject != nil }
+func (c *Certification) IsVerified() bool  { return c.status == StatusVerified }
+
+func (c *Certification) resetReview() {
+	now := time.Now()
+	c.status = StatusPending
+	c.reviewedBy = nil
+	c.reviewedAt = nil
+	c.reviewNote = nil
+	c.updatedAt = &now
+}
+
 func (c *Certification) validate() error {
+	if c.userProfileID == "" {
+		return fmt.Errorf("user_profile_id is required")
+	}
+	if c.institution == "" {
+		return fmt.Errorf("institution is required")
+	}
+	if c.courseName == "" {
+		return fmt.Errorf("course_name is required")
+	}
+	if c.startDate.IsZero() {
+		return fmt.Errorf("start_date is required")
+	}
+	if c.endDate != nil && c.endDate.Before(c.startDate) {
+		return fmt.Errorf("end_date cannot be before start_date")
+	}
 	return nil
 }
 
+func nilIfBlank(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*s)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
 // Getters
-func (c *Certification) ID() string            { return c.id }
-func (c *Certification) UserProfileID() string { return c.userProfileID }
-func (c *Certification) Institution() string   { return c.institution }
-func (c *Certification) CourseName() string    { return c.courseName }
-func (c *Certification) StartDate() time.Time  { return c.startDate }
-func (c *Certification) EndDate() *time.Time   { return c.endDate }
-func (c *Certification) CreatedAt() time.Time  { return c.createdAt }
+func (c *Certification) ID() string                   { return c.id }
+func (c *Certification) UserProfileID() string        { return c.userProfileID }
+func (c *Certification) Institution() string          { return c.institution }
+func (c *Certification) CourseName() string           { return c.courseName }
+func (c *Certification) StartDate() time.Time         { return c.startDate }
+func (c *Certification) EndDate() *time.Time          { return c.endDate }
+func (c *Certification) DocumentObject() *string      { return c.documentObject }
+func (c *Certification) DocumentContentType() *string { return c.documentContentType }
+func (c *Certification) Status() string               { return c.status }
+func (c *Certification) ReviewedBy() *string          { return c.reviewedBy }
+func (c *Certification) ReviewedAt() *time.Time       { return c.reviewedAt }
+func (c *Certification) ReviewNote() *string          { return c.reviewNote }
+func (c *Certification) CreatedAt() time.Time         { return c.createdAt }
+func (c *Certification) UpdatedAt() *time.Time        { return c.updatedAt }
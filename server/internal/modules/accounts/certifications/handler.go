@@ -0,0 +1,227 @@
+package certifications
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/httphelpers"
+	"conecta-mare-server/pkg/valueobjects"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxDocumentUploadSize bounds the multipart body kept in memory while parsing.
+const maxDocumentUploadSize = 32 << 20
+
+var (
+	instance *certificationsHandler
+	Once     sync.Once
+)
+
+func NewHandler(certificationsService CertificationsService, authMiddleware *middlewares.AuthMiddleware) *certificationsHandler {
+	Once.Do(
+		func() {
+			instance = &certificationsHandler{
+				certificationsService: certificationsService,
+				authMiddleware:        authMiddleware,
+			}
+		},
+	)
+
+	return instance
+}
+
+func (h certificationsHandler) RegisterRoutes(r *chi.Mux) {
+	m := h.authMiddleware
+	r.Route(
+		"/api/v1/me/certifications", func(r chi.Router) {
+			// Private
+			r.Group(func(r chi.Router) {
+				r.Use(m.WithAuth, m.RequireRole(valueobjects.Professional))
+				r.Get("/", h.handleGetMyCertifications)
+				r.Post("/", h.handleCreateCertification)
+				r.Put("/{certification_id}", h.handleUpdateCertification)
+				r.Delete("/{certification_id}", h.handleDeleteCertification)
+				r.Put("/{certification_id}/document", h.handleUploadDocument)
+				r.Get("/{certification_id}/document", h.handleGetMyDocument)
+			})
+		},
+	)
+	r.Route(
+		"/api/v1/certifications", func(r chi.Router) {
+			// Admin
+			r.Group(func(r chi.Router) {
+				r.Use(m.WithAuth, m.RequireRole(valueobjects.Admin))
+				r.Get("/", h.handleListForReview)
+				r.Get("/{certification_id}/document", h.handleGetDocument)
+				r.Patch("/{certification_id}/status", h.handleReviewCertification)
+			})
+		},
+	)
+}
+
+func (h certificationsHandler) handleGetMyCertifications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	certifications, err := h.certificationsService.GetMyCertifications(ctx)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, map[string]any{"certifications": certifications})
+}
+
+func (h certificationsHandler) handleCreateCertification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.SaveCertificationRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	certification, err := h.certificationsService.CreateCertification(ctx, body)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusCreated, certification)
+}
+
+func (h certificationsHandler) handleUpdateCertification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	certificationID := chi.URLParam(r, "certification_id")
+
+	var body common.SaveCertificationRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	certification, err := h.certificationsService.UpdateCertification(ctx, certificationID, body)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, certification)
+}
+
+func (h certificationsHandler) handleDeleteCertification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	certificationID := chi.URLParam(r, "certification_id")
+
+	if err := h.certificationsService.DeleteCertification(ctx, certificationID); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteSuccess(w, http.StatusOK)
+}
+
+// handleUploadDocument takes the proof in the multipart field "document".
+func (h certificationsHandler) handleUploadDocument(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	certificationID := chi.URLParam(r, "certification_id")
+
+	if err := r.ParseMultipartForm(maxDocumentUploadSize); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidRequestBody)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	_, file, err := r.FormFile("document")
+	if err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidCertificationDocument)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	certification, apiErr := h.certificationsService.UploadDocument(ctx, certificationID, file)
+	if apiErr != nil {
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, certification)
+}
+
+func (h certificationsHandler) handleGetMyDocument(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	certificationID := chi.URLParam(r, "certification_id")
+
+	document, contentType, err := h.certificationsService.OpenMyDocument(ctx, certificationID)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+	defer document.Close()
+
+	writeDocument(w, certificationID, contentType, document)
+}
+
+func (h certificationsHandler) handleListForReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	status := r.URL.Query().Get("status")
+
+	certifications, err := h.certificationsService.ListForReview(ctx, status)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, map[string]any{"certifications": certifications})
+}
+
+func (h certificationsHandler) handleGetDocument(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	certificationID := chi.URLParam(r, "certification_id")
+
+	document, contentType, err := h.certificationsService.OpenDocument(ctx, certificationID)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+	defer document.Close()
+
+	writeDocument(w, certificationID, contentType, document)
+}
+
+func (h certificationsHandler) handleReviewCertification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	certificationID := chi.URLParam(r, "certification_id")
+
+	var body common.ReviewCertificationRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	certification, err := h.certificationsService.ReviewCertification(ctx, certificationID, body)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, certification)
+}
+
+// writeDocument streams a proof as a download. Documents are uploaded by users, so the
+// browser is told not to guess another type and render it inline.
+func writeDocument(w http.ResponseWriter, certificationID, contentType string, document io.Reader) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "comprovante-"+certificationID))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, document)
+}
@@ -1,7 +1,48 @@
 package certifications
 
-import "github.com/jmoiron/sqlx"
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/modules/accounts/userprofiles"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/storage"
+	"context"
+	"io"
+	"log/slog"
+	"mime/multipart"
 
-type CertificationsRepository interface {
-	CreateTx(tx *sqlx.Tx, certification *Certification) error
-}
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	CertificationsRepository interface {
+		CreateTx(tx *sqlx.Tx, certification *Certification) error
+		Create(ctx context.Context, certification *Certification) error
+		GetByID(ctx context.Context, ID string) (*Certification, error)
+		GetByUserProfileID(ctx context.Context, userProfileID string) ([]*Certification, error)
+		GetByStatus(ctx context.Context, status string, limit int) ([]*Certification, error)
+		Update(ctx context.Context, certification *Certification) error
+		Delete(ctx context.Context, ID string) error
+	}
+	CertificationsService interface {
+		GetMyCertifications(ctx context.Context) ([]common.CertificationDetails, *exceptions.ApiError[string])
+		CreateCertification(ctx context.Context, input common.SaveCertificationRequest) (*common.CertificationDetails, *exceptions.ApiError[string])
+		UpdateCertification(ctx context.Context, ID string, input common.SaveCertificationRequest) (*common.CertificationDetails, *exceptions.ApiError[string])
+		DeleteCertification(ctx context.Context, ID string) *exceptions.ApiError[string]
+		UploadDocument(ctx context.Context, ID string, file *multipart.FileHeader) (*common.CertificationDetails, *exceptions.ApiError[string])
+		OpenMyDocument(ctx context.Context, ID string) (io.ReadCloser, string, *exceptions.ApiError[string])
+		ListForReview(ctx context.Context, status string) ([]common.CertificationDetails, *exceptions.ApiError[string])
+		OpenDocument(ctx context.Context, ID string) (io.ReadCloser, string, *exceptions.ApiError[string])
+		ReviewCertification(ctx context.Context, ID string, input common.ReviewCertificationRequest) (*common.CertificationDetails, *exceptions.ApiError[string])
+	}
+	certificationsService struct {
+		repository       CertificationsRepository
+		userProfilesRepo userprofiles.UserProfilesRepository
+		storageClient    *storage.StorageClient
+		logger           *slog.Logger
+	}
+	certificationsHandler struct {
+		certificationsService CertificationsService
+		authMiddleware        *middlewares.AuthMiddleware
+	}
+)
@@ -1,6 +1,12 @@
 package certifications
 
 import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/jmoiron/sqlx"
 )
 
@@ -12,16 +18,129 @@ func NewRepository(db *sqlx.DB) CertificationsRepository {
 	return &repository{db}
 }
 
-func (r *repository) CreateTx(tx *sqlx.Tx, certification *Certification) error {
-	model := certification.ToModel()
-	_, err := tx.NamedExec(`
+const insertQuery = `
 			INSERT INTO certifications (
-				id, user_profile_id, institution, course_name, start_date, end_date, created_at
+				id, user_profile_id, institution, course_name, start_date, end_date, status, created_at
 			) VALUES (
-				:id, :user_profile_id, :institution, :course_name, :start_date, :end_date, :created_at
+				:id, :user_profile_id, :institution, :course_name, :start_date, :end_date, :status, :created_at
 			)
+		`
+
+func (r *repository) CreateTx(tx *sqlx.Tx, certification *Certification) error {
+	model := certification.ToModel()
+	_, err := tx.NamedExec(insertQuery, &model)
+	return err
+}
+
+func (r *repository) Create(ctx context.Context, certification *Certification) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	model := certification.ToModel()
+	_, err := r.db.NamedExecContext(ctx, insertQuery, &model)
+	return err
+}
+
+func (r *repository) GetByID(ctx context.Context, ID string) (*Certification, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var model models.Certification
+	err := r.db.GetContext(ctx, &model, "SELECT * FROM certifications WHERE id = $1", ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFromModel(model), nil
+}
+
+func (r *repository) GetByUserProfileID(ctx context.Context, userProfileID string) ([]*Certification, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var rows []models.Certification
+	err := r.db.SelectContext(
+		ctx,
+		&rows,
+		`
+		SELECT * FROM certifications
+		WHERE user_profile_id = $1
+		ORDER BY start_date DESC, created_at DESC
 		`,
-		&model,
+		userProfileID,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromModels(rows), nil
+}
+
+// GetByStatus returns the oldest certifications first, so reviewers work through the
+// queue in the order it was filled.
+func (r *repository) GetByStatus(ctx context.Context, status string, limit int) ([]*Certification, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var rows []models.Certification
+	err := r.db.SelectContext(
+		ctx,
+		&rows,
+		`
+		SELECT * FROM certifications
+		WHERE status = $1
+		ORDER BY created_at, id
+		LIMIT $2
+		`,
+		status,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromModels(rows), nil
+}
+
+func (r *repository) Update(ctx context.Context, certification *Certification) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE certifications SET
+			institution = :institution,
+			course_name = :course_name,
+			start_date = :start_date,
+			end_date = :end_date,
+			document_object = :document_object,
+			document_content_type = :document_content_type,
+			status = :status,
+			reviewed_by = :reviewed_by,
+			reviewed_at = :reviewed_at,
+			review_note = :review_note,
+			updated_at = :updated_at
+		WHERE id = :id`
+
+	model := certification.ToModel()
+	_, err := r.db.NamedExecContext(ctx, query, &model)
 	return err
 }
+
+func (r *repository) Delete(ctx context.Context, ID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM certifications WHERE id = $1", ID)
+	return err
+}
+
+func fromModels(rows []models.Certification) []*Certification {
+	certifications := make([]*Certification, 0, len(rows))
+	for _, row := range rows {
+		certifications = append(certifications, NewFromModel(row))
+	}
+	return certifications
+}
@@ -0,0 +1,359 @@
+package certifications
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/modules/accounts/userprofiles"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/jwt"
+	"conecta-mare-server/pkg/storage"
+	"conecta-mare-server/pkg/uid"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+)
+
+const (
+	// maxDocumentSize bounds the proof documents, a scanned certificate fits easily.
+	maxDocumentSize = 10 << 20
+	// maxReviewQueueSize bounds how many certifications a reviewer gets at once.
+	maxReviewQueueSize = 100
+)
+
+func NewService(
+	repository CertificationsRepository,
+	userProfilesRepo userprofiles.UserProfilesRepository,
+	storageClient *storage.StorageClient,
+	logger *slog.Logger,
+) CertificationsService {
+	return &certificationsService{
+		repository:       repository,
+		userProfilesRepo: userProfilesRepo,
+		storageClient:    storageClient,
+		logger:           logger,
+	}
+}
+
+func (s *certificationsService) GetMyCertifications(ctx context.Context) ([]common.CertificationDetails, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to get own certifications")
+
+	_, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	certifications, err := s.repository.GetByUserProfileID(ctx, profileID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get certifications", "user_profile_id", profileID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	return toResponses(certifications), nil
+}
+
+// CreateCertification adds a certification waiting for review. The proof document is
+// sent afterwards through UploadDocument.
+func (s *certificationsService) CreateCertification(ctx context.Context, input common.SaveCertificationRequest) (*common.CertificationDetails, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to create certification")
+
+	_, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	certification, err := New(profileID, input.Institution, input.CourseName, input.StartDate, input.EndDate)
+	if err != nil {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+	}
+
+	if err := s.repository.Create(ctx, certification); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to create certification", "user_profile_id", profileID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "certification created with success", "certification_id", certification.ID())
+	return toResponse(certification), nil
+}
+
+func (s *certificationsService) UpdateCertification(ctx context.Context, ID string, input common.SaveCertificationRequest) (*common.CertificationDetails, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to update certification", "certification_id", ID)
+
+	_, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	certification, apiErr := s.getOwnedCertification(ctx, profileID, ID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	if err := certification.Update(input.Institution, input.CourseName, input.StartDate, input.EndDate); err != nil {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+	}
+
+	if err := s.repository.Update(ctx, certification); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to update certification", "certification_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "certification updated with success", "certification_id", ID)
+	return toResponse(certification), nil
+}
+
+func (s *certificationsService) DeleteCertification(ctx context.Context, ID string) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to delete certification", "certification_id", ID)
+
+	userID, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	if _, apiErr := s.getOwnedCertification(ctx, profileID, ID); apiErr != nil {
+		return apiErr
+	}
+
+	if err := s.repository.Delete(ctx, ID); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to delete certification", "certification_id", ID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	// The row is gone already, a leftover document is only logged.
+	prefix := documentPrefix(userID, ID)
+	if err := s.storageClient.RemoveByPrefix(ctx, prefix); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to remove certification documents", "prefix", prefix, "err", err)
+	}
+
+	s.logger.InfoContext(ctx, "certification deleted with success", "certification_id", ID)
+	return nil
+}
+
+// UploadDocument stores the proof in the private bucket, replacing any previous one,
+// and sends the certification back to review.
+func (s *certificationsService) UploadDocument(ctx context.Context, ID string, file *multipart.FileHeader) (*common.CertificationDetails, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to upload certification document", "certification_id", ID)
+
+	if file == nil || !storage.IsDocument(file) || file.Size > maxDocumentSize {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidCertificationDocument)
+	}
+
+	userID, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	certification, apiErr := s.getOwnedCertification(ctx, profileID, ID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	previous := certification.DocumentObject()
+
+	// A fresh name per upload keeps the previous document readable until the row
+	// points to the new one.
+	objectName := documentPrefix(userID, ID) + uid.New("certdoc")
+	if _, err := s.storageClient.UploadFile(objectName, file); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to upload certification document", "certification_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	certification.AttachDocument(objectName, file.Header.Get("Content-Type"))
+	if err := s.repository.Update(ctx, certification); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to update certification document", "certification_id", ID, "err", err)
+		s.removeObject(ctx, objectName)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	if previous != nil {
+		s.removeObject(ctx, *previous)
+	}
+
+	s.logger.InfoContext(ctx, "certification document uploaded with success", "certification_id", ID)
+	return toResponse(certification), nil
+}
+
+func (s *certificationsService) OpenMyDocument(ctx context.Context, ID string) (io.ReadCloser, string, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to open own certification document", "certification_id", ID)
+
+	_, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return nil, "", apiErr
+	}
+
+	certification, apiErr := s.getOwnedCertification(ctx, profileID, ID)
+	if apiErr != nil {
+		return nil, "", apiErr
+	}
+
+	return s.openDocument(ctx, certification)
+}
+
+func (s *certificationsService) ListForReview(ctx context.Context, status string) ([]common.CertificationDetails, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to list certifications for review", "status", status)
+
+	if status == "" {
+		status = StatusPending
+	}
+	if status != StatusPending && status != StatusVerified && status != StatusRejected {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidCertificationStatus)
+	}
+
+	certifications, err := s.repository.GetByStatus(ctx, status, maxReviewQueueSize)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to list certifications", "status", status, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	return toResponses(certifications), nil
+}
+
+func (s *certificationsService) OpenDocument(ctx context.Context, ID string) (io.ReadCloser, string, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to open certification document", "certification_id", ID)
+
+	certification, apiErr := s.getCertification(ctx, ID)
+	if apiErr != nil {
+		return nil, "", apiErr
+	}
+
+	return s.openDocument(ctx, certification)
+}
+
+// ReviewCertification records the decision of an admin. Only certifications with a
+// proof document can be verified, rejecting is always possible.
+func (s *certificationsService) ReviewCertification(ctx context.Context, ID string, input common.ReviewCertificationRequest) (*common.CertificationDetails, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to review certification", "certification_id", ID, "status", input.Status)
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	certification, apiErr := s.getCertification(ctx, ID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	if input.Status == StatusVerified && !certification.HasDocument() {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusConflict, exceptions.ErrCertificationDocumentNotFound)
+	}
+
+	if err := certification.Review(c.UserID, input.Status, input.Note); err != nil {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+	}
+
+	if err := s.repository.Update(ctx, certification); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to review certification", "certification_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "certification reviewed with success", "certification_id", ID, "status", input.Status, "reviewer_id", c.UserID)
+	return toResponse(certification), nil
+}
+
+// currentProfile returns the signed user and the profile that owns their certifications.
+func (s *certificationsService) currentProfile(ctx context.Context) (string, string, *exceptions.ApiError[string]) {
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return "", "", exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	userProfile, err := s.userProfilesRepo.FindByUserID(ctx, c.UserID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to find user profile", "user_id", c.UserID, "err", err)
+		return "", "", exceptions.MakeGenericApiError()
+	}
+	if userProfile == nil {
+		s.logger.WarnContext(ctx, "user profile not found", "user_id", c.UserID)
+		return "", "", exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrUserProfileNotFound)
+	}
+
+	return c.UserID, userProfile.ID(), nil
+}
+
+func (s *certificationsService) getCertification(ctx context.Context, ID string) (*Certification, *exceptions.ApiError[string]) {
+	certification, err := s.repository.GetByID(ctx, ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get certification", "certification_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if certification == nil {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrCertificationNotFound)
+	}
+
+	return certification, nil
+}
+
+// getOwnedCertification answers certifications of other profiles as not found, so
+// their ids cannot be probed.
+func (s *certificationsService) getOwnedCertification(ctx context.Context, profileID, ID string) (*Certification, *exceptions.ApiError[string]) {
+	certification, apiErr := s.getCertification(ctx, ID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if certification.UserProfileID() != profileID {
+		s.logger.WarnContext(ctx, "certification not found for profile", "certification_id", ID, "user_profile_id", profileID)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrCertificationNotFound)
+	}
+
+	return certification, nil
+}
+
+func (s *certificationsService) openDocument(ctx context.Context, certification *Certification) (io.ReadCloser, string, *exceptions.ApiError[string]) {
+	if !certification.HasDocument() {
+		return nil, "", exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrCertificationDocumentNotFound)
+	}
+
+	document, err := s.storageClient.GetObject(ctx, *certification.DocumentObject())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to open certification document", "certification_id", certification.ID(), "err", err)
+		return nil, "", exceptions.MakeGenericApiError()
+	}
+
+	contentType := "application/octet-stream"
+	if certification.DocumentContentType() != nil {
+		contentType = *certification.DocumentContentType()
+	}
+
+	return document, contentType, nil
+}
+
+// removeObject deletes a document that is no longer referenced. Failures are only
+// logged, a leftover file is never served since nothing points to it.
+func (s *certificationsService) removeObject(ctx context.Context, objectName string) {
+	if err := s.storageClient.RemoveObject(ctx, objectName); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to remove certification document", "object", objectName, "err", err)
+	}
+}
+
+// documentPrefix is where the proofs of a certification are kept, in the private bucket.
+func documentPrefix(userID, certificationID string) string {
+	return fmt.Sprintf("%s%s/", storage.CertificationPrefix(userID), certificationID)
+}
+
+func toResponse(certification *Certification) *common.CertificationDetails {
+	return &common.CertificationDetails{
+		ID:            certification.ID(),
+		UserProfileID: certification.UserProfileID(),
+		Institution:   certification.Institution(),
+		CourseName:    certification.CourseName(),
+		StartDate:     certification.StartDate(),
+		EndDate:       certification.EndDate(),
+		Status:        certification.Status(),
+		HasDocument:   certification.HasDocument(),
+		ReviewedAt:    certification.ReviewedAt(),
+		ReviewNote:    certification.ReviewNote(),
+		CreatedAt:     certification.CreatedAt(),
+	}
+}
+
+func toResponses(certifications []*Certification) []common.CertificationDetails {
+	responses := make([]common.CertificationDetails, 0, len(certifications))
+	for _, certification := range certifications {
+		responses = append(responses, *toResponse(certification))
+	}
+	return responses
+}
@@ -0,0 +1,155 @@
+package dataexports
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/security"
+	"conecta-mare-server/pkg/uid"
+	"fmt"
+	"time"
+)
+
+const (
+	StatusPending    = "pending"
+	StatusProcessing = "processing"
+	StatusReady      = "ready"
+	StatusFailed     = "failed"
+	StatusExpired    = "expired"
+
+	// retention is how long a finished archive is kept before it is removed.
+	retention = 7 * 24 * time.Hour
+	// downloadLinkTTL bounds how long a download link keeps working.
+	downloadLinkTTL = time.Hour
+	// claimTimeout is how long an export may stay in processing before another
+	// worker takes it over, so a crash mid-build does not leave it stuck.
+	claimTimeout = 30 * time.Minute
+)
+
+type DataExport struct {
+	id                string
+	userID            string
+	status            string
+	objectName        *string
+	downloadTokenHash *string
+	downloadExpiresAt *time.Time
+	failureReason     *string
+	createdAt         time.Time
+	claimedAt         *time.Time
+	completedAt       *time.Time
+	expiresAt         *time.Time
+}
+
+func New(userID string) (*DataExport, error) {
+	export := DataExport{
+		id:        uid.New("dataexport"),
+		userID:    userID,
+		status:    StatusPending,
+		createdAt: time.Now(),
+	}
+
+	if err := export.validate(); err != nil {
+		return nil, exceptions.MakeApiError(err)
+	}
+
+	return &export, nil
+}
+
+func NewFromModel(m models.DataExport) *DataExport {
+	return &DataExport{
+		id:                m.ID,
+		userID:            m.UserID,
+		status:            m.Status,
+		objectName:        m.ObjectName,
+		downloadTokenHash: m.DownloadTokenHash,
+		downloadExpiresAt: m.DownloadExpiresAt,
+		failureReason:     m.FailureReason,
+		createdAt:         m.CreatedAt,
+		claimedAt:         m.ClaimedAt,
+		completedAt:       m.CompletedAt,
+		expiresAt:         m.ExpiresAt,
+	}
+}
+
+func (e *DataExport) ToModel() models.DataExport {
+	return models.DataExport{
+		ID:                e.id,
+		UserID:            e.userID,
+		Status:            e.status,
+		ObjectName:        e.objectName,
+		DownloadTokenHash: e.downloadTokenHash,
+		DownloadExpiresAt: e.downloadExpiresAt,
+		FailureReason:     e.failureReason,
+		CreatedAt:         e.createdAt,
+		ClaimedAt:         e.claimedAt,
+		CompletedAt:       e.completedAt,
+		ExpiresAt:         e.expiresAt,
+	}
+}
+
+func (e *DataExport) validate() error {
+	if e.userID == "" {
+		return fmt.Errorf("user_id is required")
+	}
+	return nil
+}
+
+func (e *DataExport) MarkReady(objectName string) {
+	now := time.Now()
+	expiresAt := now.Add(retention)
+	e.status = StatusReady
+	e.objectName = &objectName
+	e.completedAt = &now
+	e.expiresAt = &expiresAt
+}
+
+func (e *DataExport) MarkFailed(reason string) {
+	now := time.Now()
+	e.status = StatusFailed
+	e.failureReason = &reason
+	e.completedAt = &now
+}
+
+func (e *DataExport) MarkExpired() {
+	e.status = StatusExpired
+	e.objectName = nil
+	e.downloadTokenHash = nil
+	e.downloadExpiresAt = nil
+}
+
+// IssueDownloadToken replaces any previous link and returns the plain token,
+// which is only ever handed to the user.
+func (e *DataExport) IssueDownloadToken() (string, error) {
+	plainToken, err := security.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+
+	tokenHash := security.HashToken(plainToken)
+	expiresAt := time.Now().Add(downloadLinkTTL)
+	e.downloadTokenHash = &tokenHash
+	e.downloadExpiresAt = &expiresAt
+
+	return plainToken, nil
+}
+
+func (e *DataExport) CanDownload(plainToken string) bool {
+	if e.status != StatusReady || e.downloadTokenHash == nil || e.downloadExpiresAt == nil {
+		return false
+	}
+	return *e.downloadTokenHash == security.HashToken(plainToken) && time.Now().Before(*e.downloadExpiresAt)
+}
+
+func (e *DataExport) IsInProgress() bool {
+	return e.status == StatusPending || e.status == StatusProcessing
+}
+
+func (e *DataExport) ID() string                    { return e.id }
+func (e *DataExport) UserID() string                { return e.userID }
+func (e *DataExport) Status() string                { return e.status }
+func (e *DataExport) ObjectName() *string           { return e.objectName }
+func (e *DataExport) DownloadExpiresAt() *time.Time { return e.downloadExpiresAt }
+func (e *DataExport) FailureReason() *string        { return e.failureReason }
+func (e *DataExport) CreatedAt() time.Time          { return e.createdAt }
+func (e *DataExport) ClaimedAt() *time.Time         { return e.claimedAt }
+func (e *DataExport) CompletedAt() *time.Time       { return e.completedAt }
+func (e *DataExport) ExpiresAt() *time.Time         { return e.expiresAt }
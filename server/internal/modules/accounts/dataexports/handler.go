@@ -0,0 +1,94 @@
+package dataexports
+
+import (
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/httphelpers"
+	"conecta-mare-server/pkg/valueobjects"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+var (
+	instance *dataExportsHandler
+	Once     sync.Once
+)
+
+func NewHandler(dataExportsService DataExportsService, authMiddleware *middlewares.AuthMiddleware) *dataExportsHandler {
+	Once.Do(
+		func() {
+			instance = &dataExportsHandler{
+				dataExportsService: dataExportsService,
+				authMiddleware:     authMiddleware,
+			}
+		},
+	)
+
+	return instance
+}
+
+func (h dataExportsHandler) RegisterRoutes(r *chi.Mux) {
+	m := h.authMiddleware
+	r.Route(
+		"/api/v1/data-exports", func(r chi.Router) {
+			// Public, the signed token in the link authorizes the download
+			r.Get("/{export_id}/download", h.handleDownload)
+
+			// Private
+			r.Group(func(r chi.Router) {
+				r.Use(m.WithAuth, m.RequireRole(valueobjects.AnyRole...))
+				r.Post("/", h.handleRequestExport)
+				r.Get("/{export_id}", h.handleGetExport)
+			})
+		},
+	)
+}
+
+func (h dataExportsHandler) handleRequestExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	export, err := h.dataExportsService.RequestExport(ctx)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusAccepted, export)
+}
+
+func (h dataExportsHandler) handleGetExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	exportID := chi.URLParam(r, "export_id")
+
+	export, err := h.dataExportsService.GetExport(ctx, exportID)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, export)
+}
+
+func (h dataExportsHandler) handleDownload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	exportID := chi.URLParam(r, "export_id")
+	token := r.URL.Query().Get("token")
+
+	archive, err := h.dataExportsService.OpenDownload(ctx, exportID, token)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+	defer archive.Close()
+
+	filename := fmt.Sprintf("conecta-mare-dados-%s.zip", time.Now().Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, archive)
+}
@@ -0,0 +1,47 @@
+package dataexports
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/modules/accounts/metrics"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/storage"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"time"
+)
+
+type (
+	DataExportsRepository interface {
+		Create(ctx context.Context, export *DataExport) error
+		Update(ctx context.Context, export *DataExport) error
+		GetByID(ctx context.Context, ID string) (*DataExport, error)
+		GetLatestByUserID(ctx context.Context, userID string) (*DataExport, error)
+		ClaimNextPending(ctx context.Context, staleBefore time.Time) (*DataExport, error)
+		GetExpired(ctx context.Context, now time.Time, limit int) ([]*DataExport, error)
+		GetPersonalData(ctx context.Context, userID string) (json.RawMessage, error)
+	}
+	DataExportsService interface {
+		RequestExport(ctx context.Context) (*common.DataExport, *exceptions.ApiError[string])
+		GetExport(ctx context.Context, ID string) (*common.DataExport, *exceptions.ApiError[string])
+		OpenDownload(ctx context.Context, ID, token string) (io.ReadCloser, *exceptions.ApiError[string])
+		ProcessPending(ctx context.Context)
+		RemoveExpired(ctx context.Context)
+	}
+	dataExportsService struct {
+		repository    DataExportsRepository
+		metricsRepo   metrics.MetricsRepository
+		storageClient *storage.StorageClient
+		// privateStorage is the bucket without public access. It holds the archives, so
+		// OpenDownload is the only way to read them, and the certification proofs.
+		privateStorage *storage.StorageClient
+		appURL         string
+		logger         *slog.Logger
+	}
+	dataExportsHandler struct {
+		dataExportsService DataExportsService
+		authMiddleware     *middlewares.AuthMiddleware
+	}
+)
@@ -0,0 +1,288 @@
+package dataexports
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) DataExportsRepository {
+	return &repository{db}
+}
+
+func (r *repository) Create(ctx context.Context, export *DataExport) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	model := export.ToModel()
+	query := `
+		INSERT INTO data_exports (
+				id, user_id, status, object_name, download_token_hash, download_expires_at,
+				failure_reason, created_at, completed_at, expires_at
+			) VALUES (
+				:id, :user_id, :status, :object_name, :download_token_hash, :download_expires_at,
+				:failure_reason, :created_at, :completed_at, :expires_at
+		)`
+
+	_, err := r.db.NamedExecContext(ctx, query, model)
+	return err
+}
+
+func (r *repository) Update(ctx context.Context, export *DataExport) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	model := export.ToModel()
+	query := `
+		UPDATE data_exports SET
+			status = :status,
+			object_name = :object_name,
+			download_token_hash = :download_token_hash,
+			download_expires_at = :download_expires_at,
+			failure_reason = :failure_reason,
+			completed_at = :completed_at,
+			expires_at = :expires_at
+		WHERE id = :id`
+
+	_, err := r.db.NamedExecContext(ctx, query, model)
+	return err
+}
+
+func (r *repository) GetByID(ctx context.Context, ID string) (*DataExport, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var model models.DataExport
+	err := r.db.GetContext(ctx, &model, "SELECT * FROM data_exports WHERE id = $1", ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFromModel(model), nil
+}
+
+func (r *repository) GetLatestByUserID(ctx context.Context, userID string) (*DataExport, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var model models.DataExport
+	err := r.db.GetContext(
+		ctx,
+		&model,
+		"SELECT * FROM data_exports WHERE user_id = $1 ORDER BY created_at DESC LIMIT 1",
+		userID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFromModel(model), nil
+}
+
+// ClaimNextPending moves the oldest pending export to processing and returns it.
+// Exports claimed before staleBefore are taken again, as their worker is gone.
+// SKIP LOCKED lets several instances run the worker without taking the same export.
+func (r *repository) ClaimNextPending(ctx context.Context, staleBefore time.Time) (*DataExport, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var model models.DataExport
+	err := r.db.GetContext(
+		ctx,
+		&model,
+		`
+		UPDATE data_exports SET status = 'processing', claimed_at = NOW()
+		WHERE id = (
+			SELECT id
+			FROM data_exports
+			WHERE status = 'pending'
+				OR (status = 'processing' AND (claimed_at IS NULL OR claimed_at < $1))
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+		`,
+		staleBefore,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFromModel(model), nil
+}
+
+func (r *repository) GetExpired(ctx context.Context, now time.Time, limit int) ([]*DataExport, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var rows []models.DataExport
+	err := r.db.SelectContext(
+		ctx,
+		&rows,
+		"SELECT * FROM data_exports WHERE status = 'ready' AND expires_at < $1 ORDER BY expires_at LIMIT $2",
+		now,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]*DataExport, 0, len(rows))
+	for _, row := range rows {
+		exports = append(exports, NewFromModel(row))
+	}
+
+	return exports, nil
+}
+
+// GetPersonalData gathers everything stored in Postgres about the user as a single
+// JSON document. Secrets such as password hashes and token hashes are left out.
+func (r *repository) GetPersonalData(ctx context.Context, userID string) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var data json.RawMessage
+	err := r.db.GetContext(
+		ctx,
+		&data,
+		`
+		SELECT json_build_object(
+			'user', (
+				SELECT row_to_json(u)
+				FROM (
+					SELECT id, email, phone, role, email_verified_at, phone_verified_at, created_at, updated_at, deleted_at
+					FROM users
+					WHERE id = $1
+				) u
+			),
+			'profile', (
+				SELECT row_to_json(up)
+				FROM (
+					SELECT id, full_name, subcategory_id, profile_image, job_description,
+						phone, social_links, created_at, updated_at
+					FROM user_profiles
+					WHERE user_id = $1
+				) up
+			),
+			'locations', (
+				SELECT COALESCE(json_agg(l), '[]'::JSON)
+				FROM (
+					SELECT l.id, l.street, l."number", l.complement, cm.name AS community,
+						l.created_at, l.updated_at
+					FROM locations l
+					INNER JOIN user_profiles up ON up.id = l.user_profile_id
+					LEFT JOIN communities cm ON cm.id = l.community_id
+					WHERE up.user_id = $1
+				) l
+			),
+			'service_areas', (
+				SELECT COALESCE(json_agg(sa), '[]'::JSON)
+				FROM (
+					SELECT sa.id, cm.name AS community, sa.travel_surcharge, sa.created_at
+					FROM service_areas sa
+					INNER JOIN user_profiles up ON up.id = sa.user_profile_id
+					LEFT JOIN communities cm ON cm.id = sa.community_id
+					WHERE up.user_id = $1
+				) sa
+			),
+			'certifications', (
+				SELECT COALESCE(json_agg(ce), '[]'::JSON)
+				FROM (
+					SELECT ce.id, ce.institution, ce.course_name, ce.start_date, ce.end_date, ce.status,
+						ce.reviewed_at, ce.review_note, ce.document_object IS NOT NULL AS has_document, ce.created_at, ce.updated_at
+					FROM certifications ce
+					INNER JOIN user_profiles up ON up.id = ce.user_profile_id
+					WHERE up.user_id = $1
+				) ce
+			),
+			'projects', (
+				SELECT COALESCE(json_agg(p), '[]'::JSON)
+				FROM (
+					SELECT p.id, p.name, p.description, p.completed_at, p.tags, p.service_id, p.created_at, p.updated_at,
+						(
+							SELECT COALESCE(json_agg(json_build_object('id', pi.id, 'url', pi.url, 'ordering', pi.ordering)), '[]'::JSON)
+							FROM project_images pi
+							WHERE pi.project_id = p.id
+						) AS images
+					FROM projects p
+					INNER JOIN user_profiles up ON up.id = p.user_profile_id
+					WHERE up.user_id = $1
+				) p
+			),
+			'services', (
+				SELECT COALESCE(json_agg(se), '[]'::JSON)
+				FROM (
+					SELECT se.id, se.name, se.description, se.price, se.own_location_price, se.ordering,
+						se.created_at, se.updated_at, se.deleted_at,
+						(
+							SELECT COALESCE(json_agg(json_build_object('id', sei.id, 'url', sei.url, 'ordering', sei.ordering)), '[]'::JSON)
+							FROM service_images sei
+							WHERE sei.service_id = se.id
+						) AS images
+					FROM services se
+					INNER JOIN user_profiles up ON up.id = se.user_profile_id
+					WHERE up.user_id = $1
+				) se
+			),
+			'sessions', (
+				SELECT COALESCE(json_agg(s), '[]'::JSON)
+				FROM (
+					SELECT id, user_agent, ip_address, active, created_at, last_seen_at, expires_at
+					FROM sessions
+					WHERE user_id = $1
+					ORDER BY created_at DESC
+				) s
+			),
+			'linked_accounts', (
+				SELECT COALESCE(json_agg(li), '[]'::JSON)
+				FROM (
+					SELECT issuer, email, created_at, last_login_at
+					FROM user_identities
+					WHERE user_id = $1
+				) li
+			),
+			'reviews_written', (
+				SELECT COALESCE(json_agg(rw), '[]'::JSON)
+				FROM (
+					SELECT id, user_id AS professional_id, rating, comment, created_at
+					FROM reviews
+					WHERE client_user_id = $1
+				) rw
+			),
+			'reviews_received', (
+				SELECT COALESCE(json_agg(rr), '[]'::JSON)
+				FROM (
+					SELECT id, rating, comment, created_at
+					FROM reviews
+					WHERE user_id = $1
+				) rr
+			)
+		)
+		`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,317 @@
+package dataexports
+
+import (
+	"archive/zip"
+	"bytes"
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/modules/accounts/metrics"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/jwt"
+	"conecta-mare-server/pkg/storage"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// maxExportsPerRun keeps a single worker run short when many exports are queued.
+const maxExportsPerRun = 5
+
+func NewService(
+	repository DataExportsRepository,
+	metricsRepo metrics.MetricsRepository,
+	storageClient *storage.StorageClient,
+	privateStorage *storage.StorageClient,
+	appURL string,
+	logger *slog.Logger,
+) DataExportsService {
+	return &dataExportsService{
+		repository:     repository,
+		metricsRepo:    metricsRepo,
+		storageClient:  storageClient,
+		privateStorage: privateStorage,
+		appURL:         appURL,
+		logger:         logger,
+	}
+}
+
+func (s *dataExportsService) RequestExport(ctx context.Context) (*common.DataExport, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to request personal data export")
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	latest, err := s.repository.GetLatestByUserID(ctx, c.UserID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get latest data export", "user_id", c.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if latest != nil && latest.IsInProgress() {
+		s.logger.InfoContext(ctx, "data export already in progress", "user_id", c.UserID, "export_id", latest.ID())
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusConflict, exceptions.ErrDataExportInProgress)
+	}
+
+	export, err := New(c.UserID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to process data export entity", "user_id", c.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	if err := s.repository.Create(ctx, export); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to create data export", "user_id", c.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "data export requested", "user_id", c.UserID, "export_id", export.ID())
+	return toResponse(export, nil), nil
+}
+
+// GetExport reports the export status. Once it is ready, every call issues a fresh
+// download link that works for a short time.
+func (s *dataExportsService) GetExport(ctx context.Context, ID string) (*common.DataExport, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to get data export", "export_id", ID)
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	export, err := s.repository.GetByID(ctx, ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get data export", "export_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if export == nil || export.UserID() != c.UserID {
+		s.logger.WarnContext(ctx, "data export not found for user", "user_id", c.UserID, "export_id", ID)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrDataExportNotFound)
+	}
+
+	if export.Status() != StatusReady {
+		return toResponse(export, nil), nil
+	}
+
+	plainToken, err := export.IssueDownloadToken()
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to issue download token", "export_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	if err := s.repository.Update(ctx, export); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to store download token", "export_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	downloadURL := fmt.Sprintf(
+		"%s/api/v1/data-exports/%s/download?token=%s",
+		strings.TrimRight(s.appURL, "/"),
+		url.PathEscape(export.ID()),
+		url.QueryEscape(plainToken),
+	)
+
+	return toResponse(export, &downloadURL), nil
+}
+
+func (s *dataExportsService) OpenDownload(ctx context.Context, ID, token string) (io.ReadCloser, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to download data export", "export_id", ID)
+
+	export, err := s.repository.GetByID(ctx, ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get data export", "export_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if export == nil || !export.CanDownload(token) {
+		s.logger.WarnContext(ctx, "invalid or expired download link", "export_id", ID)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrInvalidDownloadLink)
+	}
+
+	archive, err := s.privateStorage.GetObject(ctx, *export.ObjectName())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to open data export archive", "export_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	return archive, nil
+}
+
+// ProcessPending builds the archives of queued exports. It is run by a background job.
+func (s *dataExportsService) ProcessPending(ctx context.Context) {
+	for range maxExportsPerRun {
+		export, err := s.repository.ClaimNextPending(ctx, time.Now().Add(-claimTimeout))
+		if err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to claim pending data export", "err", err)
+			return
+		}
+		if export == nil {
+			return
+		}
+
+		s.logger.InfoContext(ctx, "building data export", "user_id", export.UserID(), "export_id", export.ID())
+
+		objectName, err := s.buildArchive(ctx, export)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to build data export", "export_id", export.ID(), "err", err)
+			export.MarkFailed(err.Error())
+		} else {
+			export.MarkReady(objectName)
+		}
+
+		if err := s.repository.Update(ctx, export); err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to update data export", "export_id", export.ID(), "err", err)
+			continue
+		}
+
+		s.logger.InfoContext(ctx, "data export finished", "export_id", export.ID(), "status", export.Status())
+	}
+}
+
+// RemoveExpired deletes archives past their retention. It is run by a background job.
+func (s *dataExportsService) RemoveExpired(ctx context.Context) {
+	exports, err := s.repository.GetExpired(ctx, time.Now(), 50)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get expired data exports", "err", err)
+		return
+	}
+
+	for _, export := range exports {
+		if objectName := export.ObjectName(); objectName != nil {
+			if err := s.privateStorage.RemoveObject(ctx, *objectName); err != nil {
+				s.logger.ErrorContext(ctx, "error while attempting to remove data export archive", "export_id", export.ID(), "err", err)
+				continue
+			}
+		}
+
+		export.MarkExpired()
+		if err := s.repository.Update(ctx, export); err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to expire data export", "export_id", export.ID(), "err", err)
+		}
+	}
+}
+
+// buildArchive writes the user's data as JSON, next to the files they uploaded, into
+// a zip stored under exports/<user>/ in the private bucket.
+func (s *dataExportsService) buildArchive(ctx context.Context, export *DataExport) (string, error) {
+	personalData, err := s.repository.GetPersonalData(ctx, export.UserID())
+	if err != nil {
+		return "", fmt.Errorf("failed to read personal data: %w", err)
+	}
+
+	profileVisits, err := s.metricsRepo.DailyProfileVisits(ctx, export.UserID())
+	if err != nil {
+		return "", fmt.Errorf("failed to read profile visits: %w", err)
+	}
+
+	tmp, err := os.CreateTemp("", "dataexport-*.zip")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary archive: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	zw := zip.NewWriter(tmp)
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, personalData, "", "  "); err != nil {
+		return "", fmt.Errorf("failed to format personal data: %w", err)
+	}
+	if err := writeZipEntry(zw, "data.json", &indented); err != nil {
+		return "", err
+	}
+
+	visitsJSON, err := json.MarshalIndent(profileVisits, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to format profile visits: %w", err)
+	}
+	if err := writeZipEntry(zw, "profile_visits.json", bytes.NewReader(visitsJSON)); err != nil {
+		return "", err
+	}
+
+	if err := copyObjects(ctx, zw, s.storageClient, storage.UserObjectPrefixes(export.UserID())); err != nil {
+		return "", err
+	}
+	if err := copyObjects(ctx, zw, s.privateStorage, storage.PrivateUserObjectPrefixes(export.UserID())); err != nil {
+		return "", err
+	}
+
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("failed to finish archive: %w", err)
+	}
+
+	info, err := tmp.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to read archive size: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind archive: %w", err)
+	}
+
+	objectName := fmt.Sprintf("%s%s.zip", storage.ExportPrefix(export.UserID()), export.ID())
+	if err := s.privateStorage.PutObject(ctx, objectName, tmp, info.Size(), "application/zip"); err != nil {
+		return "", err
+	}
+
+	return objectName, nil
+}
+
+// copyObjects adds every object under the given prefixes of a bucket to the archive.
+func copyObjects(ctx context.Context, zw *zip.Writer, storageClient *storage.StorageClient, prefixes []string) error {
+	for _, prefix := range prefixes {
+		objectNames, err := storageClient.ListObjectNames(ctx, prefix)
+		if err != nil {
+			return err
+		}
+		for _, objectName := range objectNames {
+			if err := copyObject(ctx, zw, storageClient, objectName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func copyObject(ctx context.Context, zw *zip.Writer, storageClient *storage.StorageClient, objectName string) error {
+	object, err := storageClient.GetObject(ctx, objectName)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+
+	return writeZipEntry(zw, path.Join("images", objectName), object)
+}
+
+func writeZipEntry(zw *zip.Writer, name string, content io.Reader) error {
+	entry, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	if _, err := io.Copy(entry, content); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	return nil
+}
+
+func toResponse(export *DataExport, downloadURL *string) *common.DataExport {
+	response := &common.DataExport{
+		ID:          export.ID(),
+		Status:      export.Status(),
+		CreatedAt:   export.CreatedAt(),
+		CompletedAt: export.CompletedAt(),
+		ExpiresAt:   export.ExpiresAt(),
+	}
+	if downloadURL != nil {
+		response.DownloadURL = downloadURL
+		response.DownloadExpiresAt = export.DownloadExpiresAt()
+	}
+	return response
+}
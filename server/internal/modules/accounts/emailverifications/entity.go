@@ -0,0 +1,85 @@
+package emailverifications
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/security"
+	"conecta-mare-server/pkg/uid"
+	"fmt"
+	"time"
+)
+
+const (
+	ttl = 48 * time.Hour
+)
+
+type EmailVerificationToken struct {
+	id        string
+	userID    string
+	tokenHash string
+	expiresAt time.Time
+	usedAt    *time.Time
+	createdAt time.Time
+}
+
+// New creates a verification token for the user and returns it together with the plain
+// token, which must only be sent to the user and is never stored.
+func New(userID string) (*EmailVerificationToken, string, error) {
+	plainToken, err := security.GenerateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	verificationToken := EmailVerificationToken{
+		id:        uid.New("emailverif"),
+		userID:    userID,
+		tokenHash: security.HashToken(plainToken),
+		expiresAt: time.Now().Add(ttl),
+		usedAt:    nil,
+		createdAt: time.Now(),
+	}
+
+	if err := verificationToken.validate(); err != nil {
+		return nil, "", exceptions.MakeApiError(err)
+	}
+
+	return &verificationToken, plainToken, nil
+}
+
+func NewFromModel(m models.EmailVerificationToken) *EmailVerificationToken {
+	return &EmailVerificationToken{
+		id:        m.ID,
+		userID:    m.UserID,
+		tokenHash: m.TokenHash,
+		expiresAt: m.ExpiresAt,
+		usedAt:    m.UsedAt,
+		createdAt: m.CreatedAt,
+	}
+}
+
+func (t *EmailVerificationToken) ToModel() models.EmailVerificationToken {
+	return models.EmailVerificationToken{
+		ID:        t.id,
+		UserID:    t.userID,
+		TokenHash: t.tokenHash,
+		ExpiresAt: t.expiresAt,
+		UsedAt:    t.usedAt,
+		CreatedAt: t.createdAt,
+	}
+}
+
+func (t *EmailVerificationToken) validate() error {
+	if t.userID == "" {
+		return fmt.Errorf("user_id is required")
+	}
+	return nil
+}
+
+func (t *EmailVerificationToken) IsUsable() bool {
+	return t.usedAt == nil && time.Now().Before(t.expiresAt)
+}
+
+func (t *EmailVerificationToken) ID() string           { return t.id }
+func (t *EmailVerificationToken) UserID() string       { return t.userID }
+func (t *EmailVerificationToken) ExpiresAt() time.Time { return t.expiresAt }
+func (t *EmailVerificationToken) CreatedAt() time.Time { return t.createdAt }
@@ -0,0 +1,16 @@
+package emailverifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type EmailVerificationsRepository interface {
+	Create(ctx context.Context, verificationToken *EmailVerificationToken) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*EmailVerificationToken, error)
+	GetLatestByUserID(ctx context.Context, userID string) (*EmailVerificationToken, error)
+	CountCreatedSince(ctx context.Context, userID string, since time.Time) (int, error)
+	MarkUsedTx(ctx context.Context, tx *sqlx.Tx, ID string) (bool, error)
+}
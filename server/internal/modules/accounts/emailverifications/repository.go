@@ -0,0 +1,113 @@
+package emailverifications
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) EmailVerificationsRepository {
+	return &repository{db}
+}
+
+func (r *repository) Create(ctx context.Context, verificationToken *EmailVerificationToken) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	model := verificationToken.ToModel()
+	query := `
+		INSERT INTO email_verification_tokens (
+				id, user_id, token_hash, expires_at, used_at, created_at
+			) VALUES (
+				:id, :user_id, :token_hash, :expires_at, :used_at, :created_at
+		)`
+
+	_, err := r.db.NamedExecContext(ctx, query, model)
+	return err
+}
+
+func (r *repository) GetByTokenHash(ctx context.Context, tokenHash string) (*EmailVerificationToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var model models.EmailVerificationToken
+	err := r.db.GetContext(ctx, &model, "SELECT * FROM email_verification_tokens WHERE token_hash = $1", tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFromModel(model), nil
+}
+
+func (r *repository) GetLatestByUserID(ctx context.Context, userID string) (*EmailVerificationToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var model models.EmailVerificationToken
+	err := r.db.GetContext(
+		ctx,
+		&model,
+		"SELECT * FROM email_verification_tokens WHERE user_id = $1 ORDER BY created_at DESC LIMIT 1",
+		userID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFromModel(model), nil
+}
+
+func (r *repository) CountCreatedSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var count int
+	err := r.db.GetContext(
+		ctx,
+		&count,
+		"SELECT count(*) FROM email_verification_tokens WHERE user_id = $1 AND created_at >= $2",
+		userID,
+		since,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *repository) MarkUsedTx(ctx context.Context, tx *sqlx.Tx, ID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	result, err := tx.ExecContext(
+		ctx,
+		"UPDATE email_verification_tokens SET used_at = $1 WHERE id = $2 AND used_at IS NULL",
+		time.Now(),
+		ID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
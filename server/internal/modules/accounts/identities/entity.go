@@ -0,0 +1,80 @@
+package identities
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/uid"
+	"fmt"
+	"time"
+)
+
+// UserIdentity links a user to an account at an external OpenID provider.
+type UserIdentity struct {
+	id          string
+	userID      string
+	issuer      string
+	subject     string
+	email       *string
+	createdAt   time.Time
+	lastLoginAt *time.Time
+}
+
+func New(userID, issuer, subject, email string) (*UserIdentity, error) {
+	now := time.Now()
+	identity := UserIdentity{
+		id:          uid.New("identity"),
+		userID:      userID,
+		issuer:      issuer,
+		subject:     subject,
+		createdAt:   now,
+		lastLoginAt: &now,
+	}
+	if email != "" {
+		identity.email = &email
+	}
+
+	if err := identity.validate(); err != nil {
+		return nil, exceptions.MakeApiError(err)
+	}
+
+	return &identity, nil
+}
+
+func NewFromModel(m models.UserIdentity) *UserIdentity {
+	return &UserIdentity{
+		id:          m.ID,
+		userID:      m.UserID,
+		issuer:      m.Issuer,
+		subject:     m.Subject,
+		email:       m.Email,
+		createdAt:   m.CreatedAt,
+		lastLoginAt: m.LastLoginAt,
+	}
+}
+
+func (i *UserIdentity) ToModel() models.UserIdentity {
+	return models.UserIdentity{
+		ID:          i.id,
+		UserID:      i.userID,
+		Issuer:      i.issuer,
+		Subject:     i.subject,
+		Email:       i.email,
+		CreatedAt:   i.createdAt,
+		LastLoginAt: i.lastLoginAt,
+	}
+}
+
+func (i *UserIdentity) validate() error {
+	if i.userID == "" {
+		return fmt.Errorf("user_id is required")
+	}
+	if i.issuer == "" || i.subject == "" {
+		return fmt.Errorf("issuer and subject are required")
+	}
+	return nil
+}
+
+func (i *UserIdentity) ID() string      { return i.id }
+func (i *UserIdentity) UserID() string  { return i.userID }
+func (i *UserIdentity) Issuer() string  { return i.issuer }
+func (i *UserIdentity) Subject() string { return i.subject }
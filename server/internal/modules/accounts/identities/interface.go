@@ -0,0 +1,13 @@
+package identities
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type IdentitiesRepository interface {
+	CreateTx(ctx context.Context, tx *sqlx.Tx, identity *UserIdentity) error
+	GetByIssuerAndSubject(ctx context.Context, issuer, subject string) (*UserIdentity, error)
+	TouchLastLogin(ctx context.Context, ID string) error
+}
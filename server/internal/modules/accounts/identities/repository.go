@@ -0,0 +1,65 @@
+package identities
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) IdentitiesRepository {
+	return &repository{db}
+}
+
+func (r *repository) CreateTx(ctx context.Context, tx *sqlx.Tx, identity *UserIdentity) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	model := identity.ToModel()
+	query := `
+		INSERT INTO user_identities (
+				id, user_id, issuer, subject, email, created_at, last_login_at
+			) VALUES (
+				:id, :user_id, :issuer, :subject, :email, :created_at, :last_login_at
+		)`
+
+	_, err := tx.NamedExecContext(ctx, query, model)
+	return err
+}
+
+func (r *repository) GetByIssuerAndSubject(ctx context.Context, issuer, subject string) (*UserIdentity, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var model models.UserIdentity
+	err := r.db.GetContext(
+		ctx,
+		&model,
+		"SELECT * FROM user_identities WHERE issuer = $1 AND subject = $2",
+		issuer,
+		subject,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFromModel(model), nil
+}
+
+func (r *repository) TouchLastLogin(ctx context.Context, ID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "UPDATE user_identities SET last_login_at = $1 WHERE id = $2", time.Now(), ID)
+	return err
+}
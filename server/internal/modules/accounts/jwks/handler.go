@@ -0,0 +1,37 @@
+package jwks
+
+import (
+	"conecta-mare-server/pkg/httphelpers"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+var (
+	instance *jwksHandler
+	Once     sync.Once
+)
+
+func NewHandler(keyPublisher KeyPublisher) *jwksHandler {
+	Once.Do(
+		func() {
+			instance = &jwksHandler{
+				keyPublisher: keyPublisher,
+			}
+		},
+	)
+
+	return instance
+}
+
+func (h jwksHandler) RegisterRoutes(r *chi.Mux) {
+	// Public
+	r.Get("/.well-known/jwks.json", h.handleGetJWKS)
+}
+
+func (h jwksHandler) handleGetJWKS(w http.ResponseWriter, r *http.Request) {
+	// Short enough for verifiers to pick up a newly added key before it starts signing.
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	httphelpers.WriteJSON(w, http.StatusOK, h.keyPublisher.JWKS())
+}
@@ -0,0 +1,12 @@
+package jwks
+
+import "conecta-mare-server/pkg/jwt"
+
+type (
+	KeyPublisher interface {
+		JWKS() jwt.JWKS
+	}
+	jwksHandler struct {
+		keyPublisher KeyPublisher
+	}
+)
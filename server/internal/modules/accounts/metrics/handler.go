@@ -5,6 +5,7 @@ import (
 	"conecta-mare-server/pkg/exceptions"
 	"conecta-mare-server/pkg/httphelpers"
 	"conecta-mare-server/pkg/jwt"
+	"conecta-mare-server/pkg/valueobjects"
 	"fmt"
 	"net/http"
 	"sync"
@@ -17,12 +18,12 @@ var (
 	Once     sync.Once
 )
 
-func NewHandler(metricsService MetricsService, accessKey string) *metricsHandler {
+func NewHandler(metricsService MetricsService, authMiddleware *middlewares.AuthMiddleware) *metricsHandler {
 	Once.Do(
 		func() {
 			instance = &metricsHandler{
 				metricsService: metricsService,
-				accessKey:      accessKey,
+				authMiddleware: authMiddleware,
 			}
 		},
 	)
@@ -31,11 +32,11 @@ func NewHandler(metricsService MetricsService, accessKey string) *metricsHandler
 }
 
 func (h metricsHandler) RegisterRoutes(r *chi.Mux) {
-	m := middlewares.NewWithAuth(h.accessKey)
+	m := h.authMiddleware
 	r.Route(
 		"/api/v1/metrics/user-profile-views", func(r chi.Router) {
 			// Private
-			r.With(m.WithAuth).Get("/", h.handleGetUserProfileViews)
+			r.With(m.WithAuth, m.RequireRole(valueobjects.Professional)).Get("/", h.handleGetUserProfileViews)
 		},
 	)
 }
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/server/middlewares"
 	"context"
 	"log/slog"
 	"time"
@@ -12,6 +14,7 @@ type (
 	MetricsRepository interface {
 		UserProfileViews(ctx context.Context, userID string, startDate, endDate time.Time) (any, error)
 		UserTopPerformingServices(ctx context.Context, userID string) (any, error)
+		DailyProfileVisits(ctx context.Context, userID string) ([]common.DailyVisit, error)
 		UserProfileViewsComparisonBySubcategory(ctx context.Context, userID, subcategoryID string, startDate, endDate time.Time) (any, error)
 		UserProfileViewsComparisonByCategory(ctx context.Context, userID, categoryID string, startDate, endDate time.Time) (any, error)
 	}
@@ -30,6 +33,6 @@ type (
 	}
 	metricsHandler struct {
 		metricsService MetricsService
-		accessKey      string
+		authMiddleware *middlewares.AuthMiddleware
 	}
 )
@@ -80,6 +80,33 @@ func (r *metricsRepository) UserProfileViews(ctx context.Context, userID string,
 	return &userProfileView, nil
 }
 
+// DailyProfileVisits returns every day on which the professional profile had visits.
+func (r *metricsRepository) DailyProfileVisits(ctx context.Context, userID string) ([]common.DailyVisit, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var visits []common.DailyVisit
+	err := r.db.SelectContext(
+		ctx,
+		&visits,
+		`
+		SELECT
+				toDate(pv."timestamp") AS date,
+				toInt64(count()) AS visits
+		FROM profile_visited AS pv
+		WHERE pv.professional_id = $1
+		GROUP BY date
+		ORDER BY date
+		`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return visits, nil
+}
+
 func (r *metricsRepository) UserTopPerformingServices(ctx context.Context, userID string) (any, error) {
 	// ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	// defer cancel()
@@ -0,0 +1,93 @@
+package oidcstates
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"conecta-mare-server/pkg/oidc"
+	"conecta-mare-server/pkg/security"
+	"conecta-mare-server/pkg/uid"
+	"conecta-mare-server/pkg/valueobjects"
+	"time"
+)
+
+const (
+	// ttl is how long the user has to finish signing in at the provider.
+	ttl = 10 * time.Minute
+)
+
+// LoginState keeps what is needed to finish an authorization code flow between the
+// redirect to the provider and the callback.
+type LoginState struct {
+	id           string
+	stateHash    string
+	codeVerifier string
+	nonce        string
+	role         valueobjects.Role
+	expiresAt    time.Time
+	createdAt    time.Time
+}
+
+// New creates a login state and returns it together with the plain state parameter,
+// which travels through the browser and is only stored hashed.
+func New(role valueobjects.Role) (*LoginState, string, error) {
+	plainState, err := security.GenerateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	codeVerifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return nil, "", err
+	}
+
+	nonce, err := security.GenerateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	state := LoginState{
+		id:           uid.New("oidcstate"),
+		stateHash:    security.HashToken(plainState),
+		codeVerifier: codeVerifier,
+		nonce:        nonce,
+		role:         role,
+		expiresAt:    time.Now().Add(ttl),
+		createdAt:    time.Now(),
+	}
+
+	return &state, plainState, nil
+}
+
+func NewFromModel(m models.OIDCLoginState) *LoginState {
+	return &LoginState{
+		id:           m.ID,
+		stateHash:    m.StateHash,
+		codeVerifier: m.CodeVerifier,
+		nonce:        m.Nonce,
+		role:         m.Role,
+		expiresAt:    m.ExpiresAt,
+		createdAt:    m.CreatedAt,
+	}
+}
+
+func (s *LoginState) ToModel() models.OIDCLoginState {
+	return models.OIDCLoginState{
+		ID:           s.id,
+		StateHash:    s.stateHash,
+		CodeVerifier: s.codeVerifier,
+		Nonce:        s.nonce,
+		Role:         s.role,
+		ExpiresAt:    s.expiresAt,
+		CreatedAt:    s.createdAt,
+	}
+}
+
+func (s *LoginState) IsExpired() bool {
+	return time.Now().After(s.expiresAt)
+}
+
+func (s *LoginState) ID() string              { return s.id }
+func (s *LoginState) CodeVerifier() string    { return s.codeVerifier }
+func (s *LoginState) CodeChallenge() string   { return oidc.CodeChallenge(s.codeVerifier) }
+func (s *LoginState) Nonce() string           { return s.nonce }
+func (s *LoginState) Role() valueobjects.Role { return s.role }
+func (s *LoginState) ExpiresAt() time.Time    { return s.expiresAt }
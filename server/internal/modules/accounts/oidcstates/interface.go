@@ -0,0 +1,12 @@
+package oidcstates
+
+import (
+	"context"
+	"time"
+)
+
+type OIDCStatesRepository interface {
+	Create(ctx context.Context, state *LoginState) error
+	Consume(ctx context.Context, stateHash string) (*LoginState, error)
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
@@ -0,0 +1,60 @@
+package oidcstates
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) OIDCStatesRepository {
+	return &repository{db}
+}
+
+func (r *repository) Create(ctx context.Context, state *LoginState) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	model := state.ToModel()
+	query := `
+		INSERT INTO oidc_login_states (
+				id, state_hash, code_verifier, nonce, role, expires_at, created_at
+			) VALUES (
+				:id, :state_hash, :code_verifier, :nonce, :role, :expires_at, :created_at
+		)`
+
+	_, err := r.db.NamedExecContext(ctx, query, model)
+	return err
+}
+
+// Consume deletes and returns the state, so a callback can only be completed once.
+func (r *repository) Consume(ctx context.Context, stateHash string) (*LoginState, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var model models.OIDCLoginState
+	err := r.db.GetContext(ctx, &model, "DELETE FROM oidc_login_states WHERE state_hash = $1 RETURNING *", stateHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFromModel(model), nil
+}
+
+func (r *repository) DeleteExpired(ctx context.Context, now time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM oidc_login_states WHERE expires_at < $1", now)
+	return err
+}
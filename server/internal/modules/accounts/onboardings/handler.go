@@ -6,6 +6,7 @@ import (
 	"conecta-mare-server/pkg/exceptions"
 	"conecta-mare-server/pkg/httphelpers"
 	"conecta-mare-server/pkg/jwt"
+	"conecta-mare-server/pkg/valueobjects"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -21,13 +22,13 @@ var (
 
 func NewHandler(
 	onboardingsService OnboardingsService,
-	accessKey string,
+	authMiddleware *middlewares.AuthMiddleware,
 ) *onboardingsHandler {
 	Once.Do(
 		func() {
 			instance = &onboardingsHandler{
 				onboardingsService: onboardingsService,
-				accessKey:          accessKey,
+				authMiddleware:     authMiddleware,
 			}
 		},
 	)
@@ -36,10 +37,14 @@ func NewHandler(
 }
 
 func (h *onboardingsHandler) RegisterRoutes(r *chi.Mux) {
-	m := middlewares.NewWithAuth(h.accessKey)
+	m := h.authMiddleware
 
 	r.Route("/api/v1", func(r chi.Router) {
-		r.With(m.WithAuth).Post("/onboarding", h.handleCompleteOnboarding)
+		r.With(
+			m.WithAuth,
+			m.RequireRole(valueobjects.Professional),
+			m.RequireVerifiedEmail(middlewares.ActionCompleteOnboarding),
+		).Post("/onboarding", h.handleCompleteOnboarding)
 	})
 }
 
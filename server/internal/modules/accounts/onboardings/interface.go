@@ -11,6 +11,7 @@ import (
 	"conecta-mare-server/internal/modules/accounts/subcategories"
 	"conecta-mare-server/internal/modules/accounts/userprofiles"
 	"conecta-mare-server/internal/modules/accounts/users"
+	"conecta-mare-server/internal/server/middlewares"
 	"conecta-mare-server/pkg/storage"
 	"context"
 	"log/slog"
@@ -39,6 +40,6 @@ type (
 	}
 	onboardingsHandler struct {
 		onboardingsService OnboardingsService
-		accessKey          string
+		authMiddleware     *middlewares.AuthMiddleware
 	}
 )
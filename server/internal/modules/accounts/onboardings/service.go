@@ -14,7 +14,6 @@ import (
 	"conecta-mare-server/pkg/exceptions"
 	"conecta-mare-server/pkg/storage"
 	"conecta-mare-server/pkg/uid"
-	"conecta-mare-server/pkg/valueobjects"
 	"context"
 	"fmt"
 	"log/slog"
@@ -70,11 +69,6 @@ func (s *onboardingsService) MakeOnboarding(
 		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, fmt.Errorf("user with id %s does not exists", req.UserID))
 	}
 
-	if user.Role != valueobjects.Professional {
-		s.logger.WarnContext(ctx, "user trying to do onboarding as a client user", "user_id", req.UserID)
-		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, fmt.Errorf("only professional can do onboarding"))
-	}
-
 	userProfile, err := s.userProfilesRepository.FindByUserID(ctx, req.UserID)
 	if err != nil {
 		s.logger.ErrorContext(ctx, "failed to verifiy if user profile already exists", "err", err)
@@ -256,7 +250,7 @@ func (s *onboardingsService) createServicesTx(
 		}
 		service.Images = imageWithIDs
 
-		if err := s.createServiceAndImages(ctx, tx, service, userProfileID); err != nil {
+		if err := s.createServiceAndImages(ctx, tx, service, userProfileID, i); err != nil {
 			return err
 		}
 	}
@@ -338,7 +332,7 @@ func (s *onboardingsService) createProjectAndImages(
 	prj *common.Project,
 	userProfileID string,
 ) error {
-	project, err := projects.New(prj.ID, userProfileID, prj.Name, prj.Description)
+	project, err := projects.New(prj.ID, userProfileID, prj.Name, prj.Description, prj.CompletedAt, prj.Tags, nil)
 	if err != nil {
 		s.logger.ErrorContext(ctx, "error creating project entity", "err", err)
 		return exceptions.MakeApiErrorWithStatus(http.StatusUnprocessableEntity, fmt.Errorf("error creating service entity"))
@@ -369,8 +363,9 @@ func (s *onboardingsService) createServiceAndImages(
 	tx *sqlx.Tx,
 	svc *common.OnboardingService,
 	userProfileID string,
+	ordering int,
 ) error {
-	service, err := services.New(svc.ID, userProfileID, svc.Name, svc.Description, svc.Price, svc.OwnLocationPrice)
+	service, err := services.New(svc.ID, userProfileID, svc.Name, svc.Description, svc.Price, svc.OwnLocationPrice, ordering)
 	if err != nil {
 		s.logger.ErrorContext(ctx, "error creating service entity", "err", err)
 		return exceptions.MakeApiErrorWithStatus(http.StatusUnprocessableEntity, fmt.Errorf("error creating service entity"))
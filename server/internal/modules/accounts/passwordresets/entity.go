@@ -0,0 +1,84 @@
+package passwordresets
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/security"
+	"conecta-mare-server/pkg/uid"
+	"fmt"
+	"time"
+)
+
+const (
+	ttl = time.Hour
+)
+
+type PasswordResetToken struct {
+	id        string
+	userID    string
+	tokenHash string
+	expiresAt time.Time
+	usedAt    *time.Time
+	createdAt time.Time
+}
+
+// New creates a reset token for the user and returns it together with the plain
+// token, which must only be sent to the user and is never stored.
+func New(userID string) (*PasswordResetToken, string, error) {
+	plainToken, err := security.GenerateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	resetToken := PasswordResetToken{
+		id:        uid.New("pwdreset"),
+		userID:    userID,
+		tokenHash: security.HashToken(plainToken),
+		expiresAt: time.Now().Add(ttl),
+		usedAt:    nil,
+		createdAt: time.Now(),
+	}
+
+	if err := resetToken.validate(); err != nil {
+		return nil, "", exceptions.MakeApiError(err)
+	}
+
+	return &resetToken, plainToken, nil
+}
+
+func NewFromModel(m models.PasswordResetToken) *PasswordResetToken {
+	return &PasswordResetToken{
+		id:        m.ID,
+		userID:    m.UserID,
+		tokenHash: m.TokenHash,
+		expiresAt: m.ExpiresAt,
+		usedAt:    m.UsedAt,
+		createdAt: m.CreatedAt,
+	}
+}
+
+func (t *PasswordResetToken) ToModel() models.PasswordResetToken {
+	return models.PasswordResetToken{
+		ID:        t.id,
+		UserID:    t.userID,
+		TokenHash: t.tokenHash,
+		ExpiresAt: t.expiresAt,
+		UsedAt:    t.usedAt,
+		CreatedAt: t.createdAt,
+	}
+}
+
+func (t *PasswordResetToken) validate() error {
+	if t.userID == "" {
+		return fmt.Errorf("user_id is required")
+	}
+	return nil
+}
+
+func (t *PasswordResetToken) IsUsable() bool {
+	return t.usedAt == nil && time.Now().Before(t.expiresAt)
+}
+
+func (t *PasswordResetToken) ID() string           { return t.id }
+func (t *PasswordResetToken) UserID() string       { return t.userID }
+func (t *PasswordResetToken) ExpiresAt() time.Time { return t.expiresAt }
@@ -0,0 +1,14 @@
+package passwordresets
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type PasswordResetsRepository interface {
+	Create(ctx context.Context, resetToken *PasswordResetToken) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
+	MarkUsedTx(ctx context.Context, tx *sqlx.Tx, ID string) (bool, error)
+	InvalidateAllByUserID(ctx context.Context, userID string) error
+}
@@ -0,0 +1,88 @@
+package passwordresets
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) PasswordResetsRepository {
+	return &repository{db}
+}
+
+func (r *repository) Create(ctx context.Context, resetToken *PasswordResetToken) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	model := resetToken.ToModel()
+	query := `
+		INSERT INTO password_reset_tokens (
+				id, user_id, token_hash, expires_at, used_at, created_at
+			) VALUES (
+				:id, :user_id, :token_hash, :expires_at, :used_at, :created_at
+		)`
+
+	_, err := r.db.NamedExecContext(ctx, query, model)
+	return err
+}
+
+func (r *repository) GetByTokenHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var model models.PasswordResetToken
+	err := r.db.GetContext(ctx, &model, "SELECT * FROM password_reset_tokens WHERE token_hash = $1", tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFromModel(model), nil
+}
+
+// MarkUsedTx consumes the token, reporting false when it was already used so
+// concurrent resets with the same token cannot both succeed.
+func (r *repository) MarkUsedTx(ctx context.Context, tx *sqlx.Tx, ID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	result, err := tx.ExecContext(
+		ctx,
+		"UPDATE password_reset_tokens SET used_at = $1 WHERE id = $2 AND used_at IS NULL",
+		time.Now(),
+		ID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (r *repository) InvalidateAllByUserID(ctx context.Context, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		"UPDATE password_reset_tokens SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL",
+		time.Now(),
+		userID,
+	)
+	return err
+}
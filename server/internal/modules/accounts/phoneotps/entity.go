@@ -0,0 +1,92 @@
+package phoneotps
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"conecta-mare-server/pkg/security"
+	"conecta-mare-server/pkg/uid"
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	ttl = 10 * time.Minute
+	// maxAttempts bounds guesses against a single six-digit code.
+	maxAttempts = 5
+)
+
+type PhoneOTP struct {
+	id        string
+	phone     string
+	codeHash  string
+	attempts  int
+	expiresAt time.Time
+	usedAt    *time.Time
+	createdAt time.Time
+}
+
+// New creates a code for the phone and returns it together with the plain code,
+// which must only be sent by SMS and is never stored.
+func New(phone string) (*PhoneOTP, string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate phone code: %w", err)
+	}
+	plainCode := fmt.Sprintf("%06d", n.Int64())
+
+	otp := PhoneOTP{
+		id:        uid.New("phoneotp"),
+		phone:     phone,
+		codeHash:  hashCode(phone, plainCode),
+		attempts:  0,
+		expiresAt: time.Now().Add(ttl),
+		usedAt:    nil,
+		createdAt: time.Now(),
+	}
+
+	return &otp, plainCode, nil
+}
+
+func NewFromModel(m models.PhoneOTP) *PhoneOTP {
+	return &PhoneOTP{
+		id:        m.ID,
+		phone:     m.Phone,
+		codeHash:  m.CodeHash,
+		attempts:  m.Attempts,
+		expiresAt: m.ExpiresAt,
+		usedAt:    m.UsedAt,
+		createdAt: m.CreatedAt,
+	}
+}
+
+func (o *PhoneOTP) ToModel() models.PhoneOTP {
+	return models.PhoneOTP{
+		ID:        o.id,
+		Phone:     o.phone,
+		CodeHash:  o.codeHash,
+		Attempts:  o.attempts,
+		ExpiresAt: o.expiresAt,
+		UsedAt:    o.usedAt,
+		CreatedAt: o.createdAt,
+	}
+}
+
+func (o *PhoneOTP) IsUsable() bool {
+	return o.usedAt == nil && o.attempts < maxAttempts && time.Now().Before(o.expiresAt)
+}
+
+func (o *PhoneOTP) Matches(code string) bool {
+	return subtle.ConstantTimeCompare([]byte(o.codeHash), []byte(hashCode(o.phone, code))) == 1
+}
+
+// hashCode salts the code with the phone so equal codes sent to different numbers
+// do not share a hash.
+func hashCode(phone, code string) string {
+	return security.HashToken(phone + ":" + code)
+}
+
+func (o *PhoneOTP) ID() string           { return o.id }
+func (o *PhoneOTP) Phone() string        { return o.phone }
+func (o *PhoneOTP) CreatedAt() time.Time { return o.createdAt }
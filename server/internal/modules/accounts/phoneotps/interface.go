@@ -0,0 +1,14 @@
+package phoneotps
+
+import (
+	"context"
+	"time"
+)
+
+type PhoneOTPsRepository interface {
+	Create(ctx context.Context, otp *PhoneOTP) error
+	GetLatestByPhone(ctx context.Context, phone string) (*PhoneOTP, error)
+	CountCreatedSince(ctx context.Context, phone string, since time.Time) (int, error)
+	ReserveAttempt(ctx context.Context, ID string) (bool, error)
+	MarkUsed(ctx context.Context, ID string) (bool, error)
+}
@@ -0,0 +1,119 @@
+package phoneotps
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) PhoneOTPsRepository {
+	return &repository{db}
+}
+
+func (r *repository) Create(ctx context.Context, otp *PhoneOTP) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	model := otp.ToModel()
+	query := `
+		INSERT INTO phone_otps (
+				id, phone, code_hash, attempts, expires_at, used_at, created_at
+			) VALUES (
+				:id, :phone, :code_hash, :attempts, :expires_at, :used_at, :created_at
+		)`
+
+	_, err := r.db.NamedExecContext(ctx, query, model)
+	return err
+}
+
+func (r *repository) GetLatestByPhone(ctx context.Context, phone string) (*PhoneOTP, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var model models.PhoneOTP
+	err := r.db.GetContext(
+		ctx,
+		&model,
+		"SELECT * FROM phone_otps WHERE phone = $1 ORDER BY created_at DESC LIMIT 1",
+		phone,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFromModel(model), nil
+}
+
+func (r *repository) CountCreatedSince(ctx context.Context, phone string, since time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var count int
+	err := r.db.GetContext(
+		ctx,
+		&count,
+		"SELECT COUNT(*) FROM phone_otps WHERE phone = $1 AND created_at >= $2",
+		phone,
+		since,
+	)
+	return count, err
+}
+
+// ReserveAttempt counts a guess against the code, reporting false once the code ran
+// out of attempts.
+func (r *repository) ReserveAttempt(ctx context.Context, ID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		"UPDATE phone_otps SET attempts = attempts + 1 WHERE id = $1 AND attempts < $2",
+		ID,
+		maxAttempts,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// MarkUsed consumes the code, reporting false when it was already used so concurrent
+// requests with the same code cannot both succeed.
+func (r *repository) MarkUsed(ctx context.Context, ID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		"UPDATE phone_otps SET used_at = $1 WHERE id = $2 AND used_at IS NULL",
+		time.Now(),
+		ID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
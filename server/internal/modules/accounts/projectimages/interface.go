@@ -1,7 +1,17 @@
 package projectimages
 
-import "github.com/jmoiron/sqlx"
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
 
 type ProjectImagesRepository interface {
 	CreateTx(tx *sqlx.Tx, projectImg *ProjectImage) error
+	Create(ctx context.Context, projectImg *ProjectImage) error
+	GetByID(ctx context.Context, ID string) (*ProjectImage, error)
+	GetByProjectID(ctx context.Context, projectID string) ([]*ProjectImage, error)
+	GetByProjectIDs(ctx context.Context, projectIDs []string) ([]*ProjectImage, error)
+	Delete(ctx context.Context, ID string) error
+	UpdateOrderingTx(ctx context.Context, tx *sqlx.Tx, ID string, ordering int) error
 }
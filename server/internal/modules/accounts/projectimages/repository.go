@@ -1,6 +1,12 @@
 package projectimages
 
 import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/jmoiron/sqlx"
 )
 
@@ -12,9 +18,7 @@ func NewRepository(db *sqlx.DB) ProjectImagesRepository {
 	return &repository{db}
 }
 
-func (r *repository) CreateTx(tx *sqlx.Tx, projectImg *ProjectImage) error {
-	model := projectImg.ToModel()
-	query := `
+const insertQuery = `
 		INSERT INTO project_images (
 				id, project_id, url, ordering, created_at
 			) VALUES (
@@ -22,6 +26,82 @@ func (r *repository) CreateTx(tx *sqlx.Tx, projectImg *ProjectImage) error {
 		)
 	`
 
-	_, err := tx.NamedExec(query, model)
+func (r *repository) CreateTx(tx *sqlx.Tx, projectImg *ProjectImage) error {
+	model := projectImg.ToModel()
+
+	_, err := tx.NamedExec(insertQuery, model)
+	return err
+}
+
+func (r *repository) Create(ctx context.Context, projectImg *ProjectImage) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.NamedExecContext(ctx, insertQuery, projectImg.ToModel())
+	return err
+}
+
+func (r *repository) GetByID(ctx context.Context, ID string) (*ProjectImage, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var model models.ProjectImage
+	err := r.db.GetContext(ctx, &model, "SELECT * FROM project_images WHERE id = $1", ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFromModel(model), nil
+}
+
+func (r *repository) GetByProjectID(ctx context.Context, projectID string) ([]*ProjectImage, error) {
+	return r.GetByProjectIDs(ctx, []string{projectID})
+}
+
+func (r *repository) GetByProjectIDs(ctx context.Context, projectIDs []string) ([]*ProjectImage, error) {
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	query, args, err := sqlx.In(
+		"SELECT * FROM project_images WHERE project_id IN (?) ORDER BY project_id, ordering, created_at",
+		projectIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []models.ProjectImage
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	images := make([]*ProjectImage, 0, len(rows))
+	for _, row := range rows {
+		images = append(images, NewFromModel(row))
+	}
+
+	return images, nil
+}
+
+func (r *repository) Delete(ctx context.Context, ID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM project_images WHERE id = $1", ID)
+	return err
+}
+
+func (r *repository) UpdateOrderingTx(ctx context.Context, tx *sqlx.Tx, ID string, ordering int) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "UPDATE project_images SET ordering = $1 WHERE id = $2", ordering, ID)
 	return err
 }
@@ -3,15 +3,26 @@ package projects
 import (
 	"conecta-mare-server/internal/databases/postgres/models"
 	"conecta-mare-server/pkg/exceptions"
+	"fmt"
+	"strings"
 	"time"
 )
 
+const (
+	maxTags      = 10
+	maxTagLength = 30
+)
+
 type Project struct {
 	id            string
 	userProfileID string
+	serviceID     *string
 	name          string
 	description   string
+	completedAt   *time.Time
+	tags          []string
 	createdAt     time.Time
+	updatedAt     *time.Time
 }
 
 func New(
@@ -19,12 +30,18 @@ func New(
 	userProfileID string,
 	name string,
 	description string,
+	completedAt *time.Time,
+	tags []string,
+	serviceID *string,
 ) (*Project, error) {
 	project := Project{
 		id:            porfolioID,
 		userProfileID: userProfileID,
-		name:          name,
-		description:   description,
+		serviceID:     serviceID,
+		name:          strings.TrimSpace(name),
+		description:   strings.TrimSpace(description),
+		completedAt:   truncateToDate(completedAt),
+		tags:          normalizeTags(tags),
 		createdAt:     time.Now(),
 	}
 
@@ -39,28 +56,101 @@ func NewFromModel(m models.Project) *Project {
 	return &Project{
 		id:            m.ID,
 		userProfileID: m.UserProfileID,
+		serviceID:     m.ServiceID,
 		name:          m.Name,
 		description:   m.Description,
+		completedAt:   m.CompletedAt,
+		tags:          m.Tags,
 		createdAt:     m.CreatedAt,
+		updatedAt:     m.UpdatedAt,
 	}
 }
 
 func (s *Project) ToModel() models.Project {
+	tags := s.tags
+	if tags == nil {
+		tags = []string{}
+	}
+
 	return models.Project{
 		ID:            s.id,
 		UserProfileID: s.userProfileID,
+		ServiceID:     s.serviceID,
 		Name:          s.name,
 		Description:   s.description,
+		CompletedAt:   s.completedAt,
+		Tags:          tags,
 		CreatedAt:     s.createdAt,
+		UpdatedAt:     s.updatedAt,
 	}
 }
 
+func (s *Project) Update(name, description string, completedAt *time.Time, tags []string, serviceID *string) error {
+	s.name = strings.TrimSpace(name)
+	s.description = strings.TrimSpace(description)
+	s.completedAt = truncateToDate(completedAt)
+	s.tags = normalizeTags(tags)
+	s.serviceID = serviceID
+
+	now := time.Now()
+	s.updatedAt = &now
+
+	return s.validate()
+}
+
 func (s *Project) validate() error {
+	if s.userProfileID == "" {
+		return fmt.Errorf("user_profile_id is required")
+	}
+	if s.name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if s.completedAt != nil && s.completedAt.After(time.Now()) {
+		return fmt.Errorf("completed_at cannot be in the future")
+	}
+	if len(s.tags) > maxTags {
+		return fmt.Errorf("a project can have at most %d tags", maxTags)
+	}
+	for _, tag := range s.tags {
+		if len([]rune(tag)) > maxTagLength {
+			return fmt.Errorf("tags can have at most %d characters", maxTagLength)
+		}
+	}
 	return nil
 }
 
-func (s *Project) ID() string            { return s.id }
-func (s *Project) UserProfileID() string { return s.userProfileID }
-func (s *Project) Name() string          { return s.name }
-func (s *Project) Description() string   { return s.description }
-func (s *Project) CreatedAt() time.Time  { return s.createdAt }
+// normalizeTags lowercases and trims tags, dropping blanks and repeated ones.
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// truncateToDate drops the time of day, completed_at is stored as a date.
+func truncateToDate(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return &date
+}
+
+func (s *Project) ID() string              { return s.id }
+func (s *Project) UserProfileID() string   { return s.userProfileID }
+func (s *Project) ServiceID() *string      { return s.serviceID }
+func (s *Project) Name() string            { return s.name }
+func (s *Project) Description() string     { return s.description }
+func (s *Project) CompletedAt() *time.Time { return s.completedAt }
+func (s *Project) Tags() []string          { return s.tags }
+func (s *Project) CreatedAt() time.Time    { return s.createdAt }
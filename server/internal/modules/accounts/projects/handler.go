@@ -0,0 +1,169 @@
+package projects
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/httphelpers"
+	"conecta-mare-server/pkg/valueobjects"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxImagesUploadSize bounds the multipart body kept in memory while parsing.
+const maxImagesUploadSize = 32 << 20
+
+var (
+	instance *projectsHandler
+	Once     sync.Once
+)
+
+func NewHandler(projectsService ProjectsService, authMiddleware *middlewares.AuthMiddleware) *projectsHandler {
+	Once.Do(
+		func() {
+			instance = &projectsHandler{
+				projectsService: projectsService,
+				authMiddleware:  authMiddleware,
+			}
+		},
+	)
+
+	return instance
+}
+
+func (h projectsHandler) RegisterRoutes(r *chi.Mux) {
+	m := h.authMiddleware
+	r.Route(
+		"/api/v1/me/projects", func(r chi.Router) {
+			// Private
+			r.Group(func(r chi.Router) {
+				r.Use(m.WithAuth, m.RequireRole(valueobjects.Professional))
+				r.Get("/", h.handleGetMyProjects)
+				r.Post("/", h.handleCreateProject)
+				r.Put("/{project_id}", h.handleUpdateProject)
+				r.Delete("/{project_id}", h.handleDeleteProject)
+				r.Post("/{project_id}/images", h.handleAddImages)
+				r.Put("/{project_id}/images/order", h.handleReorderImages)
+				r.Delete("/{project_id}/images/{image_id}", h.handleRemoveImage)
+			})
+		},
+	)
+}
+
+func (h projectsHandler) handleGetMyProjects(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	projects, err := h.projectsService.GetMyProjects(ctx)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, map[string]any{"projects": projects})
+}
+
+func (h projectsHandler) handleCreateProject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.SaveProjectRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	project, err := h.projectsService.CreateProject(ctx, body)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusCreated, project)
+}
+
+func (h projectsHandler) handleUpdateProject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID := chi.URLParam(r, "project_id")
+
+	var body common.SaveProjectRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	project, err := h.projectsService.UpdateProject(ctx, projectID, body)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, project)
+}
+
+func (h projectsHandler) handleDeleteProject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID := chi.URLParam(r, "project_id")
+
+	if err := h.projectsService.DeleteProject(ctx, projectID); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteSuccess(w, http.StatusOK)
+}
+
+// handleAddImages takes one or more files in the multipart field "images".
+func (h projectsHandler) handleAddImages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID := chi.URLParam(r, "project_id")
+
+	if err := r.ParseMultipartForm(maxImagesUploadSize); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidRequestBody)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	project, err := h.projectsService.AddImages(ctx, projectID, r.MultipartForm.File["images"])
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusCreated, project)
+}
+
+func (h projectsHandler) handleReorderImages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID := chi.URLParam(r, "project_id")
+
+	var body common.ReorderRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	project, err := h.projectsService.ReorderImages(ctx, projectID, body)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, project)
+}
+
+func (h projectsHandler) handleRemoveImage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID := chi.URLParam(r, "project_id")
+	imageID := chi.URLParam(r, "image_id")
+
+	if err := h.projectsService.RemoveImage(ctx, projectID, imageID); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteSuccess(w, http.StatusOK)
+}
@@ -1,7 +1,49 @@
 package projects
 
-import "github.com/jmoiron/sqlx"
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/modules/accounts/projectimages"
+	"conecta-mare-server/internal/modules/accounts/services"
+	"conecta-mare-server/internal/modules/accounts/userprofiles"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/storage"
+	"context"
+	"log/slog"
+	"mime/multipart"
 
-type ProjectsRepository interface {
-	CreateTx(tx *sqlx.Tx, project *Project) error
-}
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	ProjectsRepository interface {
+		CreateTx(tx *sqlx.Tx, project *Project) error
+		Create(ctx context.Context, project *Project) error
+		GetByID(ctx context.Context, ID string) (*Project, error)
+		GetByUserProfileID(ctx context.Context, userProfileID string) ([]*Project, error)
+		Update(ctx context.Context, project *Project) error
+		Delete(ctx context.Context, ID string) error
+	}
+	ProjectsService interface {
+		GetMyProjects(ctx context.Context) ([]common.Project, *exceptions.ApiError[string])
+		CreateProject(ctx context.Context, input common.SaveProjectRequest) (*common.Project, *exceptions.ApiError[string])
+		UpdateProject(ctx context.Context, ID string, input common.SaveProjectRequest) (*common.Project, *exceptions.ApiError[string])
+		DeleteProject(ctx context.Context, ID string) *exceptions.ApiError[string]
+		AddImages(ctx context.Context, ID string, files []*multipart.FileHeader) (*common.Project, *exceptions.ApiError[string])
+		RemoveImage(ctx context.Context, ID, imageID string) *exceptions.ApiError[string]
+		ReorderImages(ctx context.Context, ID string, input common.ReorderRequest) (*common.Project, *exceptions.ApiError[string])
+	}
+	projectsService struct {
+		db               *sqlx.DB
+		repository       ProjectsRepository
+		imagesRepo       projectimages.ProjectImagesRepository
+		servicesRepo     services.ServicesRepository
+		userProfilesRepo userprofiles.UserProfilesRepository
+		storageClient    *storage.StorageClient
+		logger           *slog.Logger
+	}
+	projectsHandler struct {
+		projectsService ProjectsService
+		authMiddleware  *middlewares.AuthMiddleware
+	}
+)
@@ -1,6 +1,12 @@
 package projects
 
 import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/jmoiron/sqlx"
 )
 
@@ -12,15 +18,95 @@ func NewRepository(db *sqlx.DB) ProjectsRepository {
 	return &repository{db}
 }
 
-func (r *repository) CreateTx(tx *sqlx.Tx, project *Project) error {
-	model := project.ToModel()
-	query := `
+const insertQuery = `
 		INSERT INTO projects (
-				id, user_profile_id, name, description, created_at
+				id, user_profile_id, service_id, name, description, completed_at, tags, created_at
 			) VALUES (
-				:id, :user_profile_id, :name, :description, :created_at
+				:id, :user_profile_id, :service_id, :name, :description, :completed_at, :tags, :created_at
 		)`
 
-	_, err := tx.NamedExec(query, model)
+func (r *repository) CreateTx(tx *sqlx.Tx, project *Project) error {
+	model := project.ToModel()
+
+	_, err := tx.NamedExec(insertQuery, model)
+	return err
+}
+
+func (r *repository) Create(ctx context.Context, project *Project) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.NamedExecContext(ctx, insertQuery, project.ToModel())
+	return err
+}
+
+func (r *repository) GetByID(ctx context.Context, ID string) (*Project, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var model models.Project
+	err := r.db.GetContext(ctx, &model, "SELECT * FROM projects WHERE id = $1", ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFromModel(model), nil
+}
+
+// GetByUserProfileID returns the portfolio with the most recently finished jobs first.
+func (r *repository) GetByUserProfileID(ctx context.Context, userProfileID string) ([]*Project, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var rows []models.Project
+	err := r.db.SelectContext(
+		ctx,
+		&rows,
+		`
+		SELECT * FROM projects
+		WHERE user_profile_id = $1
+		ORDER BY completed_at DESC NULLS LAST, created_at DESC
+		`,
+		userProfileID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]*Project, 0, len(rows))
+	for _, row := range rows {
+		projects = append(projects, NewFromModel(row))
+	}
+
+	return projects, nil
+}
+
+func (r *repository) Update(ctx context.Context, project *Project) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE projects SET
+			service_id = :service_id,
+			name = :name,
+			description = :description,
+			completed_at = :completed_at,
+			tags = :tags,
+			updated_at = :updated_at
+		WHERE id = :id`
+
+	_, err := r.db.NamedExecContext(ctx, query, project.ToModel())
+	return err
+}
+
+// Delete removes the project, its images go with it through the foreign key.
+func (r *repository) Delete(ctx context.Context, ID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM projects WHERE id = $1", ID)
 	return err
 }
@@ -0,0 +1,443 @@
+package projects
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/modules/accounts/projectimages"
+	"conecta-mare-server/internal/modules/accounts/services"
+	"conecta-mare-server/internal/modules/accounts/userprofiles"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/jwt"
+	"conecta-mare-server/pkg/storage"
+	"conecta-mare-server/pkg/uid"
+	"conecta-mare-server/pkg/utils"
+	"context"
+	"fmt"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// maxImagesPerProject bounds how many pictures a single portfolio entry can show.
+const maxImagesPerProject = 10
+
+func NewService(
+	db *sqlx.DB,
+	repository ProjectsRepository,
+	imagesRepo projectimages.ProjectImagesRepository,
+	servicesRepo services.ServicesRepository,
+	userProfilesRepo userprofiles.UserProfilesRepository,
+	storageClient *storage.StorageClient,
+	logger *slog.Logger,
+) ProjectsService {
+	return &projectsService{
+		db:               db,
+		repository:       repository,
+		imagesRepo:       imagesRepo,
+		servicesRepo:     servicesRepo,
+		userProfilesRepo: userProfilesRepo,
+		storageClient:    storageClient,
+		logger:           logger,
+	}
+}
+
+func (s *projectsService) GetMyProjects(ctx context.Context) ([]common.Project, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to get own projects")
+
+	_, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	projects, err := s.repository.GetByUserProfileID(ctx, profileID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get projects", "user_profile_id", profileID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	return s.toResponses(ctx, projects)
+}
+
+// CreateProject adds a portfolio entry. Images are sent afterwards through AddImages.
+func (s *projectsService) CreateProject(ctx context.Context, input common.SaveProjectRequest) (*common.Project, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to create project")
+
+	_, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	if apiErr := s.checkLinkedService(ctx, profileID, input.ServiceID); apiErr != nil {
+		return nil, apiErr
+	}
+
+	project, err := New(
+		uid.New("project"),
+		profileID,
+		input.Name,
+		input.Description,
+		input.CompletedAt,
+		input.Tags,
+		input.ServiceID,
+	)
+	if err != nil {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+	}
+
+	if err := s.repository.Create(ctx, project); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to create project", "user_profile_id", profileID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "project created with success", "project_id", project.ID())
+	return s.toResponse(ctx, project)
+}
+
+func (s *projectsService) UpdateProject(ctx context.Context, ID string, input common.SaveProjectRequest) (*common.Project, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to update project", "project_id", ID)
+
+	_, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	project, apiErr := s.getOwnedProject(ctx, profileID, ID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	if apiErr := s.checkLinkedService(ctx, profileID, input.ServiceID); apiErr != nil {
+		return nil, apiErr
+	}
+
+	if err := project.Update(input.Name, input.Description, input.CompletedAt, input.Tags, input.ServiceID); err != nil {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+	}
+
+	if err := s.repository.Update(ctx, project); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to update project", "project_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "project updated with success", "project_id", ID)
+	return s.toResponse(ctx, project)
+}
+
+// DeleteProject removes the project with its images, both the rows and the files.
+func (s *projectsService) DeleteProject(ctx context.Context, ID string) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to delete project", "project_id", ID)
+
+	userID, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	if _, apiErr := s.getOwnedProject(ctx, profileID, ID); apiErr != nil {
+		return apiErr
+	}
+
+	if err := s.repository.Delete(ctx, ID); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to delete project", "project_id", ID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	// The rows are gone already, leftover files are only logged.
+	prefix := fmt.Sprintf("projects/%s/%s/", userID, ID)
+	if err := s.storageClient.RemoveByPrefix(ctx, prefix); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to remove project images", "prefix", prefix, "err", err)
+	}
+
+	s.logger.InfoContext(ctx, "project deleted with success", "project_id", ID)
+	return nil
+}
+
+// AddImages uploads pictures and appends them after the images the project already has.
+func (s *projectsService) AddImages(ctx context.Context, ID string, files []*multipart.FileHeader) (*common.Project, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to add project images", "project_id", ID)
+
+	if len(files) == 0 {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrImagesRequired)
+	}
+	for _, file := range files {
+		if !storage.IsImage(file) {
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidImage)
+		}
+	}
+
+	userID, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	project, apiErr := s.getOwnedProject(ctx, profileID, ID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	existing, err := s.imagesRepo.GetByProjectID(ctx, ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get project images", "project_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if len(existing)+len(files) > maxImagesPerProject {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrTooManyImages)
+	}
+
+	ordering := 0
+	if len(existing) > 0 {
+		ordering = existing[len(existing)-1].Ordering() + 1
+	}
+
+	var uploaded []string
+	var images []*projectimages.ProjectImage
+	for i, file := range files {
+		imageID := uid.New("projectimg")
+		objectName := fmt.Sprintf("projects/%s/%s/%s", userID, ID, imageID)
+
+		url, err := s.storageClient.UploadFile(objectName, file)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to upload project image", "project_id", ID, "err", err)
+			s.removeObjects(ctx, uploaded)
+			return nil, exceptions.MakeGenericApiError()
+		}
+		uploaded = append(uploaded, objectName)
+
+		image, err := projectimages.New(imageID, ID, url, ordering+i)
+		if err != nil {
+			s.removeObjects(ctx, uploaded)
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		}
+		images = append(images, image)
+	}
+
+	if apiErr := s.createImagesTx(ctx, images); apiErr != nil {
+		s.removeObjects(ctx, uploaded)
+		return nil, apiErr
+	}
+
+	s.logger.InfoContext(ctx, "project images added with success", "project_id", ID, "count", len(images))
+	return s.toResponse(ctx, project)
+}
+
+func (s *projectsService) RemoveImage(ctx context.Context, ID, imageID string) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to remove project image", "project_id", ID, "image_id", imageID)
+
+	userID, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	if _, apiErr := s.getOwnedProject(ctx, profileID, ID); apiErr != nil {
+		return apiErr
+	}
+
+	image, err := s.imagesRepo.GetByID(ctx, imageID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get project image", "image_id", imageID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if image == nil || image.ProjectID() != ID {
+		return exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrProjectImageNotFound)
+	}
+
+	if err := s.imagesRepo.Delete(ctx, imageID); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to delete project image", "image_id", imageID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	s.removeObjects(ctx, []string{fmt.Sprintf("projects/%s/%s/%s", userID, ID, imageID)})
+
+	s.logger.InfoContext(ctx, "project image removed with success", "project_id", ID, "image_id", imageID)
+	return nil
+}
+
+func (s *projectsService) ReorderImages(ctx context.Context, ID string, input common.ReorderRequest) (*common.Project, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to reorder project images", "project_id", ID)
+
+	_, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	project, apiErr := s.getOwnedProject(ctx, profileID, ID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	images, err := s.imagesRepo.GetByProjectID(ctx, ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get project images", "project_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	byID := make(map[string]*projectimages.ProjectImage, len(images))
+	for _, image := range images {
+		byID[image.ID()] = image
+	}
+	if !utils.IsPermutation(input.IDs, byID) {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidOrdering)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while starting transaction", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	defer tx.Rollback()
+
+	for ordering, imageID := range input.IDs {
+		if err := s.imagesRepo.UpdateOrderingTx(ctx, tx, imageID, ordering); err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to update project image ordering", "image_id", imageID, "err", err)
+			return nil, exceptions.MakeGenericApiError()
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.ErrorContext(ctx, "error while commiting project image ordering", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "project images reordered with success", "project_id", ID)
+	return s.toResponse(ctx, project)
+}
+
+// currentProfile returns the signed user and the profile that owns their portfolio.
+func (s *projectsService) currentProfile(ctx context.Context) (string, string, *exceptions.ApiError[string]) {
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return "", "", exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	userProfile, err := s.userProfilesRepo.FindByUserID(ctx, c.UserID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to find user profile", "user_id", c.UserID, "err", err)
+		return "", "", exceptions.MakeGenericApiError()
+	}
+	if userProfile == nil {
+		s.logger.WarnContext(ctx, "user profile not found", "user_id", c.UserID)
+		return "", "", exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrUserProfileNotFound)
+	}
+
+	return c.UserID, userProfile.ID(), nil
+}
+
+// getOwnedProject answers projects of other profiles as not found, so their ids
+// cannot be probed.
+func (s *projectsService) getOwnedProject(ctx context.Context, profileID, ID string) (*Project, *exceptions.ApiError[string]) {
+	project, err := s.repository.GetByID(ctx, ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get project", "project_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if project == nil || project.UserProfileID() != profileID {
+		s.logger.WarnContext(ctx, "project not found for profile", "project_id", ID, "user_profile_id", profileID)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrProjectNotFound)
+	}
+
+	return project, nil
+}
+
+// checkLinkedService makes sure a project only points to one of the caller's own
+// services that was not deleted.
+func (s *projectsService) checkLinkedService(ctx context.Context, profileID string, serviceID *string) *exceptions.ApiError[string] {
+	if serviceID == nil {
+		return nil
+	}
+
+	service, err := s.servicesRepo.GetByID(ctx, *serviceID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get service", "service_id", *serviceID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if service == nil || service.IsDeleted() || service.UserProfileID() != profileID {
+		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrServiceNotFound)
+	}
+
+	return nil
+}
+
+func (s *projectsService) createImagesTx(ctx context.Context, images []*projectimages.ProjectImage) *exceptions.ApiError[string] {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while starting transaction", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	defer tx.Rollback()
+
+	for _, image := range images {
+		if err := s.imagesRepo.CreateTx(tx, image); err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to create project image", "image_id", image.ID(), "err", err)
+			return exceptions.MakeGenericApiError()
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.ErrorContext(ctx, "error while commiting project images", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	return nil
+}
+
+// removeObjects deletes uploaded files that are no longer referenced. Failures are
+// only logged, a leftover file does not affect the portfolio.
+func (s *projectsService) removeObjects(ctx context.Context, objectNames []string) {
+	for _, objectName := range objectNames {
+		if err := s.storageClient.RemoveObject(ctx, objectName); err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to remove project image", "object", objectName, "err", err)
+		}
+	}
+}
+
+func (s *projectsService) toResponse(ctx context.Context, project *Project) (*common.Project, *exceptions.ApiError[string]) {
+	responses, apiErr := s.toResponses(ctx, []*Project{project})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return &responses[0], nil
+}
+
+func (s *projectsService) toResponses(ctx context.Context, projects []*Project) ([]common.Project, *exceptions.ApiError[string]) {
+	projectIDs := make([]string, 0, len(projects))
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ID())
+	}
+
+	images, err := s.imagesRepo.GetByProjectIDs(ctx, projectIDs)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get project images", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	imagesByProject := make(map[string][]common.ProjectImage, len(projects))
+	for _, image := range images {
+		imagesByProject[image.ProjectID()] = append(imagesByProject[image.ProjectID()], common.ProjectImage{
+			ID:       image.ID(),
+			URL:      image.URL(),
+			Ordering: image.Ordering(),
+		})
+	}
+
+	responses := make([]common.Project, 0, len(projects))
+	for _, project := range projects {
+		projectImages := imagesByProject[project.ID()]
+		if projectImages == nil {
+			projectImages = []common.ProjectImage{}
+		}
+
+		responses = append(responses, common.Project{
+			ID:          project.ID(),
+			Name:        project.Name(),
+			Description: project.Description(),
+			CompletedAt: project.CompletedAt(),
+			Tags:        project.Tags(),
+			ServiceID:   project.ServiceID(),
+			Images:      projectImages,
+		})
+	}
+
+	return responses, nil
+}
@@ -0,0 +1,76 @@
+package search
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/pkg/httphelpers"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+var (
+	instance *searchHandler
+	Once     sync.Once
+)
+
+func NewHandler(searchService SearchService) *searchHandler {
+	Once.Do(
+		func() {
+			instance = &searchHandler{
+				searchService: searchService,
+			}
+		},
+	)
+
+	return instance
+}
+
+func (h searchHandler) RegisterRoutes(r *chi.Mux) {
+	r.Route(
+		"/api/v1/search", func(r chi.Router) {
+			// Public
+			r.Get("/", h.handleSearch)
+			r.Get("/suggest", h.handleSuggest)
+		},
+	)
+}
+
+func (h searchHandler) handleSearch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	filter := common.SearchFilter{
+		Query:         httphelpers.ReadQueryString(query, "q", ""),
+		CategoryID:    httphelpers.ReadQueryString(query, "category_id", ""),
+		SubcategoryID: httphelpers.ReadQueryString(query, "subcategory_id", ""),
+		CommunityID:   httphelpers.ReadQueryString(query, "community_id", ""),
+		Cursor:        httphelpers.ReadQueryString(query, "cursor", ""),
+		Limit:         httphelpers.ReadQueryInt(query, "limit", defaultLimit),
+	}
+
+	page, err := h.searchService.Search(ctx, filter)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, page)
+}
+
+func (h searchHandler) handleSuggest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	suggestions, err := h.searchService.Suggest(
+		ctx,
+		httphelpers.ReadQueryString(query, "q", ""),
+		httphelpers.ReadQueryInt(query, "limit", defaultSuggestLimit),
+	)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, map[string]any{"suggestions": suggestions})
+}
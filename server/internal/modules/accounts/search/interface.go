@@ -0,0 +1,74 @@
+package search
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/pkg/exceptions"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+type (
+	// Filter is the validated form of common.SearchFilter. When AfterID is set, only
+	// matches ranked after (AfterRank, AfterID) are returned.
+	Filter struct {
+		Query         string
+		OnlyVerified  bool
+		CategoryID    string
+		SubcategoryID string
+		CommunityID   string
+		AfterRank     string
+		AfterID       string
+		Limit         int
+	}
+
+	// Match is a professional found by the search, as read from the database.
+	Match struct {
+		UserID          string          `db:"user_id"`
+		FullName        string          `db:"full_name"`
+		ProfileImage    *string         `db:"profile_image"`
+		JobDescription  string          `db:"job_description"`
+		Location        string          `db:"location"`
+		Category        string          `db:"category"`
+		Subcategory     string          `db:"subcategory"`
+		Rank            float64         `db:"rank"`
+		RankKey         string          `db:"rank_key"`
+		Snippet         string          `db:"snippet"`
+		MatchedServices json.RawMessage `db:"matched_services"`
+	}
+
+	// SuggestionEntry is a catalog item that can be suggested while typing.
+	SuggestionEntry struct {
+		Type           string `db:"type"`
+		ID             string `db:"id"`
+		Label          string `db:"label"`
+		Detail         string `db:"detail"`
+		ProfessionalID string `db:"professional_id"`
+	}
+
+	SearchRepository interface {
+		Search(ctx context.Context, filter Filter) ([]*Match, int, error)
+		ListSuggestionEntries(ctx context.Context, onlyVerified bool) ([]*SuggestionEntry, error)
+		CatalogVersion(ctx context.Context) (string, error)
+	}
+	SearchService interface {
+		Search(ctx context.Context, filter common.SearchFilter) (*common.SearchPage, *exceptions.ApiError[string])
+		Suggest(ctx context.Context, query string, limit int) ([]common.SearchSuggestion, *exceptions.ApiError[string])
+		RefreshSuggestions(ctx context.Context)
+	}
+	searchService struct {
+		repository     SearchRepository
+		hideUnverified bool
+		logger         *slog.Logger
+
+		// suggestions is read on every keystroke, refreshMu only serializes rebuilds.
+		suggestions    atomic.Pointer[suggestionIndex]
+		catalogVersion string
+		refreshMu      sync.Mutex
+	}
+	searchHandler struct {
+		searchService SearchService
+	}
+)
@@ -0,0 +1,219 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// headlineOptions marks matched terms with brackets that do not need HTML escaping, so
+// the snippet can be escaped before the markers become <mark> tags.
+const headlineOptions = `StartSel=⟦, StopSel=⟧, MaxWords=25, MinWords=8, MaxFragments=2, FragmentDelimiter=" … "`
+
+// synonymRank is what a term from the taxonomy adds to the rank, about what a match on
+// the subcategory name itself scores.
+const synonymRank = "0.6"
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) SearchRepository {
+	return &repository{db}
+}
+
+// Search returns a page of professionals matching the query and how many match in
+// total. A professional matches through the profile, the subcategory, any of their
+// services or a taxonomy term of their subcategory, and the ranks are added up.
+func (r *repository) Search(ctx context.Context, filter Filter) ([]*Match, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	args := []any{}
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	text := arg(filter.Query)
+	tsQuery := fmt.Sprintf("websearch_to_tsquery('portuguese_unaccent', %s)", text)
+	headline := arg(headlineOptions)
+
+	// These filters follow the professionals listing.
+	conditions := []string{
+		`u."role" = 'professional'`,
+		"up.job_description IS NOT NULL",
+		"u.deleted_at IS NULL",
+		`(
+			search_profile_document(up.full_name, up.job_description) @@ q.query
+			OR search_subcategory_document(sc.name) @@ q.query
+			OR sv.rank IS NOT NULL
+			OR up.subcategory_id = ANY(q.synonyms)
+		)`,
+	}
+	if filter.OnlyVerified {
+		conditions = append(conditions, "(u.email_verified_at IS NOT NULL OR u.phone_verified_at IS NOT NULL)")
+	}
+	if filter.CategoryID != "" {
+		conditions = append(conditions, "sc.category_id = "+arg(filter.CategoryID))
+	}
+	if filter.SubcategoryID != "" {
+		conditions = append(conditions, "up.subcategory_id = "+arg(filter.SubcategoryID))
+	}
+	if filter.CommunityID != "" {
+		placeholder := arg(filter.CommunityID)
+		conditions = append(conditions, fmt.Sprintf(
+			"(l.community_id = %s OR EXISTS (SELECT 1 FROM service_areas sa WHERE sa.user_profile_id = up.id AND sa.community_id = %s))",
+			placeholder,
+			placeholder,
+		))
+	}
+
+	from := `
+			FROM users u
+			INNER JOIN user_profiles up ON up.user_id = u.id
+			INNER JOIN subcategories sc ON sc.id = up.subcategory_id
+			INNER JOIN categories ca ON ca.id = sc.category_id
+			INNER JOIN locations l ON l.user_profile_id = up.id
+			INNER JOIN communities cm ON cm.id = l.community_id
+			CROSS JOIN (
+				SELECT
+						` + tsQuery + ` AS query,
+						ARRAY(
+							SELECT st.subcategory_id
+							FROM search_terms st
+							WHERE ' ' || search_normalize(` + text + `) || ' ' LIKE '% ' || st.normalized_term || ' %'
+						) AS synonyms
+			) q
+			LEFT JOIN LATERAL (
+				SELECT
+						MAX(ts_rank(search_service_document(se.name, se.description), q.query)) AS rank,
+						json_agg(
+							json_build_object(
+								'id', se.id,
+								'name', ts_headline('portuguese_unaccent', se.name, q.query, ` + headline + `),
+								'snippet', ts_headline('portuguese_unaccent', se.description, q.query, ` + headline + `)
+							)
+							ORDER BY ts_rank(search_service_document(se.name, se.description), q.query) DESC, se.ordering
+						) AS services
+				FROM services se
+				WHERE se.user_profile_id = up.id
+						AND se.deleted_at IS NULL
+						AND search_service_document(se.name, se.description) @@ q.query
+			) sv ON TRUE
+			WHERE ` + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*)"+from, args...); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		WITH m AS (
+			SELECT
+					u.id AS user_id,
+					up.full_name,
+					up.profile_image,
+					up.job_description,
+					cm."name" AS location,
+					ca.name AS category,
+					sc.name AS subcategory,
+					(
+						ts_rank(search_profile_document(up.full_name, up.job_description), q.query) +
+						ts_rank(search_subcategory_document(sc.name), q.query) +
+						COALESCE(sv.rank, 0) +
+						CASE WHEN up.subcategory_id = ANY(q.synonyms) THEN ` + synonymRank + ` ELSE 0 END
+					)::FLOAT8 AS rank,
+					ts_headline('portuguese_unaccent', up.job_description, q.query, ` + headline + `) AS snippet,
+					COALESCE(sv.services, '[]'::JSON) AS matched_services` + from + `
+		)
+		SELECT m.*, m.rank::TEXT AS rank_key
+		FROM m`
+
+	if filter.AfterID != "" {
+		query += fmt.Sprintf(" WHERE (m.rank, m.user_id) < (%s::FLOAT8, %s)", arg(filter.AfterRank), arg(filter.AfterID))
+	}
+	query += " ORDER BY m.rank DESC, m.user_id DESC LIMIT " + arg(filter.Limit)
+
+	var matches []*Match
+	if err := r.db.SelectContext(ctx, &matches, query, args...); err != nil {
+		return nil, 0, err
+	}
+
+	return matches, total, nil
+}
+
+// ListSuggestionEntries loads everything the autocomplete can suggest. Professionals
+// and their services follow the same visibility rules as the search.
+func (r *repository) ListSuggestionEntries(ctx context.Context, onlyVerified bool) ([]*SuggestionEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	visible := `u."role" = 'professional' AND up.job_description IS NOT NULL AND u.deleted_at IS NULL`
+	if onlyVerified {
+		visible += " AND (u.email_verified_at IS NOT NULL OR u.phone_verified_at IS NOT NULL)"
+	}
+
+	query := `
+		SELECT 'category' AS type, ca.id, ca.name AS label, '' AS detail, '' AS professional_id
+		FROM categories ca
+		WHERE ca.deleted_at IS NULL
+		UNION ALL
+		SELECT 'subcategory', sc.id, sc.name, ca.name, ''
+		FROM subcategories sc
+		INNER JOIN categories ca ON ca.id = sc.category_id
+		WHERE sc.deleted_at IS NULL AND ca.deleted_at IS NULL
+		UNION ALL
+		SELECT 'synonym', sc.id, st.term, sc.name, ''
+		FROM search_terms st
+		INNER JOIN subcategories sc ON sc.id = st.subcategory_id
+		WHERE sc.deleted_at IS NULL
+		UNION ALL
+		SELECT 'professional', u.id, up.full_name, sc.name, u.id
+		FROM users u
+		INNER JOIN user_profiles up ON up.user_id = u.id
+		INNER JOIN subcategories sc ON sc.id = up.subcategory_id
+		WHERE ` + visible + `
+		UNION ALL
+		SELECT 'service', se.id, se.name, up.full_name, u.id
+		FROM services se
+		INNER JOIN user_profiles up ON up.id = se.user_profile_id
+		INNER JOIN users u ON u.id = up.user_id
+		WHERE se.deleted_at IS NULL AND ` + visible
+
+	var entries []*SuggestionEntry
+	if err := r.db.SelectContext(ctx, &entries, query); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// CatalogVersion fingerprints the tables the suggestions come from with their row
+// counts and latest changes. Every write in the app sets created_at, updated_at or
+// deleted_at, so any change shows up here without loading the rows.
+func (r *repository) CatalogVersion(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT concat_ws(
+			'|',
+			(SELECT COUNT(*) || ':' || COALESCE(MAX(GREATEST(created_at, updated_at, deleted_at))::TEXT, '') FROM categories),
+			(SELECT COUNT(*) || ':' || COALESCE(MAX(GREATEST(created_at, updated_at, deleted_at))::TEXT, '') FROM subcategories),
+			(SELECT COUNT(*) || ':' || COALESCE(MAX(created_at)::TEXT, '') FROM search_terms),
+			(SELECT COUNT(*) || ':' || COALESCE(MAX(GREATEST(created_at, updated_at))::TEXT, '') FROM user_profiles),
+			(SELECT COUNT(*) || ':' || COALESCE(MAX(GREATEST(created_at, updated_at, deleted_at))::TEXT, '') FROM services),
+			(SELECT COUNT(*) || ':' || COALESCE(MAX(GREATEST(created_at, updated_at, deleted_at))::TEXT, '') FROM users)
+		)`
+
+	var version string
+	if err := r.db.GetContext(ctx, &version, query); err != nil {
+		return "", err
+	}
+
+	return version, nil
+}
@@ -0,0 +1,208 @@
+package search
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/pkg/exceptions"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"html"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 50
+	// maxQueryLength keeps the search terms to what fits in a search box.
+	maxQueryLength = 100
+
+	defaultSuggestLimit = 8
+	maxSuggestLimit     = 20
+)
+
+var highlightReplacer = strings.NewReplacer("⟦", "<mark>", "⟧", "</mark>")
+
+// NewService builds the search service. hideUnverified mirrors the professionals
+// listing, so both show the same people.
+func NewService(repository SearchRepository, hideUnverified bool, logger *slog.Logger) SearchService {
+	return &searchService{
+		repository:     repository,
+		hideUnverified: hideUnverified,
+		logger:         logger,
+	}
+}
+
+// Search finds professionals by what they do, best matches first. Pages are chained
+// through next_cursor.
+func (s *searchService) Search(ctx context.Context, filter common.SearchFilter) (*common.SearchPage, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to search professionals", "query", filter.Query)
+
+	query := strings.TrimSpace(filter.Query)
+	if query == "" || utf8.RuneCountInString(query) > maxQueryLength {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidSearchQuery)
+	}
+
+	searchFilter := Filter{
+		Query:         query,
+		OnlyVerified:  s.hideUnverified,
+		CategoryID:    filter.CategoryID,
+		SubcategoryID: filter.SubcategoryID,
+		CommunityID:   filter.CommunityID,
+		Limit:         filter.Limit,
+	}
+	if searchFilter.Limit <= 0 || searchFilter.Limit > maxLimit {
+		searchFilter.Limit = defaultLimit
+	}
+
+	if filter.Cursor != "" {
+		rank, ID, ok := decodeCursor(filter.Cursor)
+		if !ok {
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidCursor)
+		}
+		searchFilter.AfterRank = rank
+		searchFilter.AfterID = ID
+	}
+
+	// One extra row tells whether another page exists.
+	requested := searchFilter.Limit
+	searchFilter.Limit++
+
+	matches, total, err := s.repository.Search(ctx, searchFilter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to search professionals", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	page := common.SearchPage{TotalCount: total}
+	if len(matches) > requested {
+		matches = matches[:requested]
+		last := matches[len(matches)-1]
+		cursor := encodeCursor(last.RankKey, last.UserID)
+		page.NextCursor = &cursor
+	}
+
+	page.Results = make([]common.SearchResult, 0, len(matches))
+	for _, match := range matches {
+		result, err := toResult(match)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to decode search match", "user_id", match.UserID, "err", err)
+			return nil, exceptions.MakeGenericApiError()
+		}
+		page.Results = append(page.Results, result)
+	}
+
+	return &page, nil
+}
+
+// Suggest completes what is being typed in the search box from the in-memory index,
+// so it never waits on the database once the index is loaded.
+func (s *searchService) Suggest(ctx context.Context, query string, limit int) ([]common.SearchSuggestion, *exceptions.ApiError[string]) {
+	query = strings.TrimSpace(query)
+	if query == "" || utf8.RuneCountInString(query) > maxQueryLength {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidSearchQuery)
+	}
+	if limit <= 0 || limit > maxSuggestLimit {
+		limit = defaultSuggestLimit
+	}
+
+	index := s.suggestions.Load()
+	if index == nil {
+		// The refresh job has not loaded the index yet.
+		s.RefreshSuggestions(ctx)
+		if index = s.suggestions.Load(); index == nil {
+			return nil, exceptions.MakeGenericApiError()
+		}
+	}
+
+	return index.lookup(query, limit), nil
+}
+
+// RefreshSuggestions rebuilds the suggestion index when the catalog changed since the
+// last build. It is cheap to call often: most calls only read the catalog version.
+func (s *searchService) RefreshSuggestions(ctx context.Context) {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	version, err := s.repository.CatalogVersion(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get catalog version", "err", err)
+		return
+	}
+	if version == s.catalogVersion && s.suggestions.Load() != nil {
+		return
+	}
+
+	entries, err := s.repository.ListSuggestionEntries(ctx, s.hideUnverified)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to list suggestion entries", "err", err)
+		return
+	}
+
+	s.suggestions.Store(newSuggestionIndex(entries))
+	s.catalogVersion = version
+	s.logger.InfoContext(ctx, "search suggestions refreshed with success", "entries", len(entries))
+}
+
+func toResult(match *Match) (common.SearchResult, error) {
+	var services []common.SearchServiceMatch
+	if err := json.Unmarshal(match.MatchedServices, &services); err != nil {
+		return common.SearchResult{}, err
+	}
+	for i := range services {
+		services[i].Name = highlight(services[i].Name)
+		services[i].Snippet = highlight(services[i].Snippet)
+	}
+
+	profileImage := ""
+	if match.ProfileImage != nil {
+		profileImage = *match.ProfileImage
+	}
+
+	return common.SearchResult{
+		UserID:          match.UserID,
+		FullName:        match.FullName,
+		ProfileImage:    profileImage,
+		JobDescription:  match.JobDescription,
+		Location:        match.Location,
+		Category:        match.Category,
+		Subcategory:     match.Subcategory,
+		Rank:            match.Rank,
+		Snippet:         highlight(match.Snippet),
+		MatchedServices: services,
+	}, nil
+}
+
+// highlight escapes text written by users and turns the markers set by ts_headline into
+// <mark> tags.
+func highlight(snippet string) string {
+	return highlightReplacer.Replace(html.EscapeString(snippet))
+}
+
+func encodeCursor(rank, ID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(rank + "|" + ID))
+}
+
+func decodeCursor(cursor string) (string, string, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", false
+	}
+
+	rank, ID, found := strings.Cut(string(raw), "|")
+	if !found || rank == "" || ID == "" {
+		return "", "", false
+	}
+
+	// The rank is compared as FLOAT8 in the query, anything else would fail there.
+	value, err := strconv.ParseFloat(rank, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return "", "", false
+	}
+
+	return rank, ID, true
+}
@@ -0,0 +1,194 @@
+package search
+
+import (
+	"conecta-mare-server/internal/common"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	SuggestionCategory     = "category"
+	SuggestionSubcategory  = "subcategory"
+	SuggestionSynonym      = "synonym"
+	SuggestionProfessional = "professional"
+	SuggestionService      = "service"
+)
+
+// suggestionTypeOrder breaks ties between equally good suggestions, broader ones first.
+var suggestionTypeOrder = map[string]int{
+	SuggestionCategory:     0,
+	SuggestionSubcategory:  1,
+	SuggestionSynonym:      2,
+	SuggestionProfessional: 3,
+	SuggestionService:      4,
+}
+
+const (
+	// minSimilarity is the share of the query trigrams an entry needs to contain,
+	// enough for a typo or two in a word.
+	minSimilarity = 0.5
+	// maxPerType keeps professionals and services from crowding out the catalog
+	// while there are other suggestions to show.
+	maxPerType = 3
+)
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+type (
+	suggestionEntry struct {
+		suggestion common.SearchSuggestion
+		// padded is the normalized label between spaces, to look for word prefixes.
+		padded   string
+		trigrams int
+	}
+
+	// suggestionIndex is an immutable trigram index over the catalog. It is rebuilt as
+	// a whole when the catalog changes and swapped in place of the previous one.
+	suggestionIndex struct {
+		entries  []suggestionEntry
+		postings map[string][]int
+	}
+
+	scoredSuggestion struct {
+		entry *suggestionEntry
+		score float64
+	}
+)
+
+func newSuggestionIndex(rows []*SuggestionEntry) *suggestionIndex {
+	index := suggestionIndex{
+		entries:  make([]suggestionEntry, 0, len(rows)),
+		postings: make(map[string][]int),
+	}
+
+	for _, row := range rows {
+		normalized := normalizeSuggestion(row.Label)
+		if normalized == "" {
+			continue
+		}
+
+		grams := trigrams(normalized, true)
+		position := len(index.entries)
+		index.entries = append(index.entries, suggestionEntry{
+			suggestion: common.SearchSuggestion{
+				Type:           row.Type,
+				ID:             row.ID,
+				Label:          row.Label,
+				Detail:         row.Detail,
+				ProfessionalID: row.ProfessionalID,
+			},
+			padded:   " " + normalized + " ",
+			trigrams: len(grams),
+		})
+		for gram := range grams {
+			index.postings[gram] = append(index.postings[gram], position)
+		}
+	}
+
+	return &index
+}
+
+// lookup ranks entries by the share of the query trigrams they contain. Entries with a
+// word starting with the query come first, since the user is probably still typing it.
+func (idx *suggestionIndex) lookup(query string, limit int) []common.SearchSuggestion {
+	normalized := normalizeSuggestion(query)
+	if normalized == "" {
+		return []common.SearchSuggestion{}
+	}
+
+	// The last word may be incomplete, so its closing trigram is left out.
+	grams := trigrams(normalized, false)
+	shared := make(map[int]int)
+	for gram := range grams {
+		for _, position := range idx.postings[gram] {
+			shared[position]++
+		}
+	}
+
+	candidates := make([]scoredSuggestion, 0, len(shared))
+	for position, count := range shared {
+		entry := &idx.entries[position]
+		score := float64(count) / float64(len(grams))
+		if strings.Contains(entry.padded, " "+normalized) {
+			score++
+		} else if score < minSimilarity {
+			continue
+		}
+		candidates = append(candidates, scoredSuggestion{entry: entry, score: score})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if a.entry.suggestion.Type != b.entry.suggestion.Type {
+			return suggestionTypeOrder[a.entry.suggestion.Type] < suggestionTypeOrder[b.entry.suggestion.Type]
+		}
+		if a.entry.trigrams != b.entry.trigrams {
+			return a.entry.trigrams < b.entry.trigrams
+		}
+		return a.entry.suggestion.Label < b.entry.suggestion.Label
+	})
+
+	suggestions := make([]common.SearchSuggestion, 0, limit)
+	perType := make(map[string]int)
+	var deferred []common.SearchSuggestion
+	for _, candidate := range candidates {
+		if len(suggestions) == limit {
+			break
+		}
+		suggestion := candidate.entry.suggestion
+		if perType[suggestion.Type] == maxPerType {
+			deferred = append(deferred, suggestion)
+			continue
+		}
+		perType[suggestion.Type]++
+		suggestions = append(suggestions, suggestion)
+	}
+
+	// Fill whatever is left with the best of the capped types.
+	for _, suggestion := range deferred {
+		if len(suggestions) == limit {
+			break
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions
+}
+
+// normalizeSuggestion lowercases, drops accents and keeps letters and digits as
+// single-space separated words, like search_normalize in the database.
+func normalizeSuggestion(value string) string {
+	value = accentReplacer.Replace(strings.ToLower(value))
+	return strings.Join(strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// trigrams splits every word, padded like pg_trgm does, into its sequences of three
+// characters. closed controls whether the last word gets its closing trigram.
+func trigrams(normalized string, closed bool) map[string]struct{} {
+	grams := make(map[string]struct{})
+	words := strings.Fields(normalized)
+	for i, word := range words {
+		padded := "  " + word
+		if closed || i < len(words)-1 {
+			padded += " "
+		}
+		runes := []rune(padded)
+		for j := 0; j+3 <= len(runes); j++ {
+			grams[string(runes[j:j+3])] = struct{}{}
+		}
+	}
+	return grams
+}
@@ -0,0 +1,83 @@
+package searchterms
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/uid"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxTermLength = 100
+
+// SearchTerm links a word residents use, like "faz-tudo", to a subcategory. The same
+// term can point to several subcategories, one row each.
+type SearchTerm struct {
+	id            string
+	term          string
+	subcategoryID string
+	createdBy     *string
+	createdAt     time.Time
+}
+
+func New(term, subcategoryID string, createdBy *string) (*SearchTerm, error) {
+	searchTerm := SearchTerm{
+		id:            uid.New("searchterm"),
+		term:          strings.Join(strings.Fields(strings.ToLower(term)), " "),
+		subcategoryID: subcategoryID,
+		createdBy:     createdBy,
+		createdAt:     time.Now(),
+	}
+
+	if err := searchTerm.validate(); err != nil {
+		return nil, exceptions.MakeApiError(err)
+	}
+
+	return &searchTerm, nil
+}
+
+func NewFromModel(m models.SearchTerm) *SearchTerm {
+	return &SearchTerm{
+		id:            m.ID,
+		term:          m.Term,
+		subcategoryID: m.SubcategoryID,
+		createdBy:     m.CreatedBy,
+		createdAt:     m.CreatedAt,
+	}
+}
+
+// ToModel leaves the normalized term empty, the database fills it in.
+func (s *SearchTerm) ToModel() models.SearchTerm {
+	return models.SearchTerm{
+		ID:            s.id,
+		Term:          s.term,
+		SubcategoryID: s.subcategoryID,
+		CreatedBy:     s.createdBy,
+		CreatedAt:     s.createdAt,
+	}
+}
+
+func (s *SearchTerm) validate() error {
+	if s.term == "" {
+		return fmt.Errorf("term is required")
+	}
+	if utf8.RuneCountInString(s.term) > maxTermLength {
+		return fmt.Errorf("term must have at most %d characters", maxTermLength)
+	}
+	if strings.IndexFunc(s.term, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0 {
+		return fmt.Errorf("term must contain letters or digits")
+	}
+	if s.subcategoryID == "" {
+		return fmt.Errorf("subcategory_id is required")
+	}
+	return nil
+}
+
+func (s *SearchTerm) ID() string            { return s.id }
+func (s *SearchTerm) Term() string          { return s.term }
+func (s *SearchTerm) SubcategoryID() string { return s.subcategoryID }
+func (s *SearchTerm) CreatedBy() *string    { return s.createdBy }
+func (s *SearchTerm) CreatedAt() time.Time  { return s.createdAt }
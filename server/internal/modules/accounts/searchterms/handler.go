@@ -0,0 +1,125 @@
+package searchterms
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/httphelpers"
+	"conecta-mare-server/pkg/valueobjects"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxImportSize bounds the CSV body of a bulk import.
+const maxImportSize = 1 << 20
+
+var (
+	instance *searchTermsHandler
+	Once     sync.Once
+)
+
+func NewHandler(searchTermsService SearchTermsService, authMiddleware *middlewares.AuthMiddleware) *searchTermsHandler {
+	Once.Do(
+		func() {
+			instance = &searchTermsHandler{
+				searchTermsService: searchTermsService,
+				authMiddleware:     authMiddleware,
+			}
+		},
+	)
+
+	return instance
+}
+
+func (h searchTermsHandler) RegisterRoutes(r *chi.Mux) {
+	m := h.authMiddleware
+	r.Route(
+		"/api/v1/search-terms", func(r chi.Router) {
+			// Public
+			r.Get("/subcategory-suggestions", h.handleSuggestSubcategories)
+
+			// Admin
+			// Moderators are the community agents who collect the terms used locally.
+			r.Group(func(r chi.Router) {
+				r.Use(m.WithAuth, m.RequireRole(valueobjects.Moderator, valueobjects.Admin))
+				r.Get("/", h.handleListSearchTerms)
+				r.Post("/", h.handleCreateSearchTerm)
+				r.Post("/import", h.handleImportSearchTerms)
+				r.Delete("/{search_term_id}", h.handleDeleteSearchTerm)
+			})
+		},
+	)
+}
+
+func (h searchTermsHandler) handleListSearchTerms(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	subcategoryID := httphelpers.ReadQueryString(r.URL.Query(), "subcategory_id", "")
+
+	terms, err := h.searchTermsService.ListSearchTerms(ctx, subcategoryID)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, map[string]any{"search_terms": terms})
+}
+
+func (h searchTermsHandler) handleCreateSearchTerm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.SaveSearchTermRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	terms, err := h.searchTermsService.CreateSearchTerm(ctx, body)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusCreated, map[string]any{"search_terms": terms})
+}
+
+// handleImportSearchTerms takes the CSV file as the raw request body.
+func (h searchTermsHandler) handleImportSearchTerms(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	report, err := h.searchTermsService.ImportSearchTerms(ctx, r.Body)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, report)
+}
+
+func (h searchTermsHandler) handleDeleteSearchTerm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	searchTermID := chi.URLParam(r, "search_term_id")
+
+	if err := h.searchTermsService.DeleteSearchTerm(ctx, searchTermID); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteSuccess(w, http.StatusOK)
+}
+
+func (h searchTermsHandler) handleSuggestSubcategories(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	text := httphelpers.ReadQueryString(r.URL.Query(), "q", "")
+
+	suggestions, err := h.searchTermsService.SuggestSubcategories(ctx, text)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, map[string]any{"subcategories": suggestions})
+}
@@ -0,0 +1,46 @@
+package searchterms
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	// SubcategoryRef is a subcategory found by its id or by its name.
+	SubcategoryRef struct {
+		Ref  string `db:"ref"`
+		ID   string `db:"id"`
+		Name string `db:"name"`
+	}
+
+	SearchTermsRepository interface {
+		List(ctx context.Context, subcategoryID string) ([]common.SearchTerm, error)
+		GetByID(ctx context.Context, id string) (*SearchTerm, error)
+		CreateTx(ctx context.Context, tx *sqlx.Tx, term *SearchTerm) (bool, error)
+		Delete(ctx context.Context, id string) error
+		ResolveSubcategories(ctx context.Context, refs []string) (map[string]SubcategoryRef, error)
+		SuggestSubcategories(ctx context.Context, text string, limit int) ([]common.SubcategorySuggestion, error)
+	}
+	SearchTermsService interface {
+		ListSearchTerms(ctx context.Context, subcategoryID string) ([]common.SearchTerm, *exceptions.ApiError[string])
+		CreateSearchTerm(ctx context.Context, input common.SaveSearchTermRequest) ([]common.SearchTerm, *exceptions.ApiError[string])
+		DeleteSearchTerm(ctx context.Context, id string) *exceptions.ApiError[string]
+		ImportSearchTerms(ctx context.Context, file io.Reader) (*common.SearchTermsImportReport, *exceptions.ApiError[string])
+		SuggestSubcategories(ctx context.Context, text string) ([]common.SubcategorySuggestion, *exceptions.ApiError[string])
+	}
+	searchTermsService struct {
+		db         *sqlx.DB
+		repository SearchTermsRepository
+		logger     *slog.Logger
+	}
+	searchTermsHandler struct {
+		searchTermsService SearchTermsService
+		authMiddleware     *middlewares.AuthMiddleware
+	}
+)
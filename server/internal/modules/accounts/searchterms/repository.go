@@ -0,0 +1,166 @@
+package searchterms
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/databases/postgres/models"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) SearchTermsRepository {
+	return &repository{db}
+}
+
+// List returns the taxonomy ordered by term, optionally for a single subcategory.
+func (r *repository) List(ctx context.Context, subcategoryID string) ([]common.SearchTerm, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT st.id, st.term, st.subcategory_id, sc.name AS subcategory_name, st.created_at
+		FROM search_terms st
+		INNER JOIN subcategories sc ON sc.id = st.subcategory_id
+		WHERE $1 = '' OR st.subcategory_id = $1
+		ORDER BY st.normalized_term, sc.name`
+
+	terms := []common.SearchTerm{}
+	if err := r.db.SelectContext(ctx, &terms, query, subcategoryID); err != nil {
+		return nil, err
+	}
+
+	return terms, nil
+}
+
+func (r *repository) GetByID(ctx context.Context, id string) (*SearchTerm, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var row models.SearchTerm
+	err := r.db.GetContext(ctx, &row, "SELECT * FROM search_terms WHERE id = $1", id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFromModel(row), nil
+}
+
+// CreateTx stores the term unless it is already linked to the subcategory, reporting
+// whether a row was inserted.
+func (r *repository) CreateTx(ctx context.Context, tx *sqlx.Tx, term *SearchTerm) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	result, err := tx.NamedExecContext(
+		ctx,
+		`
+		INSERT INTO search_terms (
+			id, term, normalized_term, subcategory_id, created_by, created_at
+		) VALUES (
+			:id, :term, search_normalize(:term), :subcategory_id, :created_by, :created_at
+		)
+		ON CONFLICT (normalized_term, subcategory_id) DO NOTHING`,
+		term.ToModel(),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return inserted > 0, nil
+}
+
+func (r *repository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM search_terms WHERE id = $1", id)
+	return err
+}
+
+// ResolveSubcategories finds subcategories by id or by name, ignoring case and accents,
+// so imports can say "tecnicos de eletrodomesticos". Refs that match nothing are left
+// out of the map.
+func (r *repository) ResolveSubcategories(ctx context.Context, refs []string) (map[string]SubcategoryRef, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT DISTINCT ON (ref.value) ref.value AS ref, sc.id, sc.name
+		FROM unnest($1::TEXT[]) AS ref (value)
+		INNER JOIN subcategories sc
+				ON sc.id = ref.value OR search_normalize(sc.name) = search_normalize(ref.value)
+		WHERE sc.deleted_at IS NULL
+		ORDER BY ref.value, sc.id = ref.value DESC`
+
+	var rows []SubcategoryRef
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(refs)); err != nil {
+		return nil, err
+	}
+
+	resolved := make(map[string]SubcategoryRef, len(rows))
+	for _, row := range rows {
+		resolved[row.Ref] = row
+	}
+
+	return resolved, nil
+}
+
+// SuggestSubcategories ranks subcategories for a free text, like the job description
+// typed during onboarding. Every taxonomy term found in the text counts as one hit and
+// a word of the subcategory name counts as two.
+func (r *repository) SuggestSubcategories(ctx context.Context, text string, limit int) ([]common.SubcategorySuggestion, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	query := `
+		WITH input AS (
+			SELECT
+					' ' || search_normalize($1) || ' ' AS normalized,
+					replace(plainto_tsquery('portuguese_unaccent', $1)::TEXT, '&', '|')::TSQUERY AS words
+		)
+		SELECT s.id, s.name, s.category_id, s.category_name
+		FROM (
+			SELECT
+					sc.id,
+					sc.name,
+					ca.id AS category_id,
+					ca.name AS category_name,
+					t.hits + CASE WHEN search_subcategory_document(sc.name) @@ i.words THEN 2 ELSE 0 END AS score,
+					t.longest
+			FROM subcategories sc
+			INNER JOIN categories ca ON ca.id = sc.category_id
+			CROSS JOIN input i
+			CROSS JOIN LATERAL (
+				SELECT COUNT(*) AS hits, MAX(length(st.normalized_term)) AS longest
+				FROM search_terms st
+				WHERE st.subcategory_id = sc.id
+						AND i.normalized LIKE '% ' || st.normalized_term || ' %'
+			) t
+			WHERE sc.deleted_at IS NULL
+		) s
+		WHERE s.score > 0
+		ORDER BY s.score DESC, s.longest DESC NULLS LAST, s.name
+		LIMIT $2`
+
+	suggestions := []common.SubcategorySuggestion{}
+	if err := r.db.SelectContext(ctx, &suggestions, query, text, limit); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,323 @@
+package searchterms
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/jwt"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	maxImportRows  = 1000
+	maxSuggestions = 5
+)
+
+func NewService(db *sqlx.DB, repository SearchTermsRepository, logger *slog.Logger) SearchTermsService {
+	return &searchTermsService{
+		db:         db,
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+func (s *searchTermsService) ListSearchTerms(ctx context.Context, subcategoryID string) ([]common.SearchTerm, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to list search terms", "subcategory_id", subcategoryID)
+
+	terms, err := s.repository.List(ctx, subcategoryID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to list search terms", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	return terms, nil
+}
+
+// CreateSearchTerm links the term to every given subcategory. Links that already exist
+// are kept as they are; when all of them exist the request is a conflict.
+func (s *searchTermsService) CreateSearchTerm(ctx context.Context, input common.SaveSearchTermRequest) ([]common.SearchTerm, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to create search term", "term", input.Term)
+
+	userID, apiErr := s.currentUserID(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	if len(input.SubcategoryIDs) == 0 {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, fmt.Errorf("subcategory_ids is required"))
+	}
+
+	subcategories, err := s.repository.ResolveSubcategories(ctx, input.SubcategoryIDs)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to resolve subcategories", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	terms := make([]*SearchTerm, 0, len(input.SubcategoryIDs))
+	for _, subcategoryID := range input.SubcategoryIDs {
+		subcategory, ok := subcategories[subcategoryID]
+		if !ok || subcategory.ID != subcategoryID {
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrSubcategoryNotFound)
+		}
+
+		term, err := New(input.Term, subcategoryID, &userID)
+		if err != nil {
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		}
+		terms = append(terms, term)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while starting transaction", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	defer tx.Rollback()
+
+	created := make([]common.SearchTerm, 0, len(terms))
+	for _, term := range terms {
+		inserted, err := s.repository.CreateTx(ctx, tx, term)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to create search term", "term", term.Term(), "err", err)
+			return nil, exceptions.MakeGenericApiError()
+		}
+		if inserted {
+			created = append(created, toResponse(term, subcategories[term.SubcategoryID()].Name))
+		}
+	}
+
+	if len(created) == 0 {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusConflict, exceptions.ErrSearchTermTaken)
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.ErrorContext(ctx, "error while commiting search terms", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "search term created with success", "term", input.Term, "count", len(created))
+	return created, nil
+}
+
+func (s *searchTermsService) DeleteSearchTerm(ctx context.Context, id string) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to delete search term", "search_term_id", id)
+
+	term, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get search term", "search_term_id", id, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if term == nil {
+		return exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrSearchTermNotFound)
+	}
+
+	if err := s.repository.Delete(ctx, id); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to delete search term", "search_term_id", id, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "search term deleted with success", "search_term_id", id)
+	return nil
+}
+
+// ImportSearchTerms reads a CSV with a term and its subcategories per row, e.g.
+//
+//	term,subcategory
+//	faz-tudo,Pedreiros;Pintores;Eletricistas
+//	unha,Manicures
+//
+// Subcategories are given by id or name and separated by semicolons, the header row
+// is optional and lines starting with # are ignored. Invalid rows are reported back
+// and do not stop the valid ones from being imported.
+func (s *searchTermsService) ImportSearchTerms(ctx context.Context, file io.Reader) (*common.SearchTermsImportReport, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to import search terms")
+
+	userID, apiErr := s.currentUserID(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	rows, err := readImportRows(file)
+	if err != nil {
+		s.logger.WarnContext(ctx, "invalid search terms import file", "err", err)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidImportFile)
+	}
+
+	refs := []string{}
+	for _, row := range rows {
+		refs = append(refs, row.subcategories...)
+	}
+
+	subcategories, err := s.repository.ResolveSubcategories(ctx, refs)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to resolve subcategories", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while starting transaction", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	defer tx.Rollback()
+
+	report := common.SearchTermsImportReport{Errors: []common.SearchTermsImportError{}}
+	for _, row := range rows {
+		if row.err != nil {
+			report.Errors = append(report.Errors, common.SearchTermsImportError{Line: row.line, Message: row.err.Error()})
+			continue
+		}
+
+		for _, ref := range row.subcategories {
+			subcategory, ok := subcategories[ref]
+			if !ok {
+				report.Errors = append(report.Errors, common.SearchTermsImportError{
+					Line:    row.line,
+					Message: fmt.Sprintf("subcategory %q not found", ref),
+				})
+				continue
+			}
+
+			term, err := New(row.term, subcategory.ID, &userID)
+			if err != nil {
+				var apiErr *exceptions.ApiError[string]
+				message := err.Error()
+				if errors.As(err, &apiErr) {
+					message = apiErr.Errors["message"]
+				}
+				report.Errors = append(report.Errors, common.SearchTermsImportError{Line: row.line, Message: message})
+				continue
+			}
+
+			inserted, err := s.repository.CreateTx(ctx, tx, term)
+			if err != nil {
+				s.logger.ErrorContext(ctx, "error while attempting to import search term", "line", row.line, "err", err)
+				return nil, exceptions.MakeGenericApiError()
+			}
+			if inserted {
+				report.Created++
+			} else {
+				report.Skipped++
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.ErrorContext(ctx, "error while commiting search terms import", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(
+		ctx,
+		"search terms imported with success",
+		"created", report.Created,
+		"skipped", report.Skipped,
+		"errors", len(report.Errors),
+	)
+	return &report, nil
+}
+
+// SuggestSubcategories guesses the subcategory of a professional from what they say
+// they do, so onboarding can offer a few choices instead of the whole list.
+func (s *searchTermsService) SuggestSubcategories(ctx context.Context, text string) ([]common.SubcategorySuggestion, *exceptions.ApiError[string]) {
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > 100 {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidSearchQuery)
+	}
+
+	suggestions, err := s.repository.SuggestSubcategories(ctx, text, maxSuggestions)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to suggest subcategories", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	return suggestions, nil
+}
+
+func (s *searchTermsService) currentUserID(ctx context.Context) (string, *exceptions.ApiError[string]) {
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return "", exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+	return c.UserID, nil
+}
+
+type importRow struct {
+	line          int
+	term          string
+	subcategories []string
+	err           error
+}
+
+// readImportRows parses the file up front so a malformed CSV is rejected as a whole
+// before anything is written.
+func readImportRows(file io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	rows := []importRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(rows) == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "term") {
+			continue
+		}
+		if len(rows) == maxImportRows {
+			return nil, fmt.Errorf("more than %d rows", maxImportRows)
+		}
+
+		row := importRow{line: line}
+		if len(record) != 2 || strings.TrimSpace(record[0]) == "" {
+			row.err = fmt.Errorf("expected a term and its subcategories")
+			rows = append(rows, row)
+			continue
+		}
+
+		row.term = record[0]
+		for _, ref := range strings.Split(record[1], ";") {
+			if ref = strings.TrimSpace(ref); ref != "" {
+				row.subcategories = append(row.subcategories, ref)
+			}
+		}
+		if len(row.subcategories) == 0 {
+			row.err = fmt.Errorf("expected a term and its subcategories")
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("file has no rows")
+	}
+
+	return rows, nil
+}
+
+func toResponse(term *SearchTerm, subcategoryName string) common.SearchTerm {
+	return common.SearchTerm{
+		ID:              term.ID(),
+		Term:            term.Term(),
+		SubcategoryID:   term.SubcategoryID(),
+		SubcategoryName: subcategoryName,
+		CreatedAt:       term.CreatedAt(),
+	}
+}
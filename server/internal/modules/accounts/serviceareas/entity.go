@@ -0,0 +1,74 @@
+package serviceareas
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/uid"
+	"fmt"
+	"time"
+)
+
+// ServiceArea is a community where a professional takes jobs, besides the one they
+// live in. The travel surcharge uses the same unit as service prices.
+type ServiceArea struct {
+	id              string
+	userProfileID   string
+	communityID     string
+	travelSurcharge *int
+	createdAt       time.Time
+}
+
+func New(userProfileID, communityID string, travelSurcharge *int) (*ServiceArea, error) {
+	area := ServiceArea{
+		id:              uid.New("servicearea"),
+		userProfileID:   userProfileID,
+		communityID:     communityID,
+		travelSurcharge: travelSurcharge,
+		createdAt:       time.Now(),
+	}
+
+	if err := area.validate(); err != nil {
+		return nil, exceptions.MakeApiError(err)
+	}
+
+	return &area, nil
+}
+
+func NewFromModel(m models.ServiceArea) *ServiceArea {
+	return &ServiceArea{
+		id:              m.ID,
+		userProfileID:   m.UserProfileID,
+		communityID:     m.CommunityID,
+		travelSurcharge: m.TravelSurcharge,
+		createdAt:       m.CreatedAt,
+	}
+}
+
+func (s *ServiceArea) ToModel() models.ServiceArea {
+	return models.ServiceArea{
+		ID:              s.id,
+		UserProfileID:   s.userProfileID,
+		CommunityID:     s.communityID,
+		TravelSurcharge: s.travelSurcharge,
+		CreatedAt:       s.createdAt,
+	}
+}
+
+func (s *ServiceArea) validate() error {
+	if s.userProfileID == "" {
+		return fmt.Errorf("user_profile_id is required")
+	}
+	if s.communityID == "" {
+		return fmt.Errorf("community_id is required")
+	}
+	if s.travelSurcharge != nil && *s.travelSurcharge < 0 {
+		return fmt.Errorf("travel_surcharge cannot be negative")
+	}
+	return nil
+}
+
+func (s *ServiceArea) ID() string            { return s.id }
+func (s *ServiceArea) UserProfileID() string { return s.userProfileID }
+func (s *ServiceArea) CommunityID() string   { return s.communityID }
+func (s *ServiceArea) TravelSurcharge() *int { return s.travelSurcharge }
+func (s *ServiceArea) CreatedAt() time.Time  { return s.createdAt }
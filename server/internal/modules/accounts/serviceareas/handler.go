@@ -0,0 +1,76 @@
+package serviceareas
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/httphelpers"
+	"conecta-mare-server/pkg/valueobjects"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+var (
+	instance *serviceAreasHandler
+	Once     sync.Once
+)
+
+func NewHandler(serviceAreasService ServiceAreasService, authMiddleware *middlewares.AuthMiddleware) *serviceAreasHandler {
+	Once.Do(
+		func() {
+			instance = &serviceAreasHandler{
+				serviceAreasService: serviceAreasService,
+				authMiddleware:      authMiddleware,
+			}
+		},
+	)
+
+	return instance
+}
+
+func (h serviceAreasHandler) RegisterRoutes(r *chi.Mux) {
+	m := h.authMiddleware
+	r.Route(
+		"/api/v1/me/service-areas", func(r chi.Router) {
+			// Private
+			r.Group(func(r chi.Router) {
+				r.Use(m.WithAuth, m.RequireRole(valueobjects.Professional))
+				r.Get("/", h.handleGetMyServiceAreas)
+				r.Put("/", h.handleReplaceMyServiceAreas)
+			})
+		},
+	)
+}
+
+func (h serviceAreasHandler) handleGetMyServiceAreas(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	areas, err := h.serviceAreasService.GetMyServiceAreas(ctx)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, map[string]any{"service_areas": areas})
+}
+
+func (h serviceAreasHandler) handleReplaceMyServiceAreas(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.SaveServiceAreasRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	areas, err := h.serviceAreasService.ReplaceMyServiceAreas(ctx, body)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, map[string]any{"service_areas": areas})
+}
@@ -0,0 +1,35 @@
+package serviceareas
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/modules/accounts/communities"
+	"conecta-mare-server/internal/modules/accounts/userprofiles"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"context"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	ServiceAreasRepository interface {
+		GetByUserProfileID(ctx context.Context, userProfileID string) ([]*ServiceArea, error)
+		ReplaceTx(ctx context.Context, tx *sqlx.Tx, userProfileID string, areas []*ServiceArea) error
+	}
+	ServiceAreasService interface {
+		GetMyServiceAreas(ctx context.Context) ([]common.ServiceArea, *exceptions.ApiError[string])
+		ReplaceMyServiceAreas(ctx context.Context, input common.SaveServiceAreasRequest) ([]common.ServiceArea, *exceptions.ApiError[string])
+	}
+	serviceAreasService struct {
+		db               *sqlx.DB
+		repository       ServiceAreasRepository
+		communitiesRepo  communities.CommunitiesRepository
+		userProfilesRepo userprofiles.UserProfilesRepository
+		logger           *slog.Logger
+	}
+	serviceAreasHandler struct {
+		serviceAreasService ServiceAreasService
+		authMiddleware      *middlewares.AuthMiddleware
+	}
+)
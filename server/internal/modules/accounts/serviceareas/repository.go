@@ -0,0 +1,68 @@
+package serviceareas
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) ServiceAreasRepository {
+	return &repository{db}
+}
+
+func (r *repository) GetByUserProfileID(ctx context.Context, userProfileID string) ([]*ServiceArea, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var rows []models.ServiceArea
+	err := r.db.SelectContext(
+		ctx,
+		&rows,
+		"SELECT * FROM service_areas WHERE user_profile_id = $1 ORDER BY created_at, id",
+		userProfileID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	areas := make([]*ServiceArea, 0, len(rows))
+	for _, row := range rows {
+		areas = append(areas, NewFromModel(row))
+	}
+
+	return areas, nil
+}
+
+// ReplaceTx swaps every service area of the profile for the given ones.
+func (r *repository) ReplaceTx(ctx context.Context, tx *sqlx.Tx, userProfileID string, areas []*ServiceArea) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM service_areas WHERE user_profile_id = $1", userProfileID); err != nil {
+		return err
+	}
+
+	for _, area := range areas {
+		_, err := tx.NamedExecContext(
+			ctx,
+			`
+			INSERT INTO service_areas (
+				id, user_profile_id, community_id, travel_surcharge, created_at
+			) VALUES (
+				:id, :user_profile_id, :community_id, :travel_surcharge, :created_at
+			)`,
+			area.ToModel(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package serviceareas
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/modules/accounts/communities"
+	"conecta-mare-server/internal/modules/accounts/userprofiles"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/jwt"
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func NewService(
+	db *sqlx.DB,
+	repository ServiceAreasRepository,
+	communitiesRepo communities.CommunitiesRepository,
+	userProfilesRepo userprofiles.UserProfilesRepository,
+	logger *slog.Logger,
+) ServiceAreasService {
+	return &serviceAreasService{
+		db:               db,
+		repository:       repository,
+		communitiesRepo:  communitiesRepo,
+		userProfilesRepo: userProfilesRepo,
+		logger:           logger,
+	}
+}
+
+func (s *serviceAreasService) GetMyServiceAreas(ctx context.Context) ([]common.ServiceArea, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to get own service areas")
+
+	profileID, apiErr := s.currentProfileID(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	areas, err := s.repository.GetByUserProfileID(ctx, profileID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get service areas", "user_profile_id", profileID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	names, apiErr := s.communityNames(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return toResponses(areas, names), nil
+}
+
+// ReplaceMyServiceAreas stores the full list of communities the professional serves.
+// An empty list leaves only the home community.
+func (s *serviceAreasService) ReplaceMyServiceAreas(ctx context.Context, input common.SaveServiceAreasRequest) ([]common.ServiceArea, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to replace own service areas")
+
+	profileID, apiErr := s.currentProfileID(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	names, apiErr := s.communityNames(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	areas := make([]*ServiceArea, 0, len(input.Areas))
+	seen := make(map[string]struct{}, len(input.Areas))
+	for _, item := range input.Areas {
+		if _, ok := names[item.CommunityID]; !ok {
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrCommunityNotFound)
+		}
+		if _, ok := seen[item.CommunityID]; ok {
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrDuplicateServiceArea)
+		}
+		seen[item.CommunityID] = struct{}{}
+
+		area, err := New(profileID, item.CommunityID, item.TravelSurcharge)
+		if err != nil {
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		}
+		areas = append(areas, area)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while starting transaction", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	defer tx.Rollback()
+
+	if err := s.repository.ReplaceTx(ctx, tx, profileID, areas); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to replace service areas", "user_profile_id", profileID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.ErrorContext(ctx, "error while commiting service areas", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "service areas replaced with success", "user_profile_id", profileID, "count", len(areas))
+	return toResponses(areas, names), nil
+}
+
+func (s *serviceAreasService) currentProfileID(ctx context.Context) (string, *exceptions.ApiError[string]) {
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return "", exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	userProfile, err := s.userProfilesRepo.FindByUserID(ctx, c.UserID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to find user profile", "user_id", c.UserID, "err", err)
+		return "", exceptions.MakeGenericApiError()
+	}
+	if userProfile == nil {
+		s.logger.WarnContext(ctx, "user profile not found", "user_id", c.UserID)
+		return "", exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrUserProfileNotFound)
+	}
+
+	return userProfile.ID(), nil
+}
+
+// communityNames maps every community id to its name. There are only a handful of
+// communities, so loading them all is cheaper than one lookup per area.
+func (s *serviceAreasService) communityNames(ctx context.Context) (map[string]string, *exceptions.ApiError[string]) {
+	communities, err := s.communitiesRepo.GetCommunities(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get communities", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	names := make(map[string]string, len(communities))
+	for _, community := range communities {
+		names[community.ID] = community.Name
+	}
+
+	return names, nil
+}
+
+func toResponses(areas []*ServiceArea, names map[string]string) []common.ServiceArea {
+	responses := make([]common.ServiceArea, 0, len(areas))
+	for _, area := range areas {
+		responses = append(responses, common.ServiceArea{
+			CommunityID:     area.CommunityID(),
+			CommunityName:   names[area.CommunityID()],
+			TravelSurcharge: area.TravelSurcharge(),
+		})
+	}
+	return responses
+}
@@ -1,7 +1,17 @@
 package serviceimages
 
-import "github.com/jmoiron/sqlx"
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
 
 type ServiceImagesRepository interface {
 	CreateTx(tx *sqlx.Tx, serviceImg *ServiceImage) error
+	Create(ctx context.Context, serviceImg *ServiceImage) error
+	GetByID(ctx context.Context, ID string) (*ServiceImage, error)
+	GetByServiceID(ctx context.Context, serviceID string) ([]*ServiceImage, error)
+	GetByServiceIDs(ctx context.Context, serviceIDs []string) ([]*ServiceImage, error)
+	Delete(ctx context.Context, ID string) error
+	UpdateOrderingTx(ctx context.Context, tx *sqlx.Tx, ID string, ordering int) error
 }
@@ -1,6 +1,12 @@
 package serviceimages
 
 import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/jmoiron/sqlx"
 )
 
@@ -12,9 +18,7 @@ func NewRepository(db *sqlx.DB) ServiceImagesRepository {
 	return &repository{db}
 }
 
-func (r *repository) CreateTx(tx *sqlx.Tx, serviceImg *ServiceImage) error {
-	model := serviceImg.ToModel()
-	query := `
+const insertQuery = `
 		INSERT INTO service_images (
 				id, service_id, url, ordering, created_at
 			) VALUES (
@@ -22,6 +26,82 @@ func (r *repository) CreateTx(tx *sqlx.Tx, serviceImg *ServiceImage) error {
 		)
 	`
 
-	_, err := tx.NamedExec(query, model)
+func (r *repository) CreateTx(tx *sqlx.Tx, serviceImg *ServiceImage) error {
+	model := serviceImg.ToModel()
+
+	_, err := tx.NamedExec(insertQuery, model)
+	return err
+}
+
+func (r *repository) Create(ctx context.Context, serviceImg *ServiceImage) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.NamedExecContext(ctx, insertQuery, serviceImg.ToModel())
+	return err
+}
+
+func (r *repository) GetByID(ctx context.Context, ID string) (*ServiceImage, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var model models.ServiceImage
+	err := r.db.GetContext(ctx, &model, "SELECT * FROM service_images WHERE id = $1", ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFromModel(model), nil
+}
+
+func (r *repository) GetByServiceID(ctx context.Context, serviceID string) ([]*ServiceImage, error) {
+	return r.GetByServiceIDs(ctx, []string{serviceID})
+}
+
+func (r *repository) GetByServiceIDs(ctx context.Context, serviceIDs []string) ([]*ServiceImage, error) {
+	if len(serviceIDs) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	query, args, err := sqlx.In(
+		"SELECT * FROM service_images WHERE service_id IN (?) ORDER BY service_id, ordering, created_at",
+		serviceIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []models.ServiceImage
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	images := make([]*ServiceImage, 0, len(rows))
+	for _, row := range rows {
+		images = append(images, NewFromModel(row))
+	}
+
+	return images, nil
+}
+
+func (r *repository) Delete(ctx context.Context, ID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM service_images WHERE id = $1", ID)
+	return err
+}
+
+func (r *repository) UpdateOrderingTx(ctx context.Context, tx *sqlx.Tx, ID string, ordering int) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "UPDATE service_images SET ordering = $1 WHERE id = $2", ordering, ID)
 	return err
 }
@@ -2,6 +2,9 @@ package services
 
 import (
 	"conecta-mare-server/internal/databases/postgres/models"
+	"conecta-mare-server/pkg/exceptions"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -12,6 +15,7 @@ type Service struct {
 	description      string
 	price            int
 	ownLocationPrice *int
+	ordering         int
 	createdAt        time.Time
 	updatedAt        *time.Time
 	deletedAt        *time.Time
@@ -24,19 +28,25 @@ func New(
 	description string,
 	price int,
 	ownLocationPrice *int,
+	ordering int,
 ) (*Service, error) {
 	service := Service{
 		id:               id,
 		userProfileID:    userProfileID,
-		name:             name,
-		description:      description,
+		name:             strings.TrimSpace(name),
+		description:      strings.TrimSpace(description),
 		price:            price,
 		ownLocationPrice: ownLocationPrice,
+		ordering:         ordering,
 		createdAt:        time.Now(),
 		updatedAt:        nil,
 		deletedAt:        nil,
 	}
 
+	if err := service.validate(); err != nil {
+		return nil, exceptions.MakeApiError(err)
+	}
+
 	return &service, nil
 }
 
@@ -48,6 +58,7 @@ func NewFromModel(m models.Service) *Service {
 		description:      m.Description,
 		price:            m.Price,
 		ownLocationPrice: m.OwnLocationPrice,
+		ordering:         m.Ordering,
 		createdAt:        m.CreatedAt,
 		updatedAt:        m.UpdatedAt,
 		deletedAt:        m.DeletedAt,
@@ -62,15 +73,52 @@ func (s *Service) ToModel() models.Service {
 		Description:      s.description,
 		Price:            s.price,
 		OwnLocationPrice: s.ownLocationPrice,
+		Ordering:         s.ordering,
 		CreatedAt:        s.createdAt,
 		UpdatedAt:        s.updatedAt,
 		DeletedAt:        s.deletedAt,
 	}
 }
 
+func (s *Service) Update(name, description string, price int, ownLocationPrice *int) error {
+	s.name = strings.TrimSpace(name)
+	s.description = strings.TrimSpace(description)
+	s.price = price
+	s.ownLocationPrice = ownLocationPrice
+
+	now := time.Now()
+	s.updatedAt = &now
+
+	return s.validate()
+}
+
+func (s *Service) Delete() {
+	now := time.Now()
+	s.deletedAt = &now
+	s.updatedAt = &now
+}
+
+func (s *Service) validate() error {
+	if s.userProfileID == "" {
+		return fmt.Errorf("user_profile_id is required")
+	}
+	if s.name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if s.price < 0 {
+		return fmt.Errorf("price cannot be negative")
+	}
+	if s.ownLocationPrice != nil && *s.ownLocationPrice < 0 {
+		return fmt.Errorf("own_location_price cannot be negative")
+	}
+	return nil
+}
+
 func (s *Service) ID() string             { return s.id }
 func (s *Service) UserProfileID() string  { return s.userProfileID }
 func (s *Service) Name() string           { return s.name }
 func (s *Service) Description() string    { return s.description }
 func (s *Service) Price() int             { return s.price }
 func (s *Service) OwnLocationPrice() *int { return s.ownLocationPrice }
+func (s *Service) Ordering() int          { return s.ordering }
+func (s *Service) IsDeleted() bool        { return s.deletedAt != nil }
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/httphelpers"
+	"conecta-mare-server/pkg/valueobjects"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxImagesUploadSize bounds the multipart body kept in memory while parsing.
+const maxImagesUploadSize = 32 << 20
+
+var (
+	instance *servicesHandler
+	Once     sync.Once
+)
+
+func NewHandler(servicesService ServicesService, authMiddleware *middlewares.AuthMiddleware) *servicesHandler {
+	Once.Do(
+		func() {
+			instance = &servicesHandler{
+				servicesService: servicesService,
+				authMiddleware:  authMiddleware,
+			}
+		},
+	)
+
+	return instance
+}
+
+func (h servicesHandler) RegisterRoutes(r *chi.Mux) {
+	m := h.authMiddleware
+	r.Route(
+		"/api/v1/me/services", func(r chi.Router) {
+			// Private
+			r.Group(func(r chi.Router) {
+				r.Use(m.WithAuth, m.RequireRole(valueobjects.Professional))
+				r.Get("/", h.handleGetMyServices)
+				r.Post("/", h.handleCreateService)
+				r.Put("/order", h.handleReorderServices)
+				r.Put("/{service_id}", h.handleUpdateService)
+				r.Delete("/{service_id}", h.handleDeleteService)
+				r.Post("/{service_id}/images", h.handleAddImages)
+				r.Put("/{service_id}/images/order", h.handleReorderImages)
+				r.Delete("/{service_id}/images/{image_id}", h.handleRemoveImage)
+			})
+		},
+	)
+}
+
+func (h servicesHandler) handleGetMyServices(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	services, err := h.servicesService.GetMyServices(ctx)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, map[string]any{"services": services})
+}
+
+func (h servicesHandler) handleCreateService(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.SaveServiceRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	service, err := h.servicesService.CreateService(ctx, body)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusCreated, service)
+}
+
+func (h servicesHandler) handleUpdateService(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	serviceID := chi.URLParam(r, "service_id")
+
+	var body common.SaveServiceRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	service, err := h.servicesService.UpdateService(ctx, serviceID, body)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, service)
+}
+
+func (h servicesHandler) handleDeleteService(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	serviceID := chi.URLParam(r, "service_id")
+
+	if err := h.servicesService.DeleteService(ctx, serviceID); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteSuccess(w, http.StatusOK)
+}
+
+func (h servicesHandler) handleReorderServices(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.ReorderRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	services, err := h.servicesService.ReorderServices(ctx, body)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, map[string]any{"services": services})
+}
+
+// handleAddImages takes one or more files in the multipart field "images".
+func (h servicesHandler) handleAddImages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	serviceID := chi.URLParam(r, "service_id")
+
+	if err := r.ParseMultipartForm(maxImagesUploadSize); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidRequestBody)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	service, err := h.servicesService.AddImages(ctx, serviceID, r.MultipartForm.File["images"])
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusCreated, service)
+}
+
+func (h servicesHandler) handleReorderImages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	serviceID := chi.URLParam(r, "service_id")
+
+	var body common.ReorderRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	service, err := h.servicesService.ReorderImages(ctx, serviceID, body)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, service)
+}
+
+func (h servicesHandler) handleRemoveImage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	serviceID := chi.URLParam(r, "service_id")
+	imageID := chi.URLParam(r, "image_id")
+
+	if err := h.servicesService.RemoveImage(ctx, serviceID, imageID); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteSuccess(w, http.StatusOK)
+}
@@ -1,7 +1,48 @@
 package services
 
-import "github.com/jmoiron/sqlx"
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/modules/accounts/serviceimages"
+	"conecta-mare-server/internal/modules/accounts/userprofiles"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/storage"
+	"context"
+	"log/slog"
+	"mime/multipart"
 
-type ServicesRepository interface {
-	CreateTx(tx *sqlx.Tx, service *Service) error
-}
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	ServicesRepository interface {
+		CreateTx(tx *sqlx.Tx, service *Service) error
+		Create(ctx context.Context, service *Service) error
+		GetByID(ctx context.Context, ID string) (*Service, error)
+		GetByUserProfileID(ctx context.Context, userProfileID string) ([]*Service, error)
+		Update(ctx context.Context, service *Service) error
+		UpdateOrderingTx(ctx context.Context, tx *sqlx.Tx, ID string, ordering int) error
+	}
+	ServicesService interface {
+		GetMyServices(ctx context.Context) ([]common.Service, *exceptions.ApiError[string])
+		CreateService(ctx context.Context, input common.SaveServiceRequest) (*common.Service, *exceptions.ApiError[string])
+		UpdateService(ctx context.Context, ID string, input common.SaveServiceRequest) (*common.Service, *exceptions.ApiError[string])
+		DeleteService(ctx context.Context, ID string) *exceptions.ApiError[string]
+		ReorderServices(ctx context.Context, input common.ReorderRequest) ([]common.Service, *exceptions.ApiError[string])
+		AddImages(ctx context.Context, ID string, files []*multipart.FileHeader) (*common.Service, *exceptions.ApiError[string])
+		RemoveImage(ctx context.Context, ID, imageID string) *exceptions.ApiError[string]
+		ReorderImages(ctx context.Context, ID string, input common.ReorderRequest) (*common.Service, *exceptions.ApiError[string])
+	}
+	servicesService struct {
+		db               *sqlx.DB
+		repository       ServicesRepository
+		imagesRepo       serviceimages.ServiceImagesRepository
+		userProfilesRepo userprofiles.UserProfilesRepository
+		storageClient    *storage.StorageClient
+		logger           *slog.Logger
+	}
+	servicesHandler struct {
+		servicesService ServicesService
+		authMiddleware  *middlewares.AuthMiddleware
+	}
+)
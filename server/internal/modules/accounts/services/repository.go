@@ -1,6 +1,12 @@
 package services
 
 import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/jmoiron/sqlx"
 )
 
@@ -12,15 +18,94 @@ func NewRepository(db *sqlx.DB) ServicesRepository {
 	return &repository{db}
 }
 
-func (r *repository) CreateTx(tx *sqlx.Tx, service *Service) error {
-	model := service.ToModel()
-	query := `
+const insertQuery = `
 		INSERT INTO services (
-				id, user_profile_id, name, description, price, own_location_price, created_at
+				id, user_profile_id, name, description, price, own_location_price, ordering, created_at
 			) VALUES (
-				:id, :user_profile_id, :name, :description, :price, :own_location_price, :created_at
+				:id, :user_profile_id, :name, :description, :price, :own_location_price, :ordering, :created_at
 		)`
 
-	_, err := tx.NamedExec(query, model)
+func (r *repository) CreateTx(tx *sqlx.Tx, service *Service) error {
+	model := service.ToModel()
+
+	_, err := tx.NamedExec(insertQuery, model)
+	return err
+}
+
+func (r *repository) Create(ctx context.Context, service *Service) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.NamedExecContext(ctx, insertQuery, service.ToModel())
+	return err
+}
+
+func (r *repository) GetByID(ctx context.Context, ID string) (*Service, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var model models.Service
+	err := r.db.GetContext(ctx, &model, "SELECT * FROM services WHERE id = $1", ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFromModel(model), nil
+}
+
+// GetByUserProfileID returns the services that were not deleted, in display order.
+func (r *repository) GetByUserProfileID(ctx context.Context, userProfileID string) ([]*Service, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var rows []models.Service
+	err := r.db.SelectContext(
+		ctx,
+		&rows,
+		`
+		SELECT * FROM services
+		WHERE user_profile_id = $1 AND deleted_at IS NULL
+		ORDER BY ordering, created_at
+		`,
+		userProfileID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	services := make([]*Service, 0, len(rows))
+	for _, row := range rows {
+		services = append(services, NewFromModel(row))
+	}
+
+	return services, nil
+}
+
+func (r *repository) Update(ctx context.Context, service *Service) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE services SET
+			name = :name,
+			description = :description,
+			price = :price,
+			own_location_price = :own_location_price,
+			updated_at = :updated_at,
+			deleted_at = :deleted_at
+		WHERE id = :id`
+
+	_, err := r.db.NamedExecContext(ctx, query, service.ToModel())
+	return err
+}
+
+func (r *repository) UpdateOrderingTx(ctx context.Context, tx *sqlx.Tx, ID string, ordering int) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "UPDATE services SET ordering = $1 WHERE id = $2", ordering, ID)
 	return err
 }
@@ -0,0 +1,470 @@
+package services
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/modules/accounts/serviceimages"
+	"conecta-mare-server/internal/modules/accounts/userprofiles"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/jwt"
+	"conecta-mare-server/pkg/storage"
+	"conecta-mare-server/pkg/uid"
+	"conecta-mare-server/pkg/utils"
+	"context"
+	"fmt"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// maxImagesPerService bounds how many pictures a single service can show.
+const maxImagesPerService = 10
+
+func NewService(
+	db *sqlx.DB,
+	repository ServicesRepository,
+	imagesRepo serviceimages.ServiceImagesRepository,
+	userProfilesRepo userprofiles.UserProfilesRepository,
+	storageClient *storage.StorageClient,
+	logger *slog.Logger,
+) ServicesService {
+	return &servicesService{
+		db:               db,
+		repository:       repository,
+		imagesRepo:       imagesRepo,
+		userProfilesRepo: userProfilesRepo,
+		storageClient:    storageClient,
+		logger:           logger,
+	}
+}
+
+func (s *servicesService) GetMyServices(ctx context.Context) ([]common.Service, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to get own services")
+
+	_, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	services, err := s.repository.GetByUserProfileID(ctx, profileID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get services", "user_profile_id", profileID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	return s.toResponses(ctx, services)
+}
+
+// CreateService adds a service at the end of the catalog. Images are sent
+// afterwards through AddImages.
+func (s *servicesService) CreateService(ctx context.Context, input common.SaveServiceRequest) (*common.Service, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to create service")
+
+	_, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	existing, err := s.repository.GetByUserProfileID(ctx, profileID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get services", "user_profile_id", profileID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	ordering := 0
+	if len(existing) > 0 {
+		ordering = existing[len(existing)-1].Ordering() + 1
+	}
+
+	service, err := New(
+		uid.New("service"),
+		profileID,
+		input.Name,
+		input.Description,
+		input.Price,
+		input.OwnLocationPrice,
+		ordering,
+	)
+	if err != nil {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+	}
+
+	if err := s.repository.Create(ctx, service); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to create service", "user_profile_id", profileID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "service created with success", "service_id", service.ID())
+	return s.toResponse(ctx, service)
+}
+
+func (s *servicesService) UpdateService(ctx context.Context, ID string, input common.SaveServiceRequest) (*common.Service, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to update service", "service_id", ID)
+
+	_, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	service, apiErr := s.getOwnedService(ctx, profileID, ID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	if err := service.Update(input.Name, input.Description, input.Price, input.OwnLocationPrice); err != nil {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+	}
+
+	if err := s.repository.Update(ctx, service); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to update service", "service_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "service updated with success", "service_id", ID)
+	return s.toResponse(ctx, service)
+}
+
+// DeleteService hides a service from the catalog. The row and its images are kept,
+// deleted_at marks it as removed.
+func (s *servicesService) DeleteService(ctx context.Context, ID string) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to delete service", "service_id", ID)
+
+	_, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	service, apiErr := s.getOwnedService(ctx, profileID, ID)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	service.Delete()
+
+	if err := s.repository.Update(ctx, service); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to delete service", "service_id", ID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "service deleted with success", "service_id", ID)
+	return nil
+}
+
+func (s *servicesService) ReorderServices(ctx context.Context, input common.ReorderRequest) ([]common.Service, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to reorder services")
+
+	_, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	services, err := s.repository.GetByUserProfileID(ctx, profileID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get services", "user_profile_id", profileID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	byID := make(map[string]*Service, len(services))
+	for _, service := range services {
+		byID[service.ID()] = service
+	}
+	if !utils.IsPermutation(input.IDs, byID) {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidOrdering)
+	}
+
+	if apiErr := s.applyOrdering(ctx, input.IDs, s.repository.UpdateOrderingTx); apiErr != nil {
+		return nil, apiErr
+	}
+
+	reordered := make([]*Service, 0, len(input.IDs))
+	for _, ID := range input.IDs {
+		reordered = append(reordered, byID[ID])
+	}
+
+	s.logger.InfoContext(ctx, "services reordered with success", "user_profile_id", profileID)
+	return s.toResponses(ctx, reordered)
+}
+
+// AddImages uploads pictures and appends them after the images the service already has.
+func (s *servicesService) AddImages(ctx context.Context, ID string, files []*multipart.FileHeader) (*common.Service, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to add service images", "service_id", ID)
+
+	if len(files) == 0 {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrImagesRequired)
+	}
+	for _, file := range files {
+		if !storage.IsImage(file) {
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidImage)
+		}
+	}
+
+	userID, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	service, apiErr := s.getOwnedService(ctx, profileID, ID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	existing, err := s.imagesRepo.GetByServiceID(ctx, ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get service images", "service_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if len(existing)+len(files) > maxImagesPerService {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrTooManyImages)
+	}
+
+	ordering := 0
+	if len(existing) > 0 {
+		ordering = existing[len(existing)-1].Ordering() + 1
+	}
+
+	var uploaded []string
+	var images []*serviceimages.ServiceImage
+	for i, file := range files {
+		imageID := uid.New("service_img")
+		objectName := fmt.Sprintf("services/%s/%s/%s", userID, ID, imageID)
+
+		url, err := s.storageClient.UploadFile(objectName, file)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to upload service image", "service_id", ID, "err", err)
+			s.removeObjects(ctx, uploaded)
+			return nil, exceptions.MakeGenericApiError()
+		}
+		uploaded = append(uploaded, objectName)
+
+		image, err := serviceimages.New(imageID, ID, url, ordering+i)
+		if err != nil {
+			s.removeObjects(ctx, uploaded)
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		}
+		images = append(images, image)
+	}
+
+	if apiErr := s.createImagesTx(ctx, images); apiErr != nil {
+		s.removeObjects(ctx, uploaded)
+		return nil, apiErr
+	}
+
+	s.logger.InfoContext(ctx, "service images added with success", "service_id", ID, "count", len(images))
+	return s.toResponse(ctx, service)
+}
+
+func (s *servicesService) RemoveImage(ctx context.Context, ID, imageID string) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to remove service image", "service_id", ID, "image_id", imageID)
+
+	userID, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	if _, apiErr := s.getOwnedService(ctx, profileID, ID); apiErr != nil {
+		return apiErr
+	}
+
+	image, err := s.imagesRepo.GetByID(ctx, imageID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get service image", "image_id", imageID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if image == nil || image.ServiceID() != ID {
+		return exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrServiceImageNotFound)
+	}
+
+	if err := s.imagesRepo.Delete(ctx, imageID); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to delete service image", "image_id", imageID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	s.removeObjects(ctx, []string{fmt.Sprintf("services/%s/%s/%s", userID, ID, imageID)})
+
+	s.logger.InfoContext(ctx, "service image removed with success", "service_id", ID, "image_id", imageID)
+	return nil
+}
+
+func (s *servicesService) ReorderImages(ctx context.Context, ID string, input common.ReorderRequest) (*common.Service, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to reorder service images", "service_id", ID)
+
+	_, profileID, apiErr := s.currentProfile(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	service, apiErr := s.getOwnedService(ctx, profileID, ID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	images, err := s.imagesRepo.GetByServiceID(ctx, ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get service images", "service_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	byID := make(map[string]*serviceimages.ServiceImage, len(images))
+	for _, image := range images {
+		byID[image.ID()] = image
+	}
+	if !utils.IsPermutation(input.IDs, byID) {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidOrdering)
+	}
+
+	if apiErr := s.applyOrdering(ctx, input.IDs, s.imagesRepo.UpdateOrderingTx); apiErr != nil {
+		return nil, apiErr
+	}
+
+	s.logger.InfoContext(ctx, "service images reordered with success", "service_id", ID)
+	return s.toResponse(ctx, service)
+}
+
+// currentProfile returns the signed user and the profile that owns their services.
+func (s *servicesService) currentProfile(ctx context.Context) (string, string, *exceptions.ApiError[string]) {
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return "", "", exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	userProfile, err := s.userProfilesRepo.FindByUserID(ctx, c.UserID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to find user profile", "user_id", c.UserID, "err", err)
+		return "", "", exceptions.MakeGenericApiError()
+	}
+	if userProfile == nil {
+		s.logger.WarnContext(ctx, "user profile not found", "user_id", c.UserID)
+		return "", "", exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrUserProfileNotFound)
+	}
+
+	return c.UserID, userProfile.ID(), nil
+}
+
+// getOwnedService answers services of other profiles as not found, so their ids
+// cannot be probed.
+func (s *servicesService) getOwnedService(ctx context.Context, profileID, ID string) (*Service, *exceptions.ApiError[string]) {
+	service, err := s.repository.GetByID(ctx, ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get service", "service_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if service == nil || service.IsDeleted() || service.UserProfileID() != profileID {
+		s.logger.WarnContext(ctx, "service not found for profile", "service_id", ID, "user_profile_id", profileID)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrServiceNotFound)
+	}
+
+	return service, nil
+}
+
+func (s *servicesService) createImagesTx(ctx context.Context, images []*serviceimages.ServiceImage) *exceptions.ApiError[string] {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while starting transaction", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	defer tx.Rollback()
+
+	for _, image := range images {
+		if err := s.imagesRepo.CreateTx(tx, image); err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to create service image", "image_id", image.ID(), "err", err)
+			return exceptions.MakeGenericApiError()
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.ErrorContext(ctx, "error while commiting service images", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	return nil
+}
+
+// applyOrdering stores the position of every id in a single transaction.
+func (s *servicesService) applyOrdering(
+	ctx context.Context,
+	IDs []string,
+	update func(ctx context.Context, tx *sqlx.Tx, ID string, ordering int) error,
+) *exceptions.ApiError[string] {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while starting transaction", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	defer tx.Rollback()
+
+	for ordering, ID := range IDs {
+		if err := update(ctx, tx, ID, ordering); err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to update ordering", "id", ID, "err", err)
+			return exceptions.MakeGenericApiError()
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.ErrorContext(ctx, "error while commiting ordering", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	return nil
+}
+
+// removeObjects deletes uploaded files that are no longer referenced. Failures are
+// only logged, a leftover file does not affect the catalog.
+func (s *servicesService) removeObjects(ctx context.Context, objectNames []string) {
+	for _, objectName := range objectNames {
+		if err := s.storageClient.RemoveObject(ctx, objectName); err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to remove service image", "object", objectName, "err", err)
+		}
+	}
+}
+
+func (s *servicesService) toResponse(ctx context.Context, service *Service) (*common.Service, *exceptions.ApiError[string]) {
+	responses, apiErr := s.toResponses(ctx, []*Service{service})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return &responses[0], nil
+}
+
+func (s *servicesService) toResponses(ctx context.Context, services []*Service) ([]common.Service, *exceptions.ApiError[string]) {
+	serviceIDs := make([]string, 0, len(services))
+	for _, service := range services {
+		serviceIDs = append(serviceIDs, service.ID())
+	}
+
+	images, err := s.imagesRepo.GetByServiceIDs(ctx, serviceIDs)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get service images", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	imagesByService := make(map[string][]common.ServiceImage, len(services))
+	for _, image := range images {
+		imagesByService[image.ServiceID()] = append(imagesByService[image.ServiceID()], common.ServiceImage{
+			ID:       image.ID(),
+			URL:      image.URL(),
+			Ordering: image.Ordering(),
+		})
+	}
+
+	responses := make([]common.Service, 0, len(services))
+	for _, service := range services {
+		serviceImages := imagesByService[service.ID()]
+		if serviceImages == nil {
+			serviceImages = []common.ServiceImage{}
+		}
+
+		responses = append(responses, common.Service{
+			ID:               service.ID(),
+			Name:             service.Name(),
+			Description:      service.Description(),
+			Price:            service.Price(),
+			OwnLocationPrice: service.OwnLocationPrice(),
+			Images:           serviceImages,
+		})
+	}
+
+	return responses, nil
+}
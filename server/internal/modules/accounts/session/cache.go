@@ -0,0 +1,101 @@
+package session
+
+import (
+	"conecta-mare-server/pkg/jwt"
+	"sync"
+	"time"
+)
+
+const (
+	// validSessionTTL bounds how long another instance may keep accepting a session
+	// that was revoked elsewhere before the database is consulted again.
+	validSessionTTL = 30 * time.Second
+	// revokedSessionTTL matches the access token lifetime, after which a revoked
+	// session cannot be presented anymore.
+	revokedSessionTTL = jwt.AccessTokenDuration
+)
+
+type cachedSession struct {
+	userID    string
+	expiresAt time.Time
+}
+
+// revocationCache keeps the outcome of recent session checks in memory so the auth
+// middleware does not hit the database on every request. Revocations made by this
+// instance are recorded immediately and win over any cached validation.
+type revocationCache struct {
+	mu      sync.RWMutex
+	valid   map[string]cachedSession
+	revoked map[string]time.Time
+}
+
+func newRevocationCache() *revocationCache {
+	return &revocationCache{
+		valid:   make(map[string]cachedSession),
+		revoked: make(map[string]time.Time),
+	}
+}
+
+// lookup returns whether the session is known and, if so, whether it is valid.
+func (c *revocationCache) lookup(sessionID, userID string) (valid bool, known bool) {
+	now := time.Now()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if until, ok := c.revoked[sessionID]; ok && now.Before(until) {
+		return false, true
+	}
+
+	if entry, ok := c.valid[sessionID]; ok && now.Before(entry.expiresAt) {
+		return entry.userID == userID, true
+	}
+
+	return false, false
+}
+
+func (c *revocationCache) markValid(sessionID, userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pruneLocked()
+	c.valid[sessionID] = cachedSession{userID: userID, expiresAt: time.Now().Add(validSessionTTL)}
+}
+
+func (c *revocationCache) markRevoked(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pruneLocked()
+	delete(c.valid, sessionID)
+	c.revoked[sessionID] = time.Now().Add(revokedSessionTTL)
+}
+
+// markUserRevoked revokes every cached session of the user except exceptSessionID,
+// which may be empty.
+func (c *revocationCache) markUserRevoked(userID, exceptSessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	until := time.Now().Add(revokedSessionTTL)
+	for sessionID, entry := range c.valid {
+		if entry.userID == userID && sessionID != exceptSessionID {
+			delete(c.valid, sessionID)
+			c.revoked[sessionID] = until
+		}
+	}
+}
+
+func (c *revocationCache) pruneLocked() {
+	now := time.Now()
+	for sessionID, entry := range c.valid {
+		if now.After(entry.expiresAt) {
+			delete(c.valid, sessionID)
+		}
+	}
+	for sessionID, until := range c.revoked {
+		if now.After(until) {
+			delete(c.revoked, sessionID)
+		}
+	}
+}
@@ -12,52 +12,66 @@ const (
 )
 
 type Session struct {
-	id        string
-	userID    string
-	jti       string
-	active    bool
-	createdAt time.Time
-	updatedAt time.Time
-	expiresAt time.Time
+	id         string
+	userID     string
+	jti        string
+	active     bool
+	userAgent  *string
+	ipAddress  *string
+	lastSeenAt *time.Time
+	createdAt  time.Time
+	updatedAt  time.Time
+	expiresAt  time.Time
 }
 
-func New(userID, JTI string) (*Session, error) {
+func New(userID, JTI, userAgent, ipAddress string) (*Session, error) {
 	if userID == "" || JTI == "" {
 		return nil, fmt.Errorf("userID and JTI are required")
 	}
 
-	return &Session{
-		id:        uid.New("sess"),
-		userID:    userID,
-		jti:       JTI,
-		active:    true,
-		createdAt: time.Now(),
-		updatedAt: time.Now(),
-		expiresAt: time.Now().Add(ttl),
-	}, nil
+	now := time.Now()
+	sess := &Session{
+		id:         uid.New("sess"),
+		userID:     userID,
+		jti:        JTI,
+		active:     true,
+		lastSeenAt: &now,
+		createdAt:  now,
+		updatedAt:  now,
+		expiresAt:  now.Add(ttl),
+	}
+	sess.setDevice(userAgent, ipAddress)
+
+	return sess, nil
 }
 
 func NewFromModel(m models.Session) *Session {
 	return &Session{
-		id:        m.ID,
-		userID:    m.UserID,
-		jti:       m.JTI,
-		active:    m.Active,
-		createdAt: m.CreatedAt,
-		updatedAt: m.UpdatedAt,
-		expiresAt: m.ExpiresAt,
+		id:         m.ID,
+		userID:     m.UserID,
+		jti:        m.JTI,
+		active:     m.Active,
+		userAgent:  m.UserAgent,
+		ipAddress:  m.IPAddress,
+		lastSeenAt: m.LastSeenAt,
+		createdAt:  m.CreatedAt,
+		updatedAt:  m.UpdatedAt,
+		expiresAt:  m.ExpiresAt,
 	}
 }
 
 func (s *Session) ToModel() models.Session {
 	return models.Session{
-		ID:        s.id,
-		UserID:    s.userID,
-		JTI:       s.jti,
-		Active:    s.active,
-		CreatedAt: s.createdAt,
-		UpdatedAt: s.updatedAt,
-		ExpiresAt: s.expiresAt,
+		ID:         s.id,
+		UserID:     s.userID,
+		JTI:        s.jti,
+		Active:     s.active,
+		UserAgent:  s.userAgent,
+		IPAddress:  s.ipAddress,
+		LastSeenAt: s.lastSeenAt,
+		CreatedAt:  s.createdAt,
+		UpdatedAt:  s.updatedAt,
+		ExpiresAt:  s.expiresAt,
 	}
 }
 
@@ -70,6 +84,22 @@ func (s *Session) ChangeJTI(JTI string) {
 	s.updatedAt = time.Now()
 }
 
+// Rotate moves the session to a freshly issued refresh token JTI and extends
+// its lifetime so it matches the new token expiration.
+func (s *Session) Rotate(JTI string) {
+	s.ChangeJTI(JTI)
+	s.expiresAt = time.Now().Add(ttl)
+}
+
+// Touch records that the device owning the session was just seen, keeping
+// the latest user agent and IP address it presented.
+func (s *Session) Touch(userAgent, ipAddress string) {
+	now := time.Now()
+	s.lastSeenAt = &now
+	s.updatedAt = now
+	s.setDevice(userAgent, ipAddress)
+}
+
 func (s *Session) Activate() {
 	s.active = true
 	s.updatedAt = time.Now()
@@ -79,3 +109,48 @@ func (s *Session) Deactivate() {
 	s.active = false
 	s.updatedAt = time.Now()
 }
+
+func (s *Session) setDevice(userAgent, ipAddress string) {
+	if userAgent != "" {
+		s.userAgent = &userAgent
+	}
+	if ipAddress != "" {
+		s.ipAddress = &ipAddress
+	}
+}
+
+func (s *Session) ID() string {
+	return s.id
+}
+
+func (s *Session) UserID() string {
+	return s.userID
+}
+
+func (s *Session) JTI() string {
+	return s.jti
+}
+
+func (s *Session) IsActive() bool {
+	return s.active
+}
+
+func (s *Session) UserAgent() *string {
+	return s.userAgent
+}
+
+func (s *Session) IPAddress() *string {
+	return s.ipAddress
+}
+
+func (s *Session) LastSeenAt() *time.Time {
+	return s.lastSeenAt
+}
+
+func (s *Session) CreatedAt() time.Time {
+	return s.createdAt
+}
+
+func (s *Session) ExpiresAt() time.Time {
+	return s.expiresAt
+}
@@ -10,17 +10,27 @@ type (
 	SessionsRepository interface {
 		Create(ctx context.Context, session *Session) error
 		Update(ctx context.Context, session *Session) error
+		Rotate(ctx context.Context, session *Session, previousJTI string) (bool, error)
 		GetAllByUserID(ctx context.Context, userID string) ([]*Session, error)
 		GetActiveByUserID(ctx context.Context, userID string) (*Session, error)
+		GetByID(ctx context.Context, ID string) (*Session, error)
 		GetByJTI(ctx context.Context, JTI string) (*Session, error)
 		DeactivateAll(ctx context.Context, userID string) error
+		DeactivateAllExcept(ctx context.Context, userID, sessionID string) error
+		IsValid(ctx context.Context, ID, userID string) (bool, error)
 	}
 
 	SessionsService interface {
 		CreateSession(ctx context.Context, input common.CreateSessionRequest) (*Session, error)
 		DeactivateAllSessions(ctx context.Context, userID string) error
+		DeactivateOtherSessions(ctx context.Context, userID, currentSessionID string) error
 		GetActiveSessionByUserID(ctx context.Context, userID string) (*Session, error)
+		GetSessionByJTI(ctx context.Context, JTI string) (*Session, error)
+		GetSessionByID(ctx context.Context, ID string) (*Session, error)
+		GetSessionsByUserID(ctx context.Context, userID string) ([]*Session, error)
 		UpdateSession(ctx context.Context, session *Session) (*Session, error)
+		RotateSession(ctx context.Context, session *Session, previousJTI string) (bool, error)
+		IsSessionValid(ctx context.Context, sessionID, userID string) (bool, error)
 	}
 
 	sessionService struct {
@@ -62,6 +62,27 @@ func (r *sessionsRepository) GetActiveByUserID(ctx context.Context, userID strin
 	return NewFromModel(sessionModel), nil
 }
 
+func (r *sessionsRepository) GetByID(ctx context.Context, ID string) (*Session, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var sessionModel models.Session
+	err := r.db.GetContext(
+		ctx,
+		&sessionModel,
+		"SELECT * FROM sessions WHERE id = $1",
+		ID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFromModel(sessionModel), nil
+}
+
 func (r *sessionsRepository) GetByJTI(ctx context.Context, JTI string) (*Session, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
@@ -95,6 +116,9 @@ func (r *sessionsRepository) Create(ctx context.Context, session *Session) error
 			user_id,
 			jti,
 			active,
+			user_agent,
+			ip_address,
+			last_seen_at,
 			created_at,
 			updated_at,
 			expires_at
@@ -103,6 +127,9 @@ func (r *sessionsRepository) Create(ctx context.Context, session *Session) error
 			:user_id,
 			:jti,
 			:active,
+			:user_agent,
+			:ip_address,
+			:last_seen_at,
 			:created_at,
 			:updated_at,
 			:expires_at
@@ -127,7 +154,11 @@ func (r *sessionsRepository) Update(ctx context.Context, session *Session) error
 		SET
 			active = :active,
 			jti = :jti,
-			updated_at = :updated_at
+			user_agent = :user_agent,
+			ip_address = :ip_address,
+			last_seen_at = :last_seen_at,
+			updated_at = :updated_at,
+			expires_at = :expires_at
 		WHERE id = :id
 	`
 
@@ -139,14 +170,92 @@ func (r *sessionsRepository) Update(ctx context.Context, session *Session) error
 	return nil
 }
 
+// Rotate saves the session only while it still holds previousJTI, so two refreshes
+// racing with the same token cannot both win. It reports whether the row was updated.
+func (r *sessionsRepository) Rotate(ctx context.Context, session *Session, previousJTI string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE sessions
+		SET
+			jti = :jti,
+			user_agent = :user_agent,
+			ip_address = :ip_address,
+			last_seen_at = :last_seen_at,
+			updated_at = :updated_at,
+			expires_at = :expires_at
+		WHERE id = :id AND jti = :previous_jti AND active = true
+	`
+
+	args := struct {
+		models.Session
+		PreviousJTI string `db:"previous_jti"`
+	}{session.ToModel(), previousJTI}
+
+	result, err := r.db.NamedExecContext(ctx, query, args)
+	if err != nil {
+		return false, err
+	}
+
+	rotated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rotated > 0, nil
+}
+
 func (r sessionsRepository) DeactivateAll(ctx context.Context, userId string) error {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
-	_, err := r.db.ExecContext(ctx, "UPDATE sessions SET active = false WHERE user_id = $1", userId)
+	_, err := r.db.ExecContext(ctx, "UPDATE sessions SET active = false, updated_at = NOW() WHERE user_id = $1 AND active = true", userId)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (r sessionsRepository) DeactivateAllExcept(ctx context.Context, userID, sessionID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		"UPDATE sessions SET active = false, updated_at = NOW() WHERE user_id = $1 AND id <> $2 AND active = true",
+		userID,
+		sessionID,
+	)
+	return err
+}
+
+func (r *sessionsRepository) IsValid(ctx context.Context, ID, userID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var valid bool
+	err := r.db.GetContext(
+		ctx,
+		&valid,
+		`
+		SELECT EXISTS (
+			SELECT 1
+			FROM sessions s
+			INNER JOIN users u ON u.id = s.user_id
+			WHERE s.id = $1
+				AND s.user_id = $2
+				AND s.active = true
+				AND u.deleted_at IS NULL
+		)
+		`,
+		ID,
+		userID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return valid, nil
+}
@@ -10,19 +10,21 @@ import (
 
 type service struct {
 	sessionRepo SessionsRepository
+	cache       *revocationCache
 	logger      *slog.Logger
 }
 
 func NewService(sessionsRepository SessionsRepository, logger *slog.Logger) SessionsService {
 	return &service{
 		sessionRepo: sessionsRepository,
+		cache:       newRevocationCache(),
 		logger:      logger,
 	}
 }
 
 func (s service) CreateSession(ctx context.Context, input common.CreateSessionRequest) (*Session, error) {
 	s.logger.InfoContext(ctx, "attempting to create user session", "user_id", input.UserID)
-	sess, err := New(input.UserID, input.JTI)
+	sess, err := New(input.UserID, input.JTI, input.UserAgent, input.IPAddress)
 	if err != nil {
 		s.logger.ErrorContext(ctx, "error while attempting to process session entity", "user_id", input.UserID, "err", err)
 		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnprocessableEntity, err)
@@ -46,6 +48,21 @@ func (s service) DeactivateAllSessions(ctx context.Context, userID string) error
 		return exceptions.MakeGenericApiError()
 	}
 
+	s.cache.markUserRevoked(userID, "")
+
+	return nil
+}
+
+func (s service) DeactivateOtherSessions(ctx context.Context, userID, currentSessionID string) error {
+	s.logger.InfoContext(ctx, "attempting to deactivate other user sessions", "user_id", userID, "session_id", currentSessionID)
+	err := s.sessionRepo.DeactivateAllExcept(ctx, userID, currentSessionID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to deactivate other user sessions", "user_id", userID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	s.cache.markUserRevoked(userID, currentSessionID)
+
 	return nil
 }
 
@@ -60,6 +77,39 @@ func (s service) GetActiveSessionByUserID(ctx context.Context, userID string) (*
 	return sess, nil
 }
 
+func (s service) GetSessionByJTI(ctx context.Context, JTI string) (*Session, error) {
+	s.logger.InfoContext(ctx, "attempting to get session by jti")
+	sess, err := s.sessionRepo.GetByJTI(ctx, JTI)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get session by jti", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	return sess, nil
+}
+
+func (s service) GetSessionByID(ctx context.Context, ID string) (*Session, error) {
+	s.logger.InfoContext(ctx, "attempting to get session by id", "session_id", ID)
+	sess, err := s.sessionRepo.GetByID(ctx, ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get session by id", "session_id", ID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	return sess, nil
+}
+
+func (s service) GetSessionsByUserID(ctx context.Context, userID string) ([]*Session, error) {
+	s.logger.InfoContext(ctx, "attempting to get user sessions", "user_id", userID)
+	sessions, err := s.sessionRepo.GetAllByUserID(ctx, userID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get user sessions", "user_id", userID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	return sessions, nil
+}
+
 func (s service) UpdateSession(ctx context.Context, session *Session) (*Session, error) {
 	s.logger.InfoContext(ctx, "attempting to update session", "user_id", session.userID, "session", session.id)
 	err := s.sessionRepo.Update(ctx, session)
@@ -68,5 +118,42 @@ func (s service) UpdateSession(ctx context.Context, session *Session) (*Session,
 		return nil, exceptions.MakeGenericApiError()
 	}
 
+	if !session.active {
+		s.cache.markRevoked(session.id)
+	}
+
 	return session, nil
 }
+
+func (s service) RotateSession(ctx context.Context, session *Session, previousJTI string) (bool, error) {
+	s.logger.InfoContext(ctx, "attempting to rotate session", "user_id", session.userID, "session", session.id)
+	rotated, err := s.sessionRepo.Rotate(ctx, session, previousJTI)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to rotate session", "user_id", session.userID, "err", err)
+		return false, exceptions.MakeGenericApiError()
+	}
+
+	return rotated, nil
+}
+
+// IsSessionValid checks that the session is active and its user is not disabled,
+// answering from the in-process cache whenever possible.
+func (s service) IsSessionValid(ctx context.Context, sessionID, userID string) (bool, error) {
+	if valid, known := s.cache.lookup(sessionID, userID); known {
+		return valid, nil
+	}
+
+	valid, err := s.sessionRepo.IsValid(ctx, sessionID, userID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to validate session", "session_id", sessionID, "err", err)
+		return false, err
+	}
+
+	if valid {
+		s.cache.markValid(sessionID, userID)
+	} else {
+		s.cache.markRevoked(sessionID)
+	}
+
+	return valid, nil
+}
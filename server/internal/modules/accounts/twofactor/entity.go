@@ -0,0 +1,169 @@
+package twofactor
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"conecta-mare-server/pkg/security"
+	"conecta-mare-server/pkg/uid"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// challengeTTL is how long the user has to type the code after the password step.
+	challengeTTL = 5 * time.Minute
+	// maxChallengeAttempts bounds code guesses per password login.
+	maxChallengeAttempts = 5
+	recoveryCodeCount    = 10
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Factor is the TOTP seed of a user. It only protects logins once confirmed with a
+// code from the authenticator app.
+type Factor struct {
+	userID          string
+	secretEncrypted string
+	confirmedAt     *time.Time
+	lastUsedStep    int64
+	createdAt       time.Time
+}
+
+func NewFactor(userID, secretEncrypted string) *Factor {
+	return &Factor{
+		userID:          userID,
+		secretEncrypted: secretEncrypted,
+		confirmedAt:     nil,
+		lastUsedStep:    0,
+		createdAt:       time.Now(),
+	}
+}
+
+func NewFactorFromModel(m models.TOTPFactor) *Factor {
+	return &Factor{
+		userID:          m.UserID,
+		secretEncrypted: m.SecretEncrypted,
+		confirmedAt:     m.ConfirmedAt,
+		lastUsedStep:    m.LastUsedStep,
+		createdAt:       m.CreatedAt,
+	}
+}
+
+func (f *Factor) ToModel() models.TOTPFactor {
+	return models.TOTPFactor{
+		UserID:          f.userID,
+		SecretEncrypted: f.secretEncrypted,
+		ConfirmedAt:     f.confirmedAt,
+		LastUsedStep:    f.lastUsedStep,
+		CreatedAt:       f.createdAt,
+	}
+}
+
+func (f *Factor) Confirm(step int64) {
+	now := time.Now()
+	f.confirmedAt = &now
+	f.lastUsedStep = step
+}
+
+func (f *Factor) IsConfirmed() bool {
+	return f.confirmedAt != nil
+}
+
+func (f *Factor) UserID() string          { return f.userID }
+func (f *Factor) SecretEncrypted() string { return f.secretEncrypted }
+func (f *Factor) LastUsedStep() int64     { return f.lastUsedStep }
+
+// Challenge is the pending second step of a login whose password was already checked.
+type Challenge struct {
+	id        string
+	userID    string
+	tokenHash string
+	// loginKey is the lockout key the first factor was checked against.
+	loginKey  string
+	attempts  int
+	expiresAt time.Time
+	createdAt time.Time
+}
+
+// NewChallenge returns the challenge together with its plain token, which is only
+// handed to the client and never stored.
+func NewChallenge(userID, loginKey string) (*Challenge, string, error) {
+	plainToken, err := security.GenerateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	challenge := Challenge{
+		id:        uid.New("mfachallenge"),
+		userID:    userID,
+		tokenHash: security.HashToken(plainToken),
+		loginKey:  loginKey,
+		attempts:  0,
+		expiresAt: time.Now().Add(challengeTTL),
+		createdAt: time.Now(),
+	}
+
+	return &challenge, plainToken, nil
+}
+
+func NewChallengeFromModel(m models.TwoFactorChallenge) *Challenge {
+	return &Challenge{
+		id:        m.ID,
+		userID:    m.UserID,
+		tokenHash: m.TokenHash,
+		loginKey:  m.LoginKey,
+		attempts:  m.Attempts,
+		expiresAt: m.ExpiresAt,
+		createdAt: m.CreatedAt,
+	}
+}
+
+func (c *Challenge) ToModel() models.TwoFactorChallenge {
+	return models.TwoFactorChallenge{
+		ID:        c.id,
+		UserID:    c.userID,
+		TokenHash: c.tokenHash,
+		LoginKey:  c.loginKey,
+		Attempts:  c.attempts,
+		ExpiresAt: c.expiresAt,
+		CreatedAt: c.createdAt,
+	}
+}
+
+func (c *Challenge) IsExpired() bool {
+	return time.Now().After(c.expiresAt)
+}
+
+func (c *Challenge) ID() string           { return c.id }
+func (c *Challenge) UserID() string       { return c.userID }
+func (c *Challenge) LoginKey() string     { return c.loginKey }
+func (c *Challenge) ExpiresAt() time.Time { return c.expiresAt }
+
+// NewRecoveryCodes returns the plain codes shown once to the user and their hashes.
+func NewRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for range recoveryCodeCount {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(buf))[:10]
+		code := raw[:5] + "-" + raw[5:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode ignores case, spaces and dashes so codes can be typed loosely.
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(code)
+	normalized = strings.NewReplacer("-", "", " ", "").Replace(normalized)
+	return security.HashToken(normalized)
+}
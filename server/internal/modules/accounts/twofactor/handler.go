@@ -0,0 +1,95 @@
+package twofactor
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/httphelpers"
+	"conecta-mare-server/pkg/valueobjects"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+var (
+	instance *twoFactorHandler
+	Once     sync.Once
+)
+
+func NewHandler(twoFactorService TwoFactorService, authMiddleware *middlewares.AuthMiddleware) *twoFactorHandler {
+	Once.Do(
+		func() {
+			instance = &twoFactorHandler{
+				twoFactorService: twoFactorService,
+				authMiddleware:   authMiddleware,
+			}
+		},
+	)
+
+	return instance
+}
+
+func (h twoFactorHandler) RegisterRoutes(r *chi.Mux) {
+	m := h.authMiddleware
+	r.Route(
+		"/api/v1/two-factor", func(r chi.Router) {
+			// Private, offered to the accounts that hold sensitive data
+			r.Group(func(r chi.Router) {
+				r.Use(m.WithAuth, m.RequireRole(valueobjects.Professional, valueobjects.Moderator, valueobjects.Admin))
+				r.Post("/totp", h.handleEnroll)
+				r.Post("/totp/confirm", h.handleConfirm)
+				r.Delete("/totp", h.handleDisable)
+			})
+		},
+	)
+}
+
+func (h twoFactorHandler) handleEnroll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	enrollment, err := h.twoFactorService.Enroll(ctx)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, enrollment)
+}
+
+func (h twoFactorHandler) handleConfirm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.TOTPCodeRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	recoveryCodes, err := h.twoFactorService.Confirm(ctx, body)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, recoveryCodes)
+}
+
+func (h twoFactorHandler) handleDisable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.TOTPCodeRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if err := h.twoFactorService.Disable(ctx, body); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteSuccess(w, http.StatusOK)
+}
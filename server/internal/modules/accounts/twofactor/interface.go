@@ -0,0 +1,50 @@
+package twofactor
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/security"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	TwoFactorRepository interface {
+		GetFactor(ctx context.Context, userID string) (*Factor, error)
+		SaveFactor(ctx context.Context, factor *Factor) error
+		ConfirmFactorTx(ctx context.Context, tx *sqlx.Tx, factor *Factor) error
+		UseStep(ctx context.Context, userID string, step int64) (bool, error)
+		DeleteFactorTx(ctx context.Context, tx *sqlx.Tx, userID string) error
+		ReplaceRecoveryCodesTx(ctx context.Context, tx *sqlx.Tx, userID string, codeHashes []string) error
+		UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+		CreateChallenge(ctx context.Context, challenge *Challenge) error
+		GetChallengeByTokenHash(ctx context.Context, tokenHash string) (*Challenge, error)
+		IncrementChallengeAttempts(ctx context.Context, ID string) (int, error)
+		DeleteChallenge(ctx context.Context, ID string) (bool, error)
+		DeleteExpiredChallenges(ctx context.Context, now time.Time) error
+	}
+	TwoFactorService interface {
+		Enroll(ctx context.Context) (*common.TOTPEnrollment, *exceptions.ApiError[string])
+		Confirm(ctx context.Context, input common.TOTPCodeRequest) (*common.RecoveryCodes, *exceptions.ApiError[string])
+		Disable(ctx context.Context, input common.TOTPCodeRequest) *exceptions.ApiError[string]
+		IsEnabled(ctx context.Context, userID string) (bool, error)
+		StartChallenge(ctx context.Context, userID, loginKey string) (string, error)
+		VerifyChallenge(ctx context.Context, input common.TwoFactorLoginRequest) (*Challenge, *exceptions.ApiError[string])
+		RemoveExpiredChallenges(ctx context.Context)
+	}
+	twoFactorService struct {
+		db         *sqlx.DB
+		repository TwoFactorRepository
+		secretBox  *security.SecretBox
+		issuer     string
+		logger     *slog.Logger
+	}
+	twoFactorHandler struct {
+		twoFactorService TwoFactorService
+		authMiddleware   *middlewares.AuthMiddleware
+	}
+)
@@ -0,0 +1,238 @@
+package twofactor
+
+import (
+	"conecta-mare-server/internal/databases/postgres/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) TwoFactorRepository {
+	return &repository{db}
+}
+
+func (r *repository) GetFactor(ctx context.Context, userID string) (*Factor, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var model models.TOTPFactor
+	err := r.db.GetContext(ctx, &model, "SELECT * FROM totp_factors WHERE user_id = $1", userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewFactorFromModel(model), nil
+}
+
+// SaveFactor stores a new, unconfirmed seed, replacing a previous enrollment that was
+// never confirmed.
+func (r *repository) SaveFactor(ctx context.Context, factor *Factor) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	model := factor.ToModel()
+	query := `
+		INSERT INTO totp_factors (
+				user_id, secret_encrypted, confirmed_at, last_used_step, created_at
+			) VALUES (
+				:user_id, :secret_encrypted, :confirmed_at, :last_used_step, :created_at
+		)
+		ON CONFLICT (user_id) DO UPDATE SET
+			secret_encrypted = EXCLUDED.secret_encrypted,
+			confirmed_at = EXCLUDED.confirmed_at,
+			last_used_step = EXCLUDED.last_used_step,
+			created_at = EXCLUDED.created_at
+		WHERE totp_factors.confirmed_at IS NULL`
+
+	_, err := r.db.NamedExecContext(ctx, query, model)
+	return err
+}
+
+func (r *repository) ConfirmFactorTx(ctx context.Context, tx *sqlx.Tx, factor *Factor) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	model := factor.ToModel()
+	query := `
+		UPDATE totp_factors SET
+			confirmed_at = :confirmed_at,
+			last_used_step = :last_used_step
+		WHERE user_id = :user_id`
+
+	_, err := tx.NamedExecContext(ctx, query, model)
+	return err
+}
+
+// UseStep records the time step of an accepted code, reporting false when the same or
+// a later step was already used so a code cannot be replayed concurrently.
+func (r *repository) UseStep(ctx context.Context, userID string, step int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		"UPDATE totp_factors SET last_used_step = $1 WHERE user_id = $2 AND last_used_step < $1",
+		step,
+		userID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (r *repository) DeleteFactorTx(ctx context.Context, tx *sqlx.Tx, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM totp_factors WHERE user_id = $1", userID)
+	return err
+}
+
+func (r *repository) ReplaceRecoveryCodesTx(ctx context.Context, tx *sqlx.Tx, userID string, codeHashes []string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+
+	for _, codeHash := range codeHashes {
+		_, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO recovery_codes (user_id, code_hash, created_at) VALUES ($1, $2, $3)",
+			userID,
+			codeHash,
+			time.Now(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UseRecoveryCode consumes the code, reporting false when it does not exist or was
+// already used.
+func (r *repository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		"UPDATE recovery_codes SET used_at = $1 WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL",
+		time.Now(),
+		userID,
+		codeHash,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (r *repository) CreateChallenge(ctx context.Context, challenge *Challenge) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	model := challenge.ToModel()
+	query := `
+		INSERT INTO two_factor_challenges (
+				id, user_id, token_hash, login_key, attempts, expires_at, created_at
+			) VALUES (
+				:id, :user_id, :token_hash, :login_key, :attempts, :expires_at, :created_at
+		)`
+
+	_, err := r.db.NamedExecContext(ctx, query, model)
+	return err
+}
+
+func (r *repository) GetChallengeByTokenHash(ctx context.Context, tokenHash string) (*Challenge, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var model models.TwoFactorChallenge
+	err := r.db.GetContext(ctx, &model, "SELECT * FROM two_factor_challenges WHERE token_hash = $1", tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewChallengeFromModel(model), nil
+}
+
+func (r *repository) IncrementChallengeAttempts(ctx context.Context, ID string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var attempts int
+	err := r.db.GetContext(
+		ctx,
+		&attempts,
+		"UPDATE two_factor_challenges SET attempts = attempts + 1 WHERE id = $1 RETURNING attempts",
+		ID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return maxChallengeAttempts + 1, nil
+		}
+		return 0, err
+	}
+
+	return attempts, nil
+}
+
+// DeleteChallenge consumes the challenge, reporting false when it was already used.
+func (r *repository) DeleteChallenge(ctx context.Context, ID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM two_factor_challenges WHERE id = $1", ID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (r *repository) DeleteExpiredChallenges(ctx context.Context, now time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM two_factor_challenges WHERE expires_at < $1", now)
+	return err
+}
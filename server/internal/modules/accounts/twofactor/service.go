@@ -0,0 +1,312 @@
+package twofactor
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/jwt"
+	"conecta-mare-server/pkg/security"
+	"conecta-mare-server/pkg/totp"
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func NewService(
+	db *sqlx.DB,
+	repository TwoFactorRepository,
+	secretBox *security.SecretBox,
+	issuer string,
+	logger *slog.Logger,
+) TwoFactorService {
+	return &twoFactorService{
+		db:         db,
+		repository: repository,
+		secretBox:  secretBox,
+		issuer:     issuer,
+		logger:     logger,
+	}
+}
+
+// Enroll creates a new TOTP seed for the signed user. It has no effect on logins
+// until it is confirmed with a code.
+func (s *twoFactorService) Enroll(ctx context.Context) (*common.TOTPEnrollment, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to enroll totp factor")
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	existing, err := s.repository.GetFactor(ctx, c.UserID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get totp factor", "user_id", c.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if existing != nil && existing.IsConfirmed() {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusConflict, exceptions.ErrTwoFactorAlreadyEnabled)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to generate totp secret", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	secretEncrypted, err := s.secretBox.Seal(secret)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to encrypt totp secret", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	if err := s.repository.SaveFactor(ctx, NewFactor(c.UserID, secretEncrypted)); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to save totp factor", "user_id", c.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "totp factor enrolled, waiting for confirmation", "user_id", c.UserID)
+
+	return &common.TOTPEnrollment{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(s.issuer, c.Email, secret),
+	}, nil
+}
+
+// Confirm turns two-factor on once the user proves the app is set up, and returns
+// the recovery codes. They are shown only this once.
+func (s *twoFactorService) Confirm(ctx context.Context, input common.TOTPCodeRequest) (*common.RecoveryCodes, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to confirm totp factor")
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	factor, err := s.repository.GetFactor(ctx, c.UserID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get totp factor", "user_id", c.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if factor == nil {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrTwoFactorNotEnrolled)
+	}
+	if factor.IsConfirmed() {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusConflict, exceptions.ErrTwoFactorAlreadyEnabled)
+	}
+
+	secret, err := s.secretBox.Open(factor.SecretEncrypted())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to decrypt totp secret", "user_id", c.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	step, ok := totp.Validate(secret, input.Code, time.Now(), factor.LastUsedStep())
+	if !ok {
+		s.logger.InfoContext(ctx, "invalid totp code on confirmation", "user_id", c.UserID)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnprocessableEntity, exceptions.ErrInvalidTwoFactorCode)
+	}
+
+	codes, codeHashes, err := NewRecoveryCodes()
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to generate recovery codes", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while starting transaction", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	defer tx.Rollback()
+
+	factor.Confirm(step)
+	if err := s.repository.ConfirmFactorTx(ctx, tx, factor); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to confirm totp factor", "user_id", c.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	if err := s.repository.ReplaceRecoveryCodesTx(ctx, tx, c.UserID, codeHashes); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to store recovery codes", "user_id", c.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.ErrorContext(ctx, "error while commiting totp confirmation transaction", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "two-factor authentication enabled", "user_id", c.UserID)
+
+	return &common.RecoveryCodes{Codes: codes}, nil
+}
+
+func (s *twoFactorService) Disable(ctx context.Context, input common.TOTPCodeRequest) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to disable two-factor authentication")
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	factor, err := s.repository.GetFactor(ctx, c.UserID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get totp factor", "user_id", c.UserID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if factor == nil || !factor.IsConfirmed() {
+		return exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrTwoFactorNotEnrolled)
+	}
+
+	valid, err := s.verifyCode(ctx, factor, input.Code, input.RecoveryCode)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to verify two-factor code", "user_id", c.UserID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if !valid {
+		s.logger.InfoContext(ctx, "invalid two-factor code when disabling", "user_id", c.UserID)
+		return exceptions.MakeApiErrorWithStatus(http.StatusUnprocessableEntity, exceptions.ErrInvalidTwoFactorCode)
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while starting transaction", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	defer tx.Rollback()
+
+	if err := s.repository.DeleteFactorTx(ctx, tx, c.UserID); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to delete totp factor", "user_id", c.UserID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.ErrorContext(ctx, "error while commiting two-factor removal transaction", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "two-factor authentication disabled", "user_id", c.UserID)
+	return nil
+}
+
+func (s *twoFactorService) IsEnabled(ctx context.Context, userID string) (bool, error) {
+	factor, err := s.repository.GetFactor(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return factor != nil && factor.IsConfirmed(), nil
+}
+
+// StartChallenge opens the second login step and returns its plain token.
+func (s *twoFactorService) StartChallenge(ctx context.Context, userID, loginKey string) (string, error) {
+	challenge, plainToken, err := NewChallenge(userID, loginKey)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.repository.CreateChallenge(ctx, challenge); err != nil {
+		return "", err
+	}
+
+	return plainToken, nil
+}
+
+// VerifyChallenge checks the code typed for a login challenge and returns it. The
+// challenge is also returned with a wrong code so the caller can count the failure
+// against the account.
+func (s *twoFactorService) VerifyChallenge(ctx context.Context, input common.TwoFactorLoginRequest) (*Challenge, *exceptions.ApiError[string]) {
+	challenge, err := s.repository.GetChallengeByTokenHash(ctx, security.HashToken(input.ChallengeToken))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get two-factor challenge", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if challenge == nil || challenge.IsExpired() {
+		s.logger.InfoContext(ctx, "two-factor challenge is unknown or expired")
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrInvalidTwoFactorChallenge)
+	}
+
+	attempts, err := s.repository.IncrementChallengeAttempts(ctx, challenge.ID())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to count two-factor attempt", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if attempts > maxChallengeAttempts {
+		s.logger.WarnContext(ctx, "too many two-factor attempts, dropping challenge", "user_id", challenge.UserID())
+		if _, err := s.repository.DeleteChallenge(ctx, challenge.ID()); err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to delete two-factor challenge", "err", err)
+		}
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrInvalidTwoFactorChallenge)
+	}
+
+	factor, err := s.repository.GetFactor(ctx, challenge.UserID())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get totp factor", "user_id", challenge.UserID(), "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if factor == nil || !factor.IsConfirmed() {
+		// Two-factor was turned off after the password step, there is nothing left to check.
+		return s.consumeChallenge(ctx, challenge)
+	}
+
+	valid, err := s.verifyCode(ctx, factor, input.Code, input.RecoveryCode)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to verify two-factor code", "user_id", challenge.UserID(), "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if !valid {
+		s.logger.InfoContext(ctx, "invalid two-factor code on login", "user_id", challenge.UserID(), "attempts", attempts)
+		return challenge, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrInvalidTwoFactorCode)
+	}
+
+	return s.consumeChallenge(ctx, challenge)
+}
+
+func (s *twoFactorService) consumeChallenge(ctx context.Context, challenge *Challenge) (*Challenge, *exceptions.ApiError[string]) {
+	consumed, err := s.repository.DeleteChallenge(ctx, challenge.ID())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to consume two-factor challenge", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if !consumed {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrInvalidTwoFactorChallenge)
+	}
+
+	return challenge, nil
+}
+
+// verifyCode accepts either a TOTP code or an unused recovery code, consuming it.
+func (s *twoFactorService) verifyCode(ctx context.Context, factor *Factor, code, recoveryCode string) (bool, error) {
+	if recoveryCode != "" {
+		used, err := s.repository.UseRecoveryCode(ctx, factor.UserID(), HashRecoveryCode(recoveryCode))
+		if err != nil {
+			return false, err
+		}
+		if used {
+			s.logger.InfoContext(ctx, "recovery code used", "user_id", factor.UserID())
+		}
+		return used, nil
+	}
+
+	secret, err := s.secretBox.Open(factor.SecretEncrypted())
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := totp.Validate(secret, code, time.Now(), factor.LastUsedStep())
+	if !ok {
+		return false, nil
+	}
+
+	return s.repository.UseStep(ctx, factor.UserID(), step)
+}
+
+func (s *twoFactorService) RemoveExpiredChallenges(ctx context.Context) {
+	if err := s.repository.DeleteExpiredChallenges(ctx, time.Now()); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to remove expired two-factor challenges", "err", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"conecta-mare-server/pkg/valueobjects"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -98,6 +99,77 @@ func (up *UserProfile) Update(
 	return up.validateUpdate()
 }
 
+// ProfileChanges is a partial profile update. Nil fields are left untouched and
+// empty strings clear the optional ones.
+type ProfileChanges struct {
+	FullName       *string
+	SubcategoryID  *string
+	ProfileImage   *string
+	JobDescription *string
+	Phone          *string
+	SocialLinks    map[string]string
+}
+
+// Edit applies changes made after onboarding. Profiles that finished onboarding
+// keep the fields it made mandatory.
+func (up *UserProfile) Edit(changes ProfileChanges) error {
+	onboarded := up.IsOnboarded()
+
+	if changes.FullName != nil {
+		up.fullName = strings.TrimSpace(*changes.FullName)
+	}
+	if changes.SubcategoryID != nil {
+		up.subcategoryID = nilIfBlank(*changes.SubcategoryID)
+	}
+	if changes.ProfileImage != nil {
+		up.profileImage = nilIfBlank(*changes.ProfileImage)
+	}
+	if changes.JobDescription != nil {
+		up.jobDescription = nilIfBlank(*changes.JobDescription)
+	}
+	if changes.Phone != nil {
+		up.phone = nilIfBlank(*changes.Phone)
+		if up.phone != nil {
+			if sanitized, ok := valueobjects.SanitizePhoneNumber(*up.phone); ok {
+				up.phone = &sanitized
+			}
+		}
+	}
+	if changes.SocialLinks != nil {
+		up.socialLinks = changes.SocialLinks
+	}
+
+	now := time.Now()
+	up.updatedAt = &now
+
+	if err := up.validateCreation(); err != nil {
+		return err
+	}
+	if onboarded {
+		return up.validateUpdate()
+	}
+	if up.phone != nil {
+		if _, ok := valueobjects.SanitizePhoneNumber(*up.phone); !ok {
+			return fmt.Errorf("phone is invalid. use the 219887654321 format")
+		}
+	}
+	return nil
+}
+
+// IsOnboarded reports whether the professional onboarding was completed, which is
+// the only flow that fills in the job description.
+func (up *UserProfile) IsOnboarded() bool {
+	return up.jobDescription != nil
+}
+
+func nilIfBlank(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
 func (up *UserProfile) validateCreation() error {
 	if up.userID == "" {
 		return fmt.Errorf("user_id is required")
@@ -133,6 +205,7 @@ func (up *UserProfile) validateUpdate() error {
 func (up *UserProfile) ID() string                     { return up.id }
 func (up *UserProfile) UserID() string                 { return up.userID }
 func (up *UserProfile) FullName() string               { return up.fullName }
+func (up *UserProfile) SubcategoryID() *string         { return up.subcategoryID }
 func (up *UserProfile) ProfileImage() *string          { return up.profileImage }
 func (up *UserProfile) JobDescription() *string        { return up.jobDescription }
 func (up *UserProfile) Phone() *string                 { return up.phone }
@@ -0,0 +1,113 @@
+package userprofiles
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/httphelpers"
+	"conecta-mare-server/pkg/valueobjects"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxProfileUploadSize bounds the multipart body kept in memory while parsing.
+const maxProfileUploadSize = 10 << 20
+
+var (
+	instance *userProfilesHandler
+	Once     sync.Once
+)
+
+func NewHandler(userProfilesService UserProfilesService, authMiddleware *middlewares.AuthMiddleware) *userProfilesHandler {
+	Once.Do(
+		func() {
+			instance = &userProfilesHandler{
+				userProfilesService: userProfilesService,
+				authMiddleware:      authMiddleware,
+			}
+		},
+	)
+
+	return instance
+}
+
+func (h userProfilesHandler) RegisterRoutes(r *chi.Mux) {
+	m := h.authMiddleware
+	r.Route(
+		"/api/v1/me/profile", func(r chi.Router) {
+			// Private
+			r.Group(func(r chi.Router) {
+				r.Use(m.WithAuth, m.RequireRole(valueobjects.AnyRole...))
+				r.Get("/", h.handleGetMyProfile)
+				r.Patch("/", h.handleUpdateMyProfile)
+			})
+		},
+	)
+}
+
+func (h userProfilesHandler) handleGetMyProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	profile, err := h.userProfilesService.GetMyProfile(ctx)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, profile)
+}
+
+// handleUpdateMyProfile accepts either a JSON body or, to change the profile image,
+// a multipart form with the JSON in the "body" field and the file in "profile_image",
+// the same layout used by the onboarding.
+func (h userProfilesHandler) handleUpdateMyProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var input common.UpdateUserProfileRequest
+	var profileImage *multipart.FileHeader
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxProfileUploadSize); err != nil {
+			apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidRequestBody)
+			httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+			return
+		}
+
+		if body := r.FormValue("body"); body != "" {
+			if err := json.Unmarshal([]byte(body), &input); err != nil {
+				apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidJSON)
+				httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+				return
+			}
+		}
+
+		file, header, err := r.FormFile("profile_image")
+		if err != nil && !errors.Is(err, http.ErrMissingFile) {
+			apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidRequestBody)
+			httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+			return
+		}
+		if file != nil {
+			file.Close()
+			profileImage = header
+		}
+	} else if err := httphelpers.ReadRequestBody(w, r, &input); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	profile, apiErr := h.userProfilesService.UpdateMyProfile(ctx, input, profileImage)
+	if apiErr != nil {
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, profile)
+}
@@ -1,13 +1,37 @@
 package userprofiles
 
 import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/modules/accounts/subcategories"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/storage"
 	"context"
+	"log/slog"
+	"mime/multipart"
 
 	"github.com/jmoiron/sqlx"
 )
 
-type UserProfilesRepository interface {
-	CreateInitialProfileTx(ctx context.Context, tx *sqlx.Tx, userProfile *UserProfile) error
-	FindByUserID(ctx context.Context, userID string) (*UserProfile, error)
-	UpdateTx(ctx context.Context, tx *sqlx.Tx, userProfile *UserProfile) error
-}
+type (
+	UserProfilesRepository interface {
+		CreateInitialProfileTx(ctx context.Context, tx *sqlx.Tx, userProfile *UserProfile) error
+		FindByUserID(ctx context.Context, userID string) (*UserProfile, error)
+		UpdateTx(ctx context.Context, tx *sqlx.Tx, userProfile *UserProfile) error
+	}
+	UserProfilesService interface {
+		GetMyProfile(ctx context.Context) (*common.UserProfile, *exceptions.ApiError[string])
+		UpdateMyProfile(ctx context.Context, input common.UpdateUserProfileRequest, profileImage *multipart.FileHeader) (*common.UserProfile, *exceptions.ApiError[string])
+	}
+	userProfilesService struct {
+		db                *sqlx.DB
+		repository        UserProfilesRepository
+		subcategoriesRepo subcategories.SubcategoriesRepository
+		storageClient     *storage.StorageClient
+		logger            *slog.Logger
+	}
+	userProfilesHandler struct {
+		userProfilesService UserProfilesService
+		authMiddleware      *middlewares.AuthMiddleware
+	}
+)
@@ -0,0 +1,200 @@
+package userprofiles
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/modules/accounts/subcategories"
+	"conecta-mare-server/internal/server/middlewares"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/jwt"
+	"conecta-mare-server/pkg/storage"
+	"conecta-mare-server/pkg/valueobjects"
+	"context"
+	"fmt"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func NewService(
+	db *sqlx.DB,
+	repository UserProfilesRepository,
+	subcategoriesRepo subcategories.SubcategoriesRepository,
+	storageClient *storage.StorageClient,
+	logger *slog.Logger,
+) UserProfilesService {
+	return &userProfilesService{
+		db:                db,
+		repository:        repository,
+		subcategoriesRepo: subcategoriesRepo,
+		storageClient:     storageClient,
+		logger:            logger,
+	}
+}
+
+func (s *userProfilesService) GetMyProfile(ctx context.Context) (*common.UserProfile, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to get own user profile")
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	userProfile, err := s.repository.FindByUserID(ctx, c.UserID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to find user profile", "user_id", c.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if userProfile == nil {
+		s.logger.WarnContext(ctx, "user profile not found", "user_id", c.UserID)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrUserProfileNotFound)
+	}
+
+	return toResponse(userProfile), nil
+}
+
+// UpdateMyProfile applies a partial update to the signed user's profile. A new
+// profile image is stored under a fresh name, so cached copies of the old one are
+// not served, and the old object is removed once the profile points to the new one.
+func (s *userProfilesService) UpdateMyProfile(
+	ctx context.Context,
+	input common.UpdateUserProfileRequest,
+	profileImage *multipart.FileHeader,
+) (*common.UserProfile, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to update own user profile")
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	userProfile, err := s.repository.FindByUserID(ctx, c.UserID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to find user profile", "user_id", c.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if userProfile == nil {
+		s.logger.WarnContext(ctx, "user profile not found", "user_id", c.UserID)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrUserProfileNotFound)
+	}
+
+	// The onboarding is what creates services, projects and locations, and it only
+	// runs while the job description is empty.
+	if input.JobDescription != nil && !userProfile.IsOnboarded() {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusConflict, exceptions.ErrOnboardingPending)
+	}
+
+	if input.SubcategoryID != nil && *input.SubcategoryID != "" {
+		if c.Role != valueobjects.Professional {
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrClientCannotContainSubcat)
+		}
+
+		subcategory, err := s.subcategoriesRepo.GetByID(ctx, *input.SubcategoryID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to get subcategory", "subcategory_id", *input.SubcategoryID, "err", err)
+			return nil, exceptions.MakeGenericApiError()
+		}
+		if subcategory == nil {
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrSubcategoryNotFound)
+		}
+	}
+
+	changes := ProfileChanges{
+		FullName:       input.FullName,
+		SubcategoryID:  input.SubcategoryID,
+		JobDescription: input.JobDescription,
+		Phone:          input.Phone,
+		SocialLinks:    input.SocialLinks,
+	}
+
+	oldImage := userProfile.ProfileImage()
+	var newImageObject string
+	if profileImage != nil {
+		if !storage.IsImage(profileImage) {
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidImage)
+		}
+
+		newImageObject = fmt.Sprintf("profiles/profile_%s_%d", c.UserID, time.Now().UnixNano())
+		url, err := s.storageClient.UploadFile(newImageObject, profileImage)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to upload profile image", "user_id", c.UserID, "err", err)
+			return nil, exceptions.MakeGenericApiError()
+		}
+		changes.ProfileImage = &url
+	}
+
+	if err := userProfile.Edit(changes); err != nil {
+		s.removeObject(ctx, newImageObject)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+	}
+
+	if apiErr := s.updateTx(ctx, userProfile); apiErr != nil {
+		s.removeObject(ctx, newImageObject)
+		return nil, apiErr
+	}
+
+	if newImageObject != "" && oldImage != nil {
+		if objectName, ok := s.storageClient.ObjectNameFromURL(*oldImage); ok {
+			s.removeObject(ctx, objectName)
+		}
+	}
+
+	s.logger.InfoContext(ctx, "user profile updated with success", "user_id", c.UserID)
+	return toResponse(userProfile), nil
+}
+
+func (s *userProfilesService) updateTx(ctx context.Context, userProfile *UserProfile) *exceptions.ApiError[string] {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while starting transaction", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	defer tx.Rollback()
+
+	if err := s.repository.UpdateTx(ctx, tx, userProfile); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to update user profile", "user_id", userProfile.UserID(), "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.ErrorContext(ctx, "error while commiting user profile update", "user_id", userProfile.UserID(), "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	return nil
+}
+
+// removeObject deletes an image that is no longer referenced. Failures are only
+// logged, a leftover file does not affect the profile.
+func (s *userProfilesService) removeObject(ctx context.Context, objectName string) {
+	if objectName == "" {
+		return
+	}
+	if err := s.storageClient.RemoveObject(ctx, objectName); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to remove profile image", "object", objectName, "err", err)
+	}
+}
+
+func toResponse(userProfile *UserProfile) *common.UserProfile {
+	socialLinks := userProfile.SocialLinks()
+	if socialLinks == nil {
+		socialLinks = map[string]string{}
+	}
+
+	return &common.UserProfile{
+		ID:             userProfile.ID(),
+		UserID:         userProfile.UserID(),
+		FullName:       userProfile.FullName(),
+		SubcategoryID:  userProfile.SubcategoryID(),
+		ProfileImage:   userProfile.ProfileImage(),
+		JobDescription: userProfile.JobDescription(),
+		Phone:          userProfile.Phone(),
+		SocialLinks:    socialLinks,
+		CreatedAt:      userProfile.CreatedAt(),
+		UpdatedAt:      userProfile.UpdatedAt(),
+	}
+}
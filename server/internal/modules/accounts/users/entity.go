@@ -9,13 +9,17 @@ import (
 )
 
 type User struct {
-	id           string
-	email        string
-	role         valueobjects.Role
-	passwordHash string
-	createdAt    time.Time
-	updatedAt    *time.Time
-	deletedAt    *time.Time
+	id              string
+	email           string
+	phone           string
+	role            valueobjects.Role
+	passwordHash    string
+	emailVerifiedAt *time.Time
+	phoneVerifiedAt *time.Time
+	createdAt       time.Time
+	updatedAt       *time.Time
+	deletedAt       *time.Time
+	anonymizedAt    *time.Time
 }
 
 func New(
@@ -40,33 +44,117 @@ func New(
 	return &user, nil
 }
 
+// NewWithPhone creates an account identified by a phone number the user just proved
+// to own with a one-time code, so the number starts out verified.
+func NewWithPhone(
+	phone,
+	passwordHash string,
+	role valueobjects.Role,
+) (*User, error) {
+	now := time.Now()
+	user := User{
+		id:              uid.New("user"),
+		phone:           phone,
+		role:            role,
+		passwordHash:    passwordHash,
+		phoneVerifiedAt: &now,
+		createdAt:       now,
+		updatedAt:       nil,
+		deletedAt:       nil,
+	}
+
+	if err := user.validate(); err != nil {
+		return nil, exceptions.MakeApiError(err)
+	}
+
+	return &user, nil
+}
+
 func NewFromModel(m models.User) *User {
 	return &User{
-		id:           m.ID,
-		email:        m.Email,
-		passwordHash: m.PasswordHash,
-		role:         m.Role,
-		createdAt:    m.CreatedAt,
-		updatedAt:    m.UpdatedAt,
-		deletedAt:    m.DeletedAt,
+		id:              m.ID,
+		email:           valueOrEmpty(m.Email),
+		phone:           valueOrEmpty(m.Phone),
+		passwordHash:    m.PasswordHash,
+		role:            m.Role,
+		emailVerifiedAt: m.EmailVerifiedAt,
+		phoneVerifiedAt: m.PhoneVerifiedAt,
+		createdAt:       m.CreatedAt,
+		updatedAt:       m.UpdatedAt,
+		deletedAt:       m.DeletedAt,
+		anonymizedAt:    m.AnonymizedAt,
 	}
 }
 
 func (u *User) ToModel() models.User {
 	return models.User{
-		ID:           u.id,
-		Email:        u.email,
-		PasswordHash: u.passwordHash,
-		Role:         u.role,
-		CreatedAt:    u.createdAt,
-		UpdatedAt:    u.updatedAt,
-		DeletedAt:    u.deletedAt,
+		ID:              u.id,
+		Email:           nilIfEmpty(u.email),
+		Phone:           nilIfEmpty(u.phone),
+		PasswordHash:    u.passwordHash,
+		Role:            u.role,
+		EmailVerifiedAt: u.emailVerifiedAt,
+		PhoneVerifiedAt: u.phoneVerifiedAt,
+		CreatedAt:       u.createdAt,
+		UpdatedAt:       u.updatedAt,
+		DeletedAt:       u.deletedAt,
+		AnonymizedAt:    u.anonymizedAt,
 	}
 }
 
+func (u *User) ChangePassword(passwordHash string) {
+	now := time.Now()
+	u.passwordHash = passwordHash
+	u.updatedAt = &now
+}
+
+func (u *User) ChangeRole(role valueobjects.Role) error {
+	if !role.IsValid() {
+		return exceptions.ErrInvalidRole
+	}
+	now := time.Now()
+	u.role = role
+	u.updatedAt = &now
+	return nil
+}
+
+// Delete only marks the account, personal data is erased once the grace period ends.
+func (u *User) Delete() {
+	now := time.Now()
+	u.deletedAt = &now
+	u.updatedAt = &now
+}
+
+func (u *User) VerifyEmail() {
+	now := time.Now()
+	u.emailVerifiedAt = &now
+	u.updatedAt = &now
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.emailVerifiedAt != nil
+}
+
+// VerifyPhone sets the login phone number once the user proved to own it.
+func (u *User) VerifyPhone(phone string) {
+	now := time.Now()
+	u.phone = phone
+	u.phoneVerifiedAt = &now
+	u.updatedAt = &now
+}
+
+func (u *User) IsPhoneVerified() bool {
+	return u.phone != "" && u.phoneVerifiedAt != nil
+}
+
 func (u *User) validate() error {
-	if _, err := valueobjects.NewEmail(u.email); err != nil {
-		return err
+	if u.email == "" && u.phone == "" {
+		return exceptions.ErrEmailOrPhoneRequired
+	}
+	if u.email != "" {
+		if _, err := valueobjects.NewEmail(u.email); err != nil {
+			return err
+		}
 	}
 	if !u.role.IsValid() {
 		return exceptions.ErrInvalidRole
@@ -82,6 +170,18 @@ func (u *User) Email() string {
 	return u.email
 }
 
+func (u *User) Phone() string {
+	return u.phone
+}
+
+// LoginIdentifier is what the user signs in with, used to key login lockouts.
+func (u *User) LoginIdentifier() string {
+	if u.email != "" {
+		return u.email
+	}
+	return u.phone
+}
+
 func (u *User) PasswordHash() string {
 	return u.passwordHash
 }
@@ -90,6 +190,10 @@ func (u *User) Role() valueobjects.Role {
 	return u.role
 }
 
+func (u *User) EmailVerifiedAt() *time.Time {
+	return u.emailVerifiedAt
+}
+
 func (u *User) CreatedAt() time.Time {
 	return u.createdAt
 }
@@ -101,3 +205,21 @@ func (u *User) UpdatedAt() *time.Time {
 func (u *User) DeletedAt() *time.Time {
 	return u.deletedAt
 }
+
+func (u *User) AnonymizedAt() *time.Time {
+	return u.anonymizedAt
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func nilIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
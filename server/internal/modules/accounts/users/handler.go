@@ -6,10 +6,15 @@ import (
 	"conecta-mare-server/pkg/exceptions"
 	"conecta-mare-server/pkg/httphelpers"
 	"conecta-mare-server/pkg/jwt"
+	"conecta-mare-server/pkg/lockout"
 	"conecta-mare-server/pkg/security"
+	"conecta-mare-server/pkg/valueobjects"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
 	"sync"
 
 	"github.com/go-chi/chi/v5"
@@ -20,12 +25,12 @@ var (
 	Once     sync.Once
 )
 
-func NewHandler(usersService UsersService, accessKey string) *userHandler {
+func NewHandler(usersService UsersService, authMiddleware *middlewares.AuthMiddleware) *userHandler {
 	Once.Do(
 		func() {
 			instance = &userHandler{
-				usersService: usersService,
-				accessKey:    accessKey,
+				usersService:   usersService,
+				authMiddleware: authMiddleware,
 			}
 		},
 	)
@@ -34,18 +39,42 @@ func NewHandler(usersService UsersService, accessKey string) *userHandler {
 }
 
 func (h userHandler) RegisterRoutes(r *chi.Mux) {
-	m := middlewares.NewWithAuth(h.accessKey)
+	m := h.authMiddleware
 	r.Route(
 		"/api/v1/users", func(r chi.Router) {
 			// Public
 			r.Post("/register", h.handleRegister)
 			r.Post("/login", h.handleLogin)
+			r.Post("/refresh", h.handleRefresh)
+			r.Post("/login/2fa", h.handleTwoFactorLogin)
+			r.Post("/phone/code", h.handleRequestPhoneCode)
+			r.Post("/phone/register", h.handlePhoneRegister)
+			r.Post("/phone/login", h.handlePhoneLogin)
+			r.Post("/oidc/authorize", h.handleOIDCAuthorize)
+			r.Post("/oidc/callback", h.handleOIDCCallback)
+			r.Post("/forgot-password", h.handleForgotPassword)
+			r.Post("/reset-password", h.handleResetPassword)
+			r.Post("/verify-email", h.handleConfirmEmail)
 			r.Get("/professionals", h.handleGetProfessionals)
 			r.Get("/professionals/{user_id}", h.handleGetProfessionalByID)
 
 			// Private
-			r.With(m.WithAuth).Patch("/logout", h.handleLogout)
-			r.With(m.WithAuth).Get("/", h.handleGetSigned)
+			r.Group(func(r chi.Router) {
+				r.Use(m.WithAuth, m.RequireRole(valueobjects.AnyRole...))
+				r.Patch("/logout", h.handleLogout)
+				r.Patch("/password", h.handleChangePassword)
+				r.Get("/", h.handleGetSigned)
+				r.Delete("/", h.handleDeleteAccount)
+				r.Get("/sessions", h.handleGetSessions)
+				r.Delete("/sessions", h.handleRevokeAllSessions)
+				r.Delete("/sessions/{session_id}", h.handleRevokeSession)
+				r.Post("/verify-email/resend", h.handleResendVerificationEmail)
+				r.Post("/phone/verify", h.handleVerifyPhone)
+			})
+
+			// Admin
+			r.With(m.WithAuth, m.RequireRole(valueobjects.Admin)).Patch("/{user_id}/role", h.handleChangeRole)
+			r.With(m.WithAuth, m.RequireRole(valueobjects.Admin)).Get("/professionals/ranking", h.handleExplainProfessionalsRanking)
 		},
 	)
 }
@@ -60,6 +89,12 @@ func (h userHandler) handleRegister(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !req.Role.IsSelfAssignable() {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidRole)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
 	if req.Password != req.ConfirmPassword {
 		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrPasswordMatch)
 		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
@@ -102,43 +137,411 @@ func (h userHandler) handleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, loginErr := h.usersService.Login(ctx, common.LoginUserRequest{Email: body.Email, Password: body.Password})
+	response, loginErr := h.usersService.Login(ctx, common.LoginUserRequest{
+		Email:     body.Email,
+		Password:  body.Password,
+		UserAgent: r.UserAgent(),
+		IPAddress: httphelpers.ReadClientIP(r),
+	})
 	if loginErr != nil {
-		fmt.Println(loginErr)
+		var locked *lockout.LockedError
+		if errors.As(loginErr.Err, &locked) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		}
 		httphelpers.WriteJSON(w, loginErr.Code, loginErr)
 		return
 	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     "refresh_token",
-		Value:    *response.RefreshToken,
-		HttpOnly: true,
-		Secure:   true,
-		SameSite: http.SameSiteNoneMode,
-		Path:     "/",
-		MaxAge:   int(jwt.RefreshTokenDuration.Seconds()),
+	if response.RefreshToken != nil {
+		setRefreshTokenCookie(w, *response.RefreshToken)
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, response)
+}
+
+func (h userHandler) handleTwoFactorLogin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.TwoFactorLoginRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	response, loginErr := h.usersService.VerifyTwoFactorLogin(ctx, common.TwoFactorLoginRequest{
+		ChallengeToken: body.ChallengeToken,
+		Code:           body.Code,
+		RecoveryCode:   body.RecoveryCode,
+		UserAgent:      r.UserAgent(),
+		IPAddress:      httphelpers.ReadClientIP(r),
+	})
+	if loginErr != nil {
+		httphelpers.WriteJSON(w, loginErr.Code, loginErr)
+		return
+	}
+
+	setRefreshTokenCookie(w, *response.RefreshToken)
+
+	httphelpers.WriteJSON(w, http.StatusOK, response)
+}
+
+func (h userHandler) handleRequestPhoneCode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.PhoneCodeRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if err := h.usersService.RequestPhoneCode(ctx, body); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteSuccess(w, http.StatusOK)
+}
+
+func (h userHandler) handlePhoneRegister(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.PhoneRegisterRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	response, registerErr := h.usersService.PhoneRegister(ctx, common.PhoneRegisterRequest{
+		FullName:  body.FullName,
+		Phone:     body.Phone,
+		Code:      body.Code,
+		Role:      body.Role,
+		UserAgent: r.UserAgent(),
+		IPAddress: httphelpers.ReadClientIP(r),
+	})
+	if registerErr != nil {
+		httphelpers.WriteJSON(w, registerErr.Code, registerErr)
+		return
+	}
+
+	setRefreshTokenCookie(w, *response.RefreshToken)
+
+	httphelpers.WriteJSON(w, http.StatusCreated, response)
+}
+
+func (h userHandler) handlePhoneLogin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.PhoneLoginRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	response, loginErr := h.usersService.PhoneLogin(ctx, common.PhoneLoginRequest{
+		Phone:     body.Phone,
+		Code:      body.Code,
+		UserAgent: r.UserAgent(),
+		IPAddress: httphelpers.ReadClientIP(r),
 	})
+	if loginErr != nil {
+		var locked *lockout.LockedError
+		if errors.As(loginErr.Err, &locked) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		}
+		httphelpers.WriteJSON(w, loginErr.Code, loginErr)
+		return
+	}
+
+	if response.RefreshToken != nil {
+		setRefreshTokenCookie(w, *response.RefreshToken)
+	}
 
 	httphelpers.WriteJSON(w, http.StatusOK, response)
 }
 
+func (h userHandler) handleVerifyPhone(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.PhoneVerifyRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if err := h.usersService.VerifyPhone(ctx, body); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteSuccess(w, http.StatusOK)
+}
+
+func (h userHandler) handleOIDCAuthorize(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.OIDCAuthorizeRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	response, err := h.usersService.OIDCAuthorize(ctx, body)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, response)
+}
+
+func (h userHandler) handleOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.OIDCCallbackRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	response, loginErr := h.usersService.OIDCLogin(ctx, common.OIDCCallbackRequest{
+		Code:      body.Code,
+		State:     body.State,
+		UserAgent: r.UserAgent(),
+		IPAddress: httphelpers.ReadClientIP(r),
+	})
+	if loginErr != nil {
+		httphelpers.WriteJSON(w, loginErr.Code, loginErr)
+		return
+	}
+
+	if response.RefreshToken != nil {
+		setRefreshTokenCookie(w, *response.RefreshToken)
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, response)
+}
+
+func (h userHandler) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	refreshToken := readRefreshToken(r)
+	if refreshToken == "" {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrRefreshTokenNotFound)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	response, refreshErr := h.usersService.Refresh(ctx, common.RefreshTokenRequest{
+		RefreshToken: refreshToken,
+		UserAgent:    r.UserAgent(),
+		IPAddress:    httphelpers.ReadClientIP(r),
+	})
+	if refreshErr != nil {
+		if refreshErr.Code == http.StatusUnauthorized {
+			clearRefreshTokenCookie(w)
+		}
+		httphelpers.WriteJSON(w, refreshErr.Code, refreshErr)
+		return
+	}
+
+	setRefreshTokenCookie(w, *response.RefreshToken)
+
+	httphelpers.WriteJSON(w, http.StatusOK, response)
+}
+
+func (h userHandler) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.ForgotPasswordRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if _, err := valueobjects.NewEmail(body.Email); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrEmailInvalid)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if err := h.usersService.ForgotPassword(ctx, body); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteSuccess(w, http.StatusAccepted)
+}
+
+func (h userHandler) handleResetPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.ResetPasswordRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if body.Token == "" {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidResetToken)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if body.Password != body.ConfirmPassword {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrPasswordMatch)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if err := security.ValidatePassword(body.Password); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if err := h.usersService.ResetPassword(ctx, body); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	clearRefreshTokenCookie(w)
+
+	httphelpers.WriteSuccess(w, http.StatusOK)
+}
+
+func (h userHandler) handleConfirmEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.ConfirmEmailRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if body.Token == "" {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidVerificationToken)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if err := h.usersService.ConfirmEmail(ctx, body); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteSuccess(w, http.StatusOK)
+}
+
+func (h userHandler) handleResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := h.usersService.ResendVerificationEmail(ctx); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteSuccess(w, http.StatusAccepted)
+}
+
+func (h userHandler) handleChangePassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.ChangePasswordRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if body.Password != body.ConfirmPassword {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrPasswordMatch)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if err := security.ValidatePassword(body.Password); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if err := h.usersService.ChangePassword(ctx, body); err != nil {
+		var locked *lockout.LockedError
+		if errors.As(err.Err, &locked) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		}
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteSuccess(w, http.StatusOK)
+}
+
+func (h userHandler) handleDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.DeleteAccountRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if err := h.usersService.DeleteAccount(ctx, body); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	clearRefreshTokenCookie(w)
+
+	httphelpers.WriteSuccess(w, http.StatusOK)
+}
+
+func (h userHandler) handleChangeRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body common.ChangeRoleRequest
+	if err := httphelpers.ReadRequestBody(w, r, &body); err != nil {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if !body.Role.IsValid() {
+		apiErr := exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidRole)
+		httphelpers.WriteJSON(w, apiErr.Code, apiErr)
+		return
+	}
+
+	if err := h.usersService.ChangeRole(ctx, chi.URLParam(r, "user_id"), body.Role); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteSuccess(w, http.StatusOK)
+}
+
 func (h userHandler) handleLogout(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	err := h.usersService.Logout(ctx)
 	if err != nil {
-		httphelpers.WriteJSON(w, err.Code, err.Err)
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
 	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     "refresh_token",
-		Value:    "",
-		Path:     "/",
-		MaxAge:   -1,
-		HttpOnly: true,
-		Secure:   true,
-		SameSite: http.SameSiteStrictMode,
-	})
+	clearRefreshTokenCookie(w)
 
 	httphelpers.WriteSuccess(w, http.StatusOK)
 }
@@ -160,20 +563,98 @@ func (h userHandler) handleGetSigned(w http.ResponseWriter, r *http.Request) {
 	httphelpers.WriteJSON(w, http.StatusOK, user)
 }
 
+func (h userHandler) handleGetSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	includeInactive := httphelpers.ReadQueryBool(r.URL.Query(), "include_inactive", false)
+
+	sessions, err := h.usersService.GetSessions(ctx, readRefreshToken(r), includeInactive)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteJSON(w, http.StatusOK, map[string][]common.Session{"sessions": sessions})
+}
+
+func (h userHandler) handleRevokeSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "session_id")
+
+	if err := h.usersService.RevokeSession(ctx, sessionID); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	httphelpers.WriteSuccess(w, http.StatusOK)
+}
+
+func (h userHandler) handleRevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := h.usersService.RevokeAllSessions(ctx); err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
+		return
+	}
+
+	clearRefreshTokenCookie(w)
+
+	httphelpers.WriteSuccess(w, http.StatusOK)
+}
+
 func (h userHandler) handleGetProfessionals(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	professionals, err := h.usersService.GetProfessionals(ctx)
+	page, err := h.usersService.GetProfessionals(ctx, readProfessionalsFilter(r.URL.Query()))
 	if err != nil {
-		httphelpers.WriteJSON(w, err.Code, err.Error())
+		httphelpers.WriteJSON(w, err.Code, err)
 		return
 	}
-	if professionals == nil {
-		httphelpers.WriteJSON(w, http.StatusNoContent, map[string]string{"message": "any professional found"})
+
+	httphelpers.WriteJSON(w, http.StatusOK, page)
+}
+
+// handleExplainProfessionalsRanking lists professionals by relevance with the score
+// breakdown of each one, to tune the ranking weights. No response rate is recorded
+// yet, so that signal is always listed with applies set to false.
+func (h userHandler) handleExplainProfessionalsRanking(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter := readProfessionalsFilter(r.URL.Query())
+	filter.Sort = ProfessionalsSortRelevance
+	filter.Explain = true
+
+	page, err := h.usersService.GetProfessionals(ctx, filter)
+	if err != nil {
+		httphelpers.WriteJSON(w, err.Code, err)
 		return
 	}
 
-	httphelpers.WriteJSON(w, http.StatusOK, map[string][]*common.GetProfessionalsResponse{"professionals": professionals})
+	httphelpers.WriteJSON(w, http.StatusOK, page)
+}
+
+func readProfessionalsFilter(query url.Values) common.ProfessionalsFilter {
+	return common.ProfessionalsFilter{
+		CategoryID:    httphelpers.ReadQueryString(query, "category_id", ""),
+		SubcategoryID: httphelpers.ReadQueryString(query, "subcategory_id", ""),
+		CommunityID:   httphelpers.ReadQueryString(query, "community_id", ""),
+		MinPrice:      readOptionalQueryInt(query, "min_price"),
+		MaxPrice:      readOptionalQueryInt(query, "max_price"),
+		MinRating:     readOptionalQueryInt(query, "min_rating"),
+		Sort:          httphelpers.ReadQueryString(query, "sort", ProfessionalsSortNewest),
+		Cursor:        httphelpers.ReadQueryString(query, "cursor", ""),
+		Limit:         httphelpers.ReadQueryInt(query, "limit", defaultProfessionalsPageSize),
+	}
+}
+
+// readOptionalQueryInt returns nil when the parameter is missing or is not a number,
+// which ReadQueryInt alone cannot tell apart from a real value.
+func readOptionalQueryInt(query url.Values, key string) *int {
+	const unset = math.MinInt
+	value := httphelpers.ReadQueryInt(query, key, unset)
+	if value == unset {
+		return nil
+	}
+	return &value
 }
 
 func (h userHandler) handleGetProfessionalByID(w http.ResponseWriter, r *http.Request) {
@@ -192,3 +673,35 @@ func (h userHandler) handleGetProfessionalByID(w http.ResponseWriter, r *http.Re
 
 	httphelpers.WriteJSON(w, http.StatusOK, map[string]*common.GetProfessionalByIDResponse{"data": professional})
 }
+
+func readRefreshToken(r *http.Request) string {
+	cookie, err := r.Cookie("refresh_token")
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+func setRefreshTokenCookie(w http.ResponseWriter, refreshToken string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "refresh_token",
+		Value:    refreshToken,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteNoneMode,
+		Path:     "/",
+		MaxAge:   int(jwt.RefreshTokenDuration.Seconds()),
+	})
+}
+
+func clearRefreshTokenCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "refresh_token",
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
@@ -3,32 +3,114 @@ package users
 import (
 	"conecta-mare-server/internal/common"
 	"conecta-mare-server/internal/databases/postgres/models"
+	"conecta-mare-server/internal/modules/accounts/auditevents"
+	"conecta-mare-server/internal/modules/accounts/emailverifications"
+	"conecta-mare-server/internal/modules/accounts/identities"
+	"conecta-mare-server/internal/modules/accounts/oidcstates"
+	"conecta-mare-server/internal/modules/accounts/passwordresets"
+	"conecta-mare-server/internal/modules/accounts/phoneotps"
 	"conecta-mare-server/internal/modules/accounts/session"
+	"conecta-mare-server/internal/modules/accounts/twofactor"
 	"conecta-mare-server/internal/modules/accounts/userprofiles"
+	"conecta-mare-server/internal/server/middlewares"
 	"conecta-mare-server/pkg/exceptions"
 	"conecta-mare-server/pkg/jwt"
+	"conecta-mare-server/pkg/lockout"
+	"conecta-mare-server/pkg/mailer"
+	"conecta-mare-server/pkg/oidc"
+	"conecta-mare-server/pkg/ranking"
+	"conecta-mare-server/pkg/sms"
 	"conecta-mare-server/pkg/storage"
+	"conecta-mare-server/pkg/valueobjects"
 	"context"
 	"log/slog"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
+const (
+	ProfessionalsSortNewest = "newest"
+	ProfessionalsSortRating = "rating"
+	ProfessionalsSortPrice  = "price"
+	ProfessionalsSortName   = "name"
+	// ProfessionalsSortRelevance orders by the ranking engine, best first. Every matching
+	// professional is scored on each request, so it is only used when asked for.
+	ProfessionalsSortRelevance = "relevance"
+)
+
 type (
+	// ProfessionalsListFilter is the validated form of common.ProfessionalsFilter. When
+	// AfterID is set, only professionals after (AfterKey, AfterID) in the sort order are
+	// returned.
+	ProfessionalsListFilter struct {
+		OnlyVerified  bool
+		CategoryID    string
+		SubcategoryID string
+		CommunityID   string
+		MinPrice      *int
+		MaxPrice      *int
+		MinRating     *int
+		Sort          string
+		AfterKey      string
+		AfterID       string
+		Limit         int
+	}
+
+	// ProfessionalRankingRow is a listed professional with the facts the ranking
+	// engine scores. CommunityFit is a ranking.CommunityFit, nil without a community
+	// filter.
+	ProfessionalRankingRow struct {
+		common.GetProfessionalsResponse
+		Completeness float64    `db:"completeness"`
+		LastActiveAt *time.Time `db:"last_active_at"`
+		CommunityFit *int       `db:"community_fit"`
+	}
+
 	UsersRepository interface {
 		Register(ctx context.Context, tx *sqlx.Tx, user *User) error
+		UpdatePassword(ctx context.Context, user *User) error
+		UpdatePasswordTx(ctx context.Context, tx *sqlx.Tx, user *User) error
+		VerifyEmailTx(ctx context.Context, tx *sqlx.Tx, user *User) error
+		UpdateRole(ctx context.Context, user *User) error
+		UpdatePhone(ctx context.Context, user *User) error
+		IsEmailVerified(ctx context.Context, ID string) (bool, error)
 		GetByID(ctx context.Context, ID string) (*common.User, error)
+		GetModelByID(ctx context.Context, ID string) (*models.User, error)
 		GetByEmail(ctx context.Context, email string) (*models.User, error)
+		GetByPhone(ctx context.Context, phone string) (*models.User, error)
 		GetByRole(ctx context.Context, role string) ([]*models.User, error)
 		CountBySubcategoryIDs(ctx context.Context, subcategoryIDs []string) (map[string]int, error)
 		// Update(ctx context.Context, user *User) (*User, error)
 		DeleteByID(ctx context.Context, ID string) error
-		GetProfessionalUsers(ctx context.Context) ([]*common.GetProfessionalsResponse, error)
-		GetProfessionalByID(ctx context.Context, ID string) (*common.GetProfessionalByIDResponse, error)
+		GetPendingAnonymization(ctx context.Context, deletedBefore time.Time, limit int) ([]string, error)
+		AnonymizeTx(ctx context.Context, tx *sqlx.Tx, ID string) (bool, error)
+		ListProfessionals(ctx context.Context, filter ProfessionalsListFilter) ([]*common.GetProfessionalsResponse, int, error)
+		ListProfessionalsForRanking(ctx context.Context, filter ProfessionalsListFilter, limit int) ([]*ProfessionalRankingRow, float64, error)
+		GetProfessionalByID(ctx context.Context, ID string, onlyVerified bool) (*common.GetProfessionalByIDResponse, error)
 	}
 	UsersService interface {
 		Login(ctx context.Context, input common.LoginUserRequest) (*common.LoginUserResponse, *exceptions.ApiError[string])
+		VerifyTwoFactorLogin(ctx context.Context, input common.TwoFactorLoginRequest) (*common.LoginUserResponse, *exceptions.ApiError[string])
+		RequestPhoneCode(ctx context.Context, input common.PhoneCodeRequest) *exceptions.ApiError[string]
+		PhoneRegister(ctx context.Context, input common.PhoneRegisterRequest) (*common.LoginUserResponse, *exceptions.ApiError[string])
+		PhoneLogin(ctx context.Context, input common.PhoneLoginRequest) (*common.LoginUserResponse, *exceptions.ApiError[string])
+		VerifyPhone(ctx context.Context, input common.PhoneVerifyRequest) *exceptions.ApiError[string]
+		OIDCAuthorize(ctx context.Context, input common.OIDCAuthorizeRequest) (*common.OIDCAuthorizeResponse, *exceptions.ApiError[string])
+		OIDCLogin(ctx context.Context, input common.OIDCCallbackRequest) (*common.LoginUserResponse, *exceptions.ApiError[string])
+		RemoveExpiredOIDCStates(ctx context.Context)
 		Logout(ctx context.Context) *exceptions.ApiError[string]
+		Refresh(ctx context.Context, input common.RefreshTokenRequest) (*common.LoginUserResponse, *exceptions.ApiError[string])
+		GetSessions(ctx context.Context, refreshToken string, includeInactive bool) ([]common.Session, *exceptions.ApiError[string])
+		RevokeSession(ctx context.Context, sessionID string) *exceptions.ApiError[string]
+		RevokeAllSessions(ctx context.Context) *exceptions.ApiError[string]
+		ForgotPassword(ctx context.Context, input common.ForgotPasswordRequest) *exceptions.ApiError[string]
+		ResetPassword(ctx context.Context, input common.ResetPasswordRequest) *exceptions.ApiError[string]
+		ChangePassword(ctx context.Context, input common.ChangePasswordRequest) *exceptions.ApiError[string]
+		ConfirmEmail(ctx context.Context, input common.ConfirmEmailRequest) *exceptions.ApiError[string]
+		ResendVerificationEmail(ctx context.Context) *exceptions.ApiError[string]
+		IsEmailVerified(ctx context.Context, userID string) (bool, error)
+		ChangeRole(ctx context.Context, userID string, role valueobjects.Role) *exceptions.ApiError[string]
 		Register(ctx context.Context, input common.RegisterUserRequest) error
 		GetSigned(ctx context.Context) (*common.User, *exceptions.ApiError[string])
 		CountUsersBySubcategoryIDs(ctx context.Context, subcategoryIDs []string) (map[string]int, error)
@@ -36,7 +118,9 @@ type (
 		GetByEmail(ctx context.Context, email string) (*User, error)
 		// Updated(ctx context.Context common.)
 		DeleteByID(ctx context.Context, ID string) error
-		GetProfessionals(ctx context.Context) ([]*common.GetProfessionalsResponse, *exceptions.ApiError[string])
+		DeleteAccount(ctx context.Context, input common.DeleteAccountRequest) *exceptions.ApiError[string]
+		AnonymizeDeletedUsers(ctx context.Context) error
+		GetProfessionals(ctx context.Context, filter common.ProfessionalsFilter) (*common.ProfessionalsPage, *exceptions.ApiError[string])
 		GetProfessionalByID(ctx context.Context, ID string) (*common.GetProfessionalByIDResponse, *exceptions.ApiError[string])
 	}
 	userService struct {
@@ -44,12 +128,35 @@ type (
 		repository       UsersRepository
 		userProfilesRepo userprofiles.UserProfilesRepository
 		sessionService   session.SessionsService
+		passwordResets   passwordresets.PasswordResetsRepository
+		verifications    emailverifications.EmailVerificationsRepository
+		identities       identities.IdentitiesRepository
+		oidcStates       oidcstates.OIDCStatesRepository
+		phoneOTPs        phoneotps.PhoneOTPsRepository
 		tokenProvider    jwt.JWTProvider
-		storageClient    *storage.StorageClient
-		logger           *slog.Logger
+		loginGuard       *lockout.LoginGuard
+		twoFactor        twofactor.TwoFactorService
+		audit            auditevents.AuditEventsService
+		// oidcProvider is nil when single sign-on is not configured.
+		oidcProvider  *oidc.Provider
+		storageClient *storage.StorageClient
+		// privateStorage is the bucket without public access, holding certification proofs
+		// and data export archives.
+		privateStorage *storage.StorageClient
+		mailer         mailer.Mailer
+		smsSender      sms.SMSSender
+		appURL         string
+		// hideUnverified keeps professionals that did not confirm their email out of the listings.
+		hideUnverified bool
+		// ranker orders the professionals listing when sorted by relevance.
+		ranker *ranking.Engine
+		// deletionGracePeriod is how long a deleted account can still be restored by support
+		// before its personal data is erased.
+		deletionGracePeriod time.Duration
+		logger              *slog.Logger
 	}
 	userHandler struct {
-		usersService UsersService
-		accessKey    string
+		usersService   UsersService
+		authMiddleware *middlewares.AuthMiddleware
 	}
 )
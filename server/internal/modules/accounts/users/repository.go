@@ -3,11 +3,13 @@ package users
 import (
 	"conecta-mare-server/internal/common"
 	"conecta-mare-server/internal/databases/postgres/models"
+	"conecta-mare-server/pkg/ranking"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -29,23 +31,239 @@ func (ur *usersRepository) Register(ctx context.Context, tx *sqlx.Tx, user *User
 
 	query := `
 		INSERT INTO users (
-			id, email, password_hash, role, created_at, updated_at, deleted_at
+			id, email, phone, password_hash, role, email_verified_at, phone_verified_at, created_at, updated_at, deleted_at
 		) VALUES (
-			:id, :email, :password_hash, :role, :created_at, :updated_at, :deleted_at
+			:id, :email, :phone, :password_hash, :role, :email_verified_at, :phone_verified_at, :created_at, :updated_at, :deleted_at
 		)`
 
 	_, err := tx.NamedExecContext(ctx, query, modelUser)
 	return err
 }
 
+func (ur *usersRepository) UpdatePassword(ctx context.Context, user *User) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	modelUser := user.ToModel()
+
+	query := `
+		UPDATE users SET
+			password_hash = :password_hash,
+			updated_at = :updated_at
+		WHERE id = :id`
+
+	_, err := ur.db.NamedExecContext(ctx, query, modelUser)
+	return err
+}
+
+func (ur *usersRepository) UpdatePasswordTx(ctx context.Context, tx *sqlx.Tx, user *User) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	modelUser := user.ToModel()
+
+	query := `
+		UPDATE users SET
+			password_hash = :password_hash,
+			updated_at = :updated_at
+		WHERE id = :id`
+
+	_, err := tx.NamedExecContext(ctx, query, modelUser)
+	return err
+}
+
+func (ur *usersRepository) VerifyEmailTx(ctx context.Context, tx *sqlx.Tx, user *User) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	modelUser := user.ToModel()
+
+	query := `
+		UPDATE users SET
+			email_verified_at = :email_verified_at,
+			updated_at = :updated_at
+		WHERE id = :id`
+
+	_, err := tx.NamedExecContext(ctx, query, modelUser)
+	return err
+}
+
+func (ur *usersRepository) UpdateRole(ctx context.Context, user *User) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	modelUser := user.ToModel()
+
+	query := `
+		UPDATE users SET
+			role = :role,
+			updated_at = :updated_at
+		WHERE id = :id`
+
+	_, err := ur.db.NamedExecContext(ctx, query, modelUser)
+	return err
+}
+
+// IsEmailVerified also accepts accounts created by phone, whose verified number plays
+// the same role as a confirmed email.
+func (ur *usersRepository) IsEmailVerified(ctx context.Context, ID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var verified bool
+	err := ur.db.GetContext(
+		ctx,
+		&verified,
+		"SELECT EXISTS (SELECT 1 FROM users WHERE id = $1 AND (email_verified_at IS NOT NULL OR phone_verified_at IS NOT NULL))",
+		ID,
+	)
+	return verified, err
+}
+
+func (ur *usersRepository) UpdatePhone(ctx context.Context, user *User) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	modelUser := user.ToModel()
+
+	query := `
+		UPDATE users SET
+			phone = :phone,
+			phone_verified_at = :phone_verified_at,
+			updated_at = :updated_at
+		WHERE id = :id`
+
+	_, err := ur.db.NamedExecContext(ctx, query, modelUser)
+	return err
+}
+
+// DeleteByID soft deletes the user. Rows referencing it stay valid until AnonymizeTx runs.
 func (ur *usersRepository) DeleteByID(ctx context.Context, ID string) error {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
-	_, err := ur.db.ExecContext(ctx, "DELETE FROM users WHERE id = $1", ID)
+	_, err := ur.db.ExecContext(
+		ctx,
+		"UPDATE users SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL",
+		ID,
+	)
 	return err
 }
 
+func (ur *usersRepository) GetPendingAnonymization(ctx context.Context, deletedBefore time.Time, limit int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var IDs []string
+	err := ur.db.SelectContext(
+		ctx,
+		&IDs,
+		`
+		SELECT id
+		FROM users
+		WHERE deleted_at IS NOT NULL
+			AND deleted_at < $1
+			AND anonymized_at IS NULL
+		ORDER BY deleted_at
+		LIMIT $2
+		`,
+		deletedBefore,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return IDs, nil
+}
+
+// AnonymizeTx erases the personal data of a soft deleted user. The users row is
+// kept, without anything identifying, so reviews and metrics still reference it.
+func (ur *usersRepository) AnonymizeTx(ctx context.Context, tx *sqlx.Tx, ID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// Codes sent to the login phone are keyed by the number, so they go before it is cleared.
+	_, err := tx.ExecContext(
+		ctx,
+		`
+		DELETE FROM phone_otps
+		WHERE phone = (
+			SELECT phone
+			FROM users
+			WHERE id = $1
+				AND deleted_at IS NOT NULL
+				AND anonymized_at IS NULL
+		)
+		`,
+		ID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := tx.ExecContext(
+		ctx,
+		`
+		UPDATE users SET
+			email = 'deleted+' || id || '@anonymized.invalid',
+			password_hash = '',
+			email_verified_at = NULL,
+			phone = NULL,
+			phone_verified_at = NULL,
+			anonymized_at = NOW(),
+			updated_at = NOW()
+		WHERE id = $1
+			AND deleted_at IS NOT NULL
+			AND anonymized_at IS NULL
+		`,
+		ID,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	statements := []string{
+		`UPDATE user_profiles SET
+			full_name = NULL,
+			profile_image = NULL,
+			job_description = NULL,
+			phone = NULL,
+			social_links = NULL,
+			updated_at = NOW()
+		WHERE user_id = $1`,
+		"DELETE FROM locations WHERE user_profile_id IN (SELECT id FROM user_profiles WHERE user_id = $1)",
+		"DELETE FROM service_areas WHERE user_profile_id IN (SELECT id FROM user_profiles WHERE user_id = $1)",
+		"DELETE FROM certifications WHERE user_profile_id IN (SELECT id FROM user_profiles WHERE user_id = $1)",
+		"DELETE FROM projects WHERE user_profile_id IN (SELECT id FROM user_profiles WHERE user_id = $1)",
+		"DELETE FROM services WHERE user_profile_id IN (SELECT id FROM user_profiles WHERE user_id = $1)",
+		"UPDATE reviews SET client_user_id = NULL WHERE client_user_id = $1",
+		"DELETE FROM sessions WHERE user_id = $1",
+		"DELETE FROM password_reset_tokens WHERE user_id = $1",
+		"DELETE FROM email_verification_tokens WHERE user_id = $1",
+		"DELETE FROM data_exports WHERE user_id = $1",
+		"DELETE FROM user_identities WHERE user_id = $1",
+		"DELETE FROM two_factor_challenges WHERE user_id = $1",
+		"DELETE FROM recovery_codes WHERE user_id = $1",
+		"DELETE FROM totp_factors WHERE user_id = $1",
+		"UPDATE audit_events SET ip_address = NULL, user_agent = NULL WHERE actor_user_id = $1 OR target_user_id = $1",
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement, ID); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
 func (ur *usersRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
@@ -62,6 +280,22 @@ func (ur *usersRepository) GetByEmail(ctx context.Context, email string) (*model
 	return &user, nil
 }
 
+func (ur *usersRepository) GetByPhone(ctx context.Context, phone string) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var user models.User
+	err := ur.db.GetContext(ctx, &user, "SELECT * FROM users WHERE phone = $1", phone)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
 func (ur *usersRepository) GetByID(ctx context.Context, ID string) (*common.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
@@ -69,12 +303,14 @@ func (ur *usersRepository) GetByID(ctx context.Context, ID string) (*common.User
 	query := `
 		SELECT 
 			u.id,
-			u.email,
+			COALESCE(u.email, '') AS email,
+			u.phone,
 			u.role,
 			up.full_name,
 			up.profile_image,
 			up.job_description,
-			subc."name"
+			subc."name",
+			u.email_verified_at
 		FROM users u
 		INNER JOIN user_profiles up ON up.user_id = u.id
 		left JOIN subcategories subc ON subc.id = up.subcategory_id
@@ -93,6 +329,22 @@ func (ur *usersRepository) GetByID(ctx context.Context, ID string) (*common.User
 	return &user, nil
 }
 
+func (ur *usersRepository) GetModelByID(ctx context.Context, ID string) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var user models.User
+	err := ur.db.GetContext(ctx, &user, "SELECT * FROM users WHERE id = $1", ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
 func (ur *usersRepository) GetByRole(ctx context.Context, role string) ([]*models.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
@@ -148,42 +400,250 @@ func (r *usersRepository) CountBySubcategoryIDs(ctx context.Context, subcategory
 	return counts, nil
 }
 
-func (ur *usersRepository) GetProfessionalUsers(ctx context.Context) ([]*common.GetProfessionalsResponse, error) {
+// professionalsSort describes how a listing order is applied. The sort key is also
+// returned as text, so it can go in the cursor and be cast back on the next page.
+type professionalsSort struct {
+	expression string
+	descending bool
+	cast       string
+}
+
+var professionalsSorts = map[string]professionalsSort{
+	ProfessionalsSortNewest: {expression: "p.created_at", descending: true, cast: "timestamp"},
+	ProfessionalsSortRating: {expression: "p.rating", descending: true, cast: "float8"},
+	ProfessionalsSortPrice:  {expression: "p.sort_price", descending: false, cast: "int"},
+	ProfessionalsSortName:   {expression: "p.sort_name", descending: false, cast: "text"},
+}
+
+// professionalsFrom builds the FROM and WHERE clauses shared by the listings, adding
+// its parameters through arg.
+func professionalsFrom(filter ProfessionalsListFilter, arg func(value any) string) string {
+	conditions := []string{
+		`u."role" = 'professional'`,
+		"up.job_description IS NOT NULL",
+		"u.deleted_at IS NULL",
+	}
+	if filter.OnlyVerified {
+		conditions = append(conditions, "(u.email_verified_at IS NOT NULL OR u.phone_verified_at IS NOT NULL)")
+	}
+	if filter.CategoryID != "" {
+		conditions = append(conditions, "s.category_id = "+arg(filter.CategoryID))
+	}
+	if filter.SubcategoryID != "" {
+		conditions = append(conditions, "up.subcategory_id = "+arg(filter.SubcategoryID))
+	}
+	if filter.CommunityID != "" {
+		placeholder := arg(filter.CommunityID)
+		conditions = append(conditions, fmt.Sprintf(
+			"(l.community_id = %s OR EXISTS (SELECT 1 FROM service_areas sa WHERE sa.user_profile_id = up.id AND sa.community_id = %s))",
+			placeholder,
+			placeholder,
+		))
+	}
+	if filter.MinPrice != nil || filter.MaxPrice != nil {
+		priceConditions := []string{"se.user_profile_id = up.id", "se.deleted_at IS NULL"}
+		if filter.MinPrice != nil {
+			priceConditions = append(priceConditions, "se.price >= "+arg(*filter.MinPrice))
+		}
+		if filter.MaxPrice != nil {
+			priceConditions = append(priceConditions, "se.price <= "+arg(*filter.MaxPrice))
+		}
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM services se WHERE "+strings.Join(priceConditions, " AND ")+")")
+	}
+	if filter.MinRating != nil {
+		conditions = append(conditions, "COALESCE(r.rating, 0) >= "+arg(*filter.MinRating))
+	}
+
+	return `
+			FROM users u
+			INNER JOIN user_profiles up ON up.user_id = u.id
+			INNER JOIN subcategories s ON s.id = up.subcategory_id
+			INNER JOIN locations l ON l.user_profile_id = up.id
+			INNER JOIN communities cm ON cm.id = l.community_id
+			LEFT JOIN LATERAL (
+				SELECT ROUND(AVG(rv.rating)::NUMERIC, 1)::FLOAT8 AS rating, COUNT(*) AS review_count
+				FROM reviews rv
+				WHERE rv.user_id = u.id
+			) r ON TRUE
+			LEFT JOIN LATERAL (
+				SELECT MIN(se.price) AS min_price
+				FROM services se
+				WHERE se.user_profile_id = up.id AND se.deleted_at IS NULL
+			) pr ON TRUE
+			WHERE ` + strings.Join(conditions, " AND ")
+}
+
+// ListProfessionals returns a page of onboarded professionals and how many match the
+// filter in total. The community filter matches both the home address and the
+// declared service areas.
+func (ur *usersRepository) ListProfessionals(ctx context.Context, filter ProfessionalsListFilter) ([]*common.GetProfessionalsResponse, int, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
+	sort, ok := professionalsSorts[filter.Sort]
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown professionals sort %q", filter.Sort)
+	}
+
+	args := []any{}
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	from := professionalsFrom(filter, arg)
+
+	var total int
+	if err := ur.db.GetContext(ctx, &total, "SELECT COUNT(*)"+from, args...); err != nil {
+		return nil, 0, err
+	}
+
+	// Professionals without services sort after every priced one.
+	query := `
+		WITH p AS (
+			SELECT
+					u.id AS user_id,
+					up.full_name,
+					up.profile_image,
+					up.job_description,
+					COALESCE(r.rating, 0) AS rating,
+					COALESCE(r.review_count, 0) AS review_count,
+					pr.min_price,
+					cm."name" AS location,
+					ARRAY(
+						SELECT sacm.name
+						FROM service_areas sa
+						INNER JOIN communities sacm ON sacm.id = sa.community_id
+						WHERE sa.user_profile_id = up.id
+						ORDER BY sacm.name
+					) AS service_areas,
+					u.created_at,
+					COALESCE(pr.min_price, 2147483647) AS sort_price,
+					LOWER(COALESCE(up.full_name, '')) AS sort_name` + from + `
+		)
+		SELECT
+				p.user_id, p.full_name, p.profile_image, p.job_description, p.rating, p.review_count,
+				p.min_price, p.location, p.service_areas, ` + sort.expression + `::TEXT AS sort_key
+		FROM p`
+
+	direction, comparison := "ASC", ">"
+	if sort.descending {
+		direction, comparison = "DESC", "<"
+	}
+	if filter.AfterID != "" {
+		query += fmt.Sprintf(
+			" WHERE (%s, p.user_id) %s (%s::%s, %s)",
+			sort.expression,
+			comparison,
+			arg(filter.AfterKey),
+			sort.cast,
+			arg(filter.AfterID),
+		)
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, p.user_id %s LIMIT %s", sort.expression, direction, direction, arg(filter.Limit))
+
 	var professionals []*common.GetProfessionalsResponse
-	err := ur.db.SelectContext(
-		ctx,
-		&professionals,
-		`
-			SELECT 
-					u.id as user_id,
+	if err := ur.db.SelectContext(ctx, &professionals, query, args...); err != nil {
+		return nil, 0, err
+	}
+
+	return professionals, total, nil
+}
+
+// ListProfessionalsForRanking returns up to limit professionals matching the filter with
+// the facts the ranking engine scores, plus the average of all reviews. Ranked listings
+// are sorted in Go, so they cannot be paged in the database; instead the candidates are
+// cut to the ones closest to the community, then with the most complete and best rated
+// profiles, then the most recently active.
+func (ur *usersRepository) ListProfessionalsForRanking(ctx context.Context, filter ProfessionalsListFilter, limit int) ([]*ProfessionalRankingRow, float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var meanRating float64
+	if err := ur.db.GetContext(ctx, &meanRating, "SELECT COALESCE(AVG(rating), 0)::FLOAT8 FROM reviews"); err != nil {
+		return nil, 0, err
+	}
+
+	args := []any{}
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	from := professionalsFrom(filter, arg)
+
+	communityFit := "NULL::INT"
+	if filter.CommunityID != "" {
+		placeholder := arg(filter.CommunityID)
+		communityFit = fmt.Sprintf(`
+					CASE WHEN l.community_id = %[1]s THEN %[2]d ELSE (
+						SELECT CASE WHEN COALESCE(sa.travel_surcharge, 0) > 0 THEN %[4]d ELSE %[3]d END
+						FROM service_areas sa
+						WHERE sa.user_profile_id = up.id AND sa.community_id = %[1]s
+					) END`,
+			placeholder,
+			ranking.LivesInCommunity,
+			ranking.ServesCommunity,
+			ranking.ServesCommunityWithSurcharge,
+		)
+	}
+
+	// Completeness counts six things clients look at: a photo, a description long
+	// enough to say something, a contact phone, services, projects and a verified
+	// certification.
+	query := `
+		WITH c AS (
+			SELECT
+					u.id AS user_id,
 					up.full_name,
 					up.profile_image,
 					up.job_description,
-					5 as rating,
-					cm."name" as location
-			FROM users u
-			INNER JOIN user_profiles up ON up.user_id = u.id
-			inner JOIN subcategories s ON s.id = up.subcategory_id 
-			inner join locations l on l.user_profile_id = up.id 
-			inner join communities cm on cm.id = l.community_id
-			WHERE u."role" = 'professional' 
-			and up.job_description is not null
-			AND u.deleted_at IS NULL;
-		`)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
+					COALESCE(r.rating, 0) AS rating,
+					COALESCE(r.review_count, 0) AS review_count,
+					pr.min_price,
+					cm."name" AS location,
+					ARRAY(
+						SELECT sacm.name
+						FROM service_areas sa
+						INNER JOIN communities sacm ON sacm.id = sa.community_id
+						WHERE sa.user_profile_id = up.id
+						ORDER BY sacm.name
+					) AS service_areas,
+					(
+						(COALESCE(up.profile_image, '') <> '')::INT +
+						(LENGTH(up.job_description) >= 80)::INT +
+						(COALESCE(up.phone, '') <> '')::INT +
+						(pr.min_price IS NOT NULL)::INT +
+						EXISTS (SELECT 1 FROM projects pj WHERE pj.user_profile_id = up.id)::INT +
+						EXISTS (
+							SELECT 1 FROM certifications ce WHERE ce.user_profile_id = up.id AND ce.status = 'verified'
+						)::INT
+					)::FLOAT8 / 6 AS completeness,
+					GREATEST(
+						up.created_at,
+						up.updated_at,
+						(SELECT MAX(COALESCE(ss.last_seen_at, ss.created_at)) FROM sessions ss WHERE ss.user_id = u.id),
+						(
+							SELECT MAX(GREATEST(se.created_at, se.updated_at))
+							FROM services se
+							WHERE se.user_profile_id = up.id AND se.deleted_at IS NULL
+						)
+					) AS last_active_at,
+					` + communityFit + ` AS community_fit` + from + `
+		)
+		SELECT * FROM c
+		ORDER BY c.community_fit ASC NULLS LAST, c.completeness + c.rating / 5 DESC, c.last_active_at DESC NULLS LAST, c.user_id
+		LIMIT ` + arg(limit)
+
+	var rows []*ProfessionalRankingRow
+	if err := ur.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, 0, err
 	}
 
-	return professionals, nil
+	return rows, meanRating, nil
 }
 
-func (ur *usersRepository) GetProfessionalByID(ctx context.Context, ID string) (*common.GetProfessionalByIDResponse, error) {
+func (ur *usersRepository) GetProfessionalByID(ctx context.Context, ID string, onlyVerified bool) (*common.GetProfessionalByIDResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
@@ -195,13 +655,15 @@ func (ur *usersRepository) GetProfessionalByID(ctx context.Context, ID string) (
 		`
 		SELECT 
 				u.id AS user_id,
-				u.email,
+				COALESCE(u.email, '') AS email,
 				up.full_name,
 				up.profile_image,
 				up.job_description,
 				up.phone,
 				up.social_links,
-				5 AS rating,
+				COALESCE((
+					SELECT ROUND(AVG(rv.rating)::NUMERIC, 1)::FLOAT8 FROM reviews rv WHERE rv.user_id = u.id
+				), 0) AS rating,
 				jsonb_build_object(
             'community_id', cm.id,
             'community_name', cm.name,
@@ -223,8 +685,18 @@ func (ur *usersRepository) GetProfessionalByID(ctx context.Context, ID string) (
 								'id', p.id,
 								'name', p.name,
 								'description', p.description,
+								'completed_at', CASE
+										WHEN p.completed_at IS NULL THEN NULL
+										ELSE TO_CHAR(p.completed_at, 'YYYY-MM-DD"T"HH24:MI:SS.MS"Z"')
+								END,
+								'tags', to_json(p.tags),
+								'service_id', (
+										SELECT ps.id FROM services ps
+										WHERE ps.id = p.service_id AND ps.deleted_at IS NULL
+								),
 								'images', COALESCE(images.images, '[]'::JSON)
 							)
+							ORDER BY p.completed_at DESC NULLS LAST, p.created_at DESC
 					)
 					FROM projects p
 					LEFT JOIN LATERAL (
@@ -234,6 +706,7 @@ func (ur *usersRepository) GetProfessionalByID(ctx context.Context, ID string) (
 											'url', pi.url,
 											'ordering', pi.ordering
 									)
+									ORDER BY pi.ordering
 							) AS images
 							FROM project_images pi
 							WHERE pi.project_id = p.id
@@ -250,11 +723,13 @@ func (ur *usersRepository) GetProfessionalByID(ctx context.Context, ID string) (
 									'end_date', CASE 
 											WHEN ce.end_date IS NULL THEN NULL
 											ELSE TO_CHAR(ce.end_date, 'YYYY-MM-DD"T"HH24:MI:SS.MS"Z"')
-									END
+									END,
+									'verified', ce.status = 'verified'
 							)
+							ORDER BY ce.status = 'verified' DESC, ce.start_date DESC
 					)
 					FROM certifications ce
-					WHERE ce.user_profile_id = up.id
+					WHERE ce.user_profile_id = up.id AND ce.status <> 'rejected'
 				) AS certifications,
 				(
 					SELECT json_agg(
@@ -266,6 +741,7 @@ func (ur *usersRepository) GetProfessionalByID(ctx context.Context, ID string) (
 									'own_location_price', se.own_location_price,
 									'images', COALESCE(images.images, '[]'::JSON)
 							)
+							ORDER BY se.ordering, se.created_at
 					)
 					FROM services se
 					LEFT JOIN LATERAL (
@@ -275,12 +751,26 @@ func (ur *usersRepository) GetProfessionalByID(ctx context.Context, ID string) (
 										'url', sei.url,
 										'ordering', sei.ordering
 									)
+									ORDER BY sei.ordering
 							) AS images
 							FROM service_images sei
 							WHERE sei.service_id = se.id
 					) images ON TRUE
-					WHERE se.user_profile_id = up.id
-				) AS services
+					WHERE se.user_profile_id = up.id AND se.deleted_at IS NULL
+				) AS services,
+				(
+					SELECT COALESCE(json_agg(
+							json_build_object(
+									'community_id', sacm.id,
+									'community_name', sacm.name,
+									'travel_surcharge', sa.travel_surcharge
+							)
+							ORDER BY sacm.name
+					), '[]'::JSON)
+					FROM service_areas sa
+					INNER JOIN communities sacm ON sacm.id = sa.community_id
+					WHERE sa.user_profile_id = up.id
+				) AS service_areas
 		FROM users u
 		INNER JOIN user_profiles up ON up.user_id = u.id
 		INNER JOIN subcategories sc ON sc.id = up.subcategory_id
@@ -289,9 +779,11 @@ func (ur *usersRepository) GetProfessionalByID(ctx context.Context, ID string) (
 		INNER JOIN communities cm ON cm.id = l.community_id
 		WHERE u.role = 'professional'
 				AND u.id = $1
-				AND u.deleted_at IS NULL;
+				AND u.deleted_at IS NULL
+				AND ($2 = false OR u.email_verified_at IS NOT NULL OR u.phone_verified_at IS NOT NULL);
 		`,
 		ID,
+		onlyVerified,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -303,6 +795,7 @@ func (ur *usersRepository) GetProfessionalByID(ctx context.Context, ID string) (
 	var projects []common.Project
 	var certifications []common.Certification
 	var services []common.Service
+	var serviceAreas []common.ServiceArea
 
 	if err := json.Unmarshal(raw.ProjectsJSON, &projects); err != nil {
 		return nil, fmt.Errorf("error decoding projects: %w", err)
@@ -313,6 +806,9 @@ func (ur *usersRepository) GetProfessionalByID(ctx context.Context, ID string) (
 	if err := json.Unmarshal(raw.ServicesJSON, &services); err != nil {
 		return nil, fmt.Errorf("error decoding services: %w", err)
 	}
+	if err := json.Unmarshal(raw.ServiceAreasJSON, &serviceAreas); err != nil {
+		return nil, fmt.Errorf("error decoding service areas: %w", err)
+	}
 
 	professional := &common.GetProfessionalByIDResponse{
 		UserID:         raw.UserID,
@@ -329,6 +825,7 @@ func (ur *usersRepository) GetProfessionalByID(ctx context.Context, ID string) (
 		Projects:       projects,
 		Certifications: certifications,
 		Services:       services,
+		ServiceAreas:   serviceAreas,
 	}
 
 	return professional, nil
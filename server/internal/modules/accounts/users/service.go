@@ -2,54 +2,250 @@ package users
 
 import (
 	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/modules/accounts/auditevents"
+	"conecta-mare-server/internal/modules/accounts/emailverifications"
+	"conecta-mare-server/internal/modules/accounts/identities"
+	"conecta-mare-server/internal/modules/accounts/oidcstates"
+	"conecta-mare-server/internal/modules/accounts/passwordresets"
+	"conecta-mare-server/internal/modules/accounts/phoneotps"
 	"conecta-mare-server/internal/modules/accounts/session"
+	"conecta-mare-server/internal/modules/accounts/twofactor"
 	"conecta-mare-server/internal/modules/accounts/userprofiles"
 	"conecta-mare-server/internal/server/middlewares"
 	"conecta-mare-server/pkg/exceptions"
 	"conecta-mare-server/pkg/jwt"
+	"conecta-mare-server/pkg/lockout"
+	"conecta-mare-server/pkg/mailer"
+	"conecta-mare-server/pkg/oidc"
+	"conecta-mare-server/pkg/ranking"
 	"conecta-mare-server/pkg/security"
+	"conecta-mare-server/pkg/sms"
 	"conecta-mare-server/pkg/storage"
 	"conecta-mare-server/pkg/valueobjects"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 )
 
+const (
+	// verificationResendInterval is the minimum wait between two verification emails.
+	verificationResendInterval = time.Minute
+	// verificationHourlyLimit caps how many verification emails a user may receive per hour.
+	verificationHourlyLimit = 5
+	// phoneCodeResendInterval is the minimum wait between two codes sent to a phone.
+	phoneCodeResendInterval = time.Minute
+	// phoneCodeHourlyLimit caps how many codes a phone may receive per hour.
+	phoneCodeHourlyLimit = 5
+
+	// Login methods kept in the audit log.
+	loginMethodPassword  = "password"
+	loginMethodTwoFactor = "two_factor"
+	loginMethodPhone     = "phone"
+	loginMethodOIDC      = "oidc"
+
+	defaultProfessionalsPageSize = 20
+	maxProfessionalsPageSize     = 50
+	// maxRankedCandidates bounds how many professionals a relevance listing scores per
+	// request. The database keeps the ones most likely to rank high.
+	maxRankedCandidates = 500
+
+	// defaultDeletionGracePeriod applies when no grace period is configured, so a missing
+	// setting never erases accounts right after they are deleted.
+	defaultDeletionGracePeriod = 30 * 24 * time.Hour
+)
+
 func NewService(
 	db *sqlx.DB,
 	repository UsersRepository,
 	userProfilesRepo userprofiles.UserProfilesRepository,
 	sessionsService session.SessionsService,
+	passwordResetsRepo passwordresets.PasswordResetsRepository,
+	emailVerificationsRepo emailverifications.EmailVerificationsRepository,
+	identitiesRepo identities.IdentitiesRepository,
+	oidcStatesRepo oidcstates.OIDCStatesRepository,
+	phoneOTPsRepo phoneotps.PhoneOTPsRepository,
 	storageClient *storage.StorageClient,
+	privateStorageClient *storage.StorageClient,
 	tokenProvider jwt.JWTProvider,
+	loginGuard *lockout.LoginGuard,
+	twoFactorService twofactor.TwoFactorService,
+	auditService auditevents.AuditEventsService,
+	oidcProvider *oidc.Provider,
+	mailer mailer.Mailer,
+	smsSender sms.SMSSender,
+	appURL string,
+	hideUnverified bool,
+	deletionGracePeriod time.Duration,
+	ranker *ranking.Engine,
 	logger *slog.Logger,
 ) UsersService {
+	if deletionGracePeriod <= 0 {
+		deletionGracePeriod = defaultDeletionGracePeriod
+	}
+
 	return &userService{
-		db:               db,
-		repository:       repository,
-		userProfilesRepo: userProfilesRepo,
-		sessionService:   sessionsService,
-		storageClient:    storageClient,
-		tokenProvider:    tokenProvider,
-		logger:           logger,
+		db:                  db,
+		repository:          repository,
+		userProfilesRepo:    userProfilesRepo,
+		sessionService:      sessionsService,
+		passwordResets:      passwordResetsRepo,
+		verifications:       emailVerificationsRepo,
+		identities:          identitiesRepo,
+		oidcStates:          oidcStatesRepo,
+		phoneOTPs:           phoneOTPsRepo,
+		storageClient:       storageClient,
+		privateStorage:      privateStorageClient,
+		tokenProvider:       tokenProvider,
+		loginGuard:          loginGuard,
+		twoFactor:           twoFactorService,
+		audit:               auditService,
+		oidcProvider:        oidcProvider,
+		mailer:              mailer,
+		smsSender:           smsSender,
+		appURL:              appURL,
+		hideUnverified:      hideUnverified,
+		deletionGracePeriod: deletionGracePeriod,
+		ranker:              ranker,
+		logger:              logger,
 	}
 }
 
+// DeleteByID soft deletes the user and ends every session right away.
 func (s *userService) DeleteByID(ctx context.Context, ID string) error {
-	panic("unimplemented")
+	s.logger.InfoContext(ctx, "attempting to delete user", "user_id", ID)
+
+	if err := s.repository.DeleteByID(ctx, ID); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to delete user", "user_id", ID, "err", err)
+		return err
+	}
+
+	if err := s.sessionService.DeactivateAllSessions(ctx, ID); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to deactivate all user sessions", "user_id", ID, "err", err)
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "user deleted, personal data will be erased after the grace period", "user_id", ID)
+	return nil
 }
 
 func (s *userService) GetByEmail(ctx context.Context, email string) (*User, error) {
-	panic("unimplemented")
+	s.logger.InfoContext(ctx, "attempting to get user by email", "email", email)
+
+	model, err := s.repository.GetByEmail(ctx, email)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get user by email", "email", email, "err", err)
+		return nil, err
+	}
+	if model == nil {
+		return nil, nil
+	}
+
+	return NewFromModel(*model), nil
 }
 
 func (s *userService) GetByID(ctx context.Context, ID string) (*User, error) {
-	panic("unimplemented")
+	s.logger.InfoContext(ctx, "attempting to get user by id", "user_id", ID)
+
+	model, err := s.repository.GetModelByID(ctx, ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get user by id", "user_id", ID, "err", err)
+		return nil, err
+	}
+	if model == nil {
+		return nil, nil
+	}
+
+	return NewFromModel(*model), nil
+}
+
+// DeleteAccount is the self-service deletion. The password is asked again so a
+// stolen access token is not enough to erase an account.
+func (s *userService) DeleteAccount(ctx context.Context, input common.DeleteAccountRequest) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to delete own account")
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	user, err := s.GetByID(ctx, c.UserID)
+	if err != nil {
+		return exceptions.MakeGenericApiError()
+	}
+	if user == nil || user.DeletedAt() != nil {
+		s.logger.WarnContext(ctx, "user not found", "user_id", c.UserID)
+		return exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrUserNotFound)
+	}
+
+	if !security.PasswordMatches(input.Password, user.PasswordHash()) {
+		s.logger.WarnContext(ctx, "wrong password on account deletion", "user_id", user.ID())
+		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrWrongCurrentPassword)
+	}
+
+	if err := s.DeleteByID(ctx, user.ID()); err != nil {
+		return exceptions.MakeGenericApiError()
+	}
+
+	return nil
+}
+
+// AnonymizeDeletedUsers erases the personal data of accounts deleted more than the
+// grace period ago, as required by the LGPD. Stored files go first so a failure
+// leaves the account pending and the next run tries again.
+func (s *userService) AnonymizeDeletedUsers(ctx context.Context) error {
+	IDs, err := s.repository.GetPendingAnonymization(ctx, time.Now().Add(-s.deletionGracePeriod), 50)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get users pending anonymization", "err", err)
+		return err
+	}
+
+	for _, ID := range IDs {
+		if err := s.anonymizeUser(ctx, ID); err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to anonymize user", "user_id", ID, "err", err)
+			continue
+		}
+		s.logger.InfoContext(ctx, "user personal data erased", "user_id", ID)
+	}
+
+	return nil
+}
+
+func (s *userService) anonymizeUser(ctx context.Context, userID string) error {
+	for _, prefix := range storage.UserObjectPrefixes(userID) {
+		if err := s.storageClient.RemoveByPrefix(ctx, prefix); err != nil {
+			return err
+		}
+	}
+	for _, prefix := range append(storage.PrivateUserObjectPrefixes(userID), storage.ExportPrefix(userID)) {
+		if err := s.privateStorage.RemoveByPrefix(ctx, prefix); err != nil {
+			return err
+		}
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := s.repository.AnonymizeTx(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (s *userService) Register(ctx context.Context, input common.RegisterUserRequest) error {
@@ -112,71 +308,105 @@ func (s *userService) Register(ctx context.Context, input common.RegisterUserReq
 	}
 
 	s.logger.InfoContext(ctx, "user and initial profile successfully created", "user_id", user.ID())
+
+	// The account already exists at this point, a failed delivery can be fixed with a resend.
+	if err := s.sendVerificationEmail(ctx, user.ID(), user.Email()); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to send verification email", "user_id", user.ID(), "err", err)
+	}
+
 	return nil
 }
 
-func (s *userService) Login(ctx context.Context, input common.LoginUserRequest) (*common.LoginUserResponse, *exceptions.ApiError[string]) {
-	s.logger.InfoContext(ctx, "attempting to login user, checking for existing user", "email", input.Email)
-
-	existingUser, err := s.repository.GetByEmail(ctx, input.Email)
+func (s *userService) sendVerificationEmail(ctx context.Context, userID, email string) error {
+	verificationToken, plainToken, err := emailverifications.New(userID)
 	if err != nil {
-		s.logger.ErrorContext(ctx, "error while attempting to query for existing users", "err", err)
-		return nil, exceptions.MakeGenericApiError()
+		return err
 	}
-	if existingUser == nil {
-		s.logger.InfoContext(ctx, "user was not found", "email", input.Email)
-		return nil, exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrUserNotFound)
+
+	if err := s.verifications.Create(ctx, verificationToken); err != nil {
+		return err
 	}
 
-	if existingUser.DeletedAt != nil {
-		s.logger.InfoContext(ctx, "user must be active to login", "email", input.Email)
-		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUserDisabled)
+	verifyURL := fmt.Sprintf("%s/verify-email?token=%s", strings.TrimRight(s.appURL, "/"), url.QueryEscape(plainToken))
+	message := mailer.Message{
+		To:      email,
+		Subject: "Confirme seu email - Conecta Maré",
+		Text: fmt.Sprintf(
+			"Bem-vindo ao Conecta Maré!\n\nConfirme seu email acessando o link abaixo em até 48 horas:\n%s\n\nSe você não criou uma conta, ignore este email.",
+			verifyURL,
+		),
+		HTML: fmt.Sprintf(
+			`<p>Bem-vindo ao Conecta Maré!</p><p><a href="%s">Clique aqui para confirmar seu email</a>. O link expira em 48 horas.</p><p>Se você não criou uma conta, ignore este email.</p>`,
+			verifyURL,
+		),
 	}
 
-	user := NewFromModel(*existingUser)
+	return s.mailer.Send(ctx, message)
+}
 
-	s.logger.InfoContext(ctx, "user found, attempting to verify password", "email", user.Email())
-	if ok := security.PasswordMatches(input.Password, user.PasswordHash()); !ok {
-		s.logger.ErrorContext(ctx, "unauthorized attempt to login", "email", input.Email)
-		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrInvalidLoginAttempt)
-	}
+func (s *userService) ConfirmEmail(ctx context.Context, input common.ConfirmEmailRequest) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to confirm user email")
 
-	err = s.sessionService.DeactivateAllSessions(ctx, user.ID())
+	verificationToken, err := s.verifications.GetByTokenHash(ctx, security.HashToken(input.Token))
 	if err != nil {
-		s.logger.ErrorContext(ctx, "error while attempting to deactivate all user sessions", "user_id", existingUser.ID, "err", err)
-		return nil, exceptions.MakeGenericApiError()
+		s.logger.ErrorContext(ctx, "error while attempting to get verification token", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if verificationToken == nil || !verificationToken.IsUsable() {
+		s.logger.WarnContext(ctx, "invalid or expired verification token")
+		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidVerificationToken)
 	}
 
-	accessToken, _, err := s.tokenProvider.GenerateAccessToken(user)
+	existingUser, err := s.repository.GetModelByID(ctx, verificationToken.UserID())
 	if err != nil {
-		s.logger.ErrorContext(ctx, "error while attempting to create user access token", "user", user, "err", err)
-		return nil, exceptions.MakeGenericApiError()
+		s.logger.ErrorContext(ctx, "error while attempting to get user by id", "user_id", verificationToken.UserID(), "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if existingUser == nil || existingUser.DeletedAt != nil {
+		s.logger.WarnContext(ctx, "verification token belongs to unknown or disabled user", "user_id", verificationToken.UserID())
+		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidVerificationToken)
 	}
 
-	refreshToken, claims, err := s.tokenProvider.GenerateRefreshToken(user)
+	user := NewFromModel(*existingUser)
+	if user.IsEmailVerified() {
+		s.logger.InfoContext(ctx, "user email already verified", "user_id", user.ID())
+		return nil
+	}
+	user.VerifyEmail()
+
+	tx, err := s.db.Beginx()
 	if err != nil {
-		s.logger.ErrorContext(ctx, "error while attempting to create user refresh token", "user", user, "err", err)
-		return nil, exceptions.MakeGenericApiError()
+		s.logger.ErrorContext(ctx, "error while starting transaction", "err", err)
+		return exceptions.MakeGenericApiError()
 	}
+	defer tx.Rollback()
 
-	_, err = s.sessionService.CreateSession(
-		ctx, common.CreateSessionRequest{UserID: user.ID(), JTI: claims.ID},
-	)
+	consumed, err := s.verifications.MarkUsedTx(ctx, tx, verificationToken.ID())
 	if err != nil {
-		s.logger.ErrorContext(ctx, "error while attempting to create session", "err", err)
-		return nil, exceptions.MakeGenericApiError()
+		s.logger.ErrorContext(ctx, "error while attempting to consume verification token", "user_id", user.ID(), "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if !consumed {
+		s.logger.WarnContext(ctx, "verification token was already used", "user_id", user.ID())
+		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidVerificationToken)
 	}
 
-	s.logger.InfoContext(ctx, "access token, refresh token and session created", "user_id", user.ID())
+	if err := s.repository.VerifyEmailTx(ctx, tx, user); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to verify user email", "user_id", user.ID(), "err", err)
+		return exceptions.MakeGenericApiError()
+	}
 
-	return &common.LoginUserResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, nil
+	if err := tx.Commit(); err != nil {
+		s.logger.ErrorContext(ctx, "error while commiting email verification transaction", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "user email verified with success", "user_id", user.ID())
+	return nil
 }
 
-func (s *userService) Logout(ctx context.Context) *exceptions.ApiError[string] {
-	s.logger.InfoContext(ctx, "attempting to logout user")
+func (s *userService) ResendVerificationEmail(ctx context.Context) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to resend verification email")
 
 	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
 	if !ok {
@@ -184,97 +414,1486 @@ func (s *userService) Logout(ctx context.Context) *exceptions.ApiError[string] {
 		return exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
 	}
 
-	s.logger.InfoContext(ctx, "destructured data from context, logging out user", "user_id", c.UserID)
-
-	activeSession, err := s.sessionService.GetActiveSessionByUserID(ctx, c.UserID)
+	existingUser, err := s.repository.GetModelByID(ctx, c.UserID)
 	if err != nil {
-		s.logger.ErrorContext(ctx, "error while attempting to get user active sessions", "user_id", c.UserID, "err", err)
+		s.logger.ErrorContext(ctx, "error while attempting to get user by id", "user_id", c.UserID, "err", err)
 		return exceptions.MakeGenericApiError()
 	}
+	if existingUser == nil {
+		s.logger.WarnContext(ctx, "user not found", "user_id", c.UserID)
+		return exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrUserNotFound)
+	}
 
-	if activeSession == nil {
-		s.logger.WarnContext(ctx, "active session not found for user", "user_id", c.UserID)
-		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrActiveSessionNotFound)
+	user := NewFromModel(*existingUser)
+	if user.Email() == "" {
+		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrNoEmailOnAccount)
+	}
+	if user.IsEmailVerified() {
+		s.logger.InfoContext(ctx, "user email already verified", "user_id", user.ID())
+		return exceptions.MakeApiErrorWithStatus(http.StatusConflict, exceptions.ErrEmailAlreadyVerified)
 	}
 
-	activeSession.Deactivate()
+	latest, err := s.verifications.GetLatestByUserID(ctx, user.ID())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get latest verification token", "user_id", user.ID(), "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if latest != nil && time.Since(latest.CreatedAt()) < verificationResendInterval {
+		s.logger.WarnContext(ctx, "verification email requested too soon", "user_id", user.ID())
+		return exceptions.MakeApiErrorWithStatus(http.StatusTooManyRequests, exceptions.ErrTooManyRequests)
+	}
 
-	sess, err := s.sessionService.UpdateSession(ctx, activeSession)
+	sentLastHour, err := s.verifications.CountCreatedSince(ctx, user.ID(), time.Now().Add(-time.Hour))
 	if err != nil {
-		s.logger.ErrorContext(ctx, "error while attempting to update user session", "user_id", c.UserID, "err", err)
+		s.logger.ErrorContext(ctx, "error while attempting to count verification tokens", "user_id", user.ID(), "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if sentLastHour >= verificationHourlyLimit {
+		s.logger.WarnContext(ctx, "verification email hourly limit reached", "user_id", user.ID())
+		return exceptions.MakeApiErrorWithStatus(http.StatusTooManyRequests, exceptions.ErrTooManyRequests)
+	}
+
+	if err := s.sendVerificationEmail(ctx, user.ID(), user.Email()); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to send verification email", "user_id", user.ID(), "err", err)
 		return exceptions.MakeGenericApiError()
 	}
 
-	s.logger.InfoContext(ctx, "user logged out with success", "user_id", c.UserID, "session_id", sess.ToModel().ID)
+	s.logger.InfoContext(ctx, "verification email sent", "user_id", user.ID())
 	return nil
 }
 
-func (s *userService) GetSigned(ctx context.Context) (*common.User, *exceptions.ApiError[string]) {
-	s.logger.InfoContext(ctx, "attempting to logout user")
+// ChangeRole is an admin operation. The user's sessions are revoked so the next
+// login issues tokens carrying the new role.
+func (s *userService) ChangeRole(ctx context.Context, userID string, role valueobjects.Role) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to change user role", "user_id", userID, "role", role)
 
 	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
 	if !ok {
 		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
-		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+		return exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	if c.UserID == userID {
+		s.logger.WarnContext(ctx, "admin attempted to change own role", "user_id", userID)
+		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrCannotChangeOwnRole)
 	}
 
-	userSession, err := s.sessionService.GetActiveSessionByUserID(ctx, c.UserID)
+	existingUser, err := s.repository.GetModelByID(ctx, userID)
 	if err != nil {
-		s.logger.ErrorContext(ctx, "error whilhe attempting to get user session", "user_id", c.UserID, "err", err)
-		return nil, exceptions.MakeGenericApiError()
+		s.logger.ErrorContext(ctx, "error while attempting to get user by id", "user_id", userID, "err", err)
+		return exceptions.MakeGenericApiError()
 	}
-	if userSession == nil {
-		s.logger.WarnContext(ctx, "session not found", "user_id", c.UserID)
-		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrActiveSessionNotFound)
+	if existingUser == nil || existingUser.DeletedAt != nil {
+		s.logger.WarnContext(ctx, "user not found", "user_id", userID)
+		return exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrUserNotFound)
 	}
 
-	user, err := s.repository.GetByID(ctx, c.UserID)
+	user := NewFromModel(*existingUser)
+	previousRole := user.Role()
+	if previousRole == role {
+		return nil
+	}
+
+	if err := user.ChangeRole(role); err != nil {
+		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, err)
+	}
+
+	if err := s.repository.UpdateRole(ctx, user); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to update user role", "user_id", userID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	if err := s.sessionService.DeactivateAllSessions(ctx, userID); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to deactivate all user sessions", "user_id", userID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	s.audit.Record(ctx, common.AuditEventInput{
+		Type:         auditevents.TypeRoleChange,
+		Outcome:      auditevents.OutcomeSuccess,
+		ActorUserID:  c.UserID,
+		TargetUserID: userID,
+		Metadata:     map[string]any{"from": previousRole, "to": role},
+	})
+
+	s.logger.InfoContext(ctx, "user role changed with success", "user_id", userID, "role", role, "changed_by", c.UserID)
+	return nil
+}
+
+func (s *userService) IsEmailVerified(ctx context.Context, userID string) (bool, error) {
+	return s.repository.IsEmailVerified(ctx, userID)
+}
+
+func (s *userService) Login(ctx context.Context, input common.LoginUserRequest) (*common.LoginUserResponse, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to login user, checking for lockout", "email", input.Email)
+
+	// Lockout failures are only logged, a broken store must not block every login.
+	if err := s.loginGuard.Check(ctx, input.Email, input.IPAddress); err != nil {
+		var locked *lockout.LockedError
+		if errors.As(err, &locked) {
+			s.logger.WarnContext(ctx, "login attempt while locked", "email", input.Email, "ip", input.IPAddress, "retry_after", locked.RetryAfter)
+			s.auditUnknownLogin(ctx, input.Email, map[string]any{"method": loginMethodPassword, "reason": "locked"})
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusTooManyRequests, locked)
+		}
+		s.logger.ErrorContext(ctx, "error while attempting to check login lockout", "err", err)
+	}
+
+	existingUser, err := s.repository.GetByEmail(ctx, input.Email)
 	if err != nil {
-		s.logger.ErrorContext(ctx, "error while attempting to get user by id", "user_id", c.UserID, "err", err)
+		s.logger.ErrorContext(ctx, "error while attempting to query for existing users", "err", err)
 		return nil, exceptions.MakeGenericApiError()
 	}
 
-	if user == nil {
-		s.logger.WarnContext(ctx, "user not found", "user_id", c.UserID)
-		return nil, exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrUserNotFound)
+	// Unknown emails go through the same password check and response as wrong
+	// passwords, so the endpoint cannot be used to find registered addresses.
+	if existingUser == nil {
+		security.SimulatePasswordCheck(input.Password)
+		s.logger.InfoContext(ctx, "user was not found", "email", input.Email)
+		s.auditUnknownLogin(ctx, input.Email, map[string]any{"method": loginMethodPassword, "reason": "unknown_account"})
+		return nil, s.failLogin(ctx, input)
 	}
 
-	return user, nil
+	user := NewFromModel(*existingUser)
+
+	s.logger.InfoContext(ctx, "user found, attempting to verify password", "email", user.Email())
+	if ok := security.PasswordMatches(input.Password, user.PasswordHash()); !ok {
+		s.logger.ErrorContext(ctx, "unauthorized attempt to login", "email", input.Email)
+		s.auditLogin(ctx, user.ID(), auditevents.OutcomeFailure, map[string]any{"method": loginMethodPassword, "reason": "wrong_password"})
+		return nil, s.failLogin(ctx, input)
+	}
+
+	if user.DeletedAt() != nil {
+		s.logger.InfoContext(ctx, "user must be active to login", "email", input.Email)
+		s.auditLogin(ctx, user.ID(), auditevents.OutcomeFailure, map[string]any{"method": loginMethodPassword, "reason": "account_disabled"})
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUserDisabled)
+	}
+
+	s.upgradePasswordHash(ctx, user, input.Password)
+
+	return s.completeLogin(ctx, user, loginMethodPassword, input.Email, input.UserAgent, input.IPAddress)
 }
 
-func (s *userService) CountUsersBySubcategoryIDs(ctx context.Context, subcategoryIDs []string) (map[string]int, error) {
-	s.logger.InfoContext(ctx, "attempting to count user by subcategory IDs")
+// completeLogin issues tokens to a user who passed the first factor, or opens a
+// two-factor challenge when the account has it enabled. loginKey is the lockout key
+// the first factor was checked against, empty when it had none. Login failures are
+// only cleared once tokens are issued, so wrong codes keep counting towards the lockout.
+func (s *userService) completeLogin(ctx context.Context, user *User, method, loginKey, userAgent, ipAddress string) (*common.LoginUserResponse, *exceptions.ApiError[string]) {
+	if loginKey == "" {
+		loginKey = user.LoginIdentifier()
+	}
 
-	count, err := s.repository.CountBySubcategoryIDs(ctx, subcategoryIDs)
+	enabled, err := s.twoFactor.IsEnabled(ctx, user.ID())
 	if err != nil {
-		s.logger.ErrorContext(ctx, "error while attempting to count user by subcategory IDs", "err", err)
-		return nil, err
+		s.logger.ErrorContext(ctx, "error while attempting to check two-factor status", "user_id", user.ID(), "err", err)
+		return nil, exceptions.MakeGenericApiError()
 	}
 
-	return count, nil
+	if enabled {
+		challengeToken, err := s.twoFactor.StartChallenge(ctx, user.ID(), loginKey)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to start two-factor challenge", "user_id", user.ID(), "err", err)
+			return nil, exceptions.MakeGenericApiError()
+		}
+
+		s.logger.InfoContext(ctx, "first factor accepted, waiting for two-factor code", "user_id", user.ID())
+		return &common.LoginUserResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    &challengeToken,
+		}, nil
+	}
+
+	if err := s.loginGuard.Succeed(ctx, loginKey); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to reset login failures", "user_id", user.ID(), "err", err)
+	}
+
+	return s.issueTokens(ctx, user, method, userAgent, ipAddress)
+}
+
+func (s *userService) VerifyTwoFactorLogin(ctx context.Context, input common.TwoFactorLoginRequest) (*common.LoginUserResponse, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to finish login with two-factor code")
+
+	challenge, apiErr := s.twoFactor.VerifyChallenge(ctx, input)
+	if apiErr != nil && challenge == nil {
+		return nil, apiErr
+	}
+	userID := challenge.UserID()
+
+	userModel, err := s.repository.GetModelByID(ctx, userID)
+	if err != nil || userModel == nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get user of two-factor challenge", "user_id", userID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	user := NewFromModel(*userModel)
+
+	// Challenges created before the key was stored fall back to the account identifier.
+	loginKey := challenge.LoginKey()
+	if loginKey == "" {
+		loginKey = user.LoginIdentifier()
+	}
+
+	if apiErr != nil {
+		if err := s.loginGuard.Fail(ctx, loginKey, input.IPAddress); err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to record failed login", "user_id", userID, "err", err)
+		}
+		s.auditLogin(ctx, userID, auditevents.OutcomeFailure, map[string]any{"method": loginMethodTwoFactor, "reason": "wrong_code"})
+		return nil, apiErr
+	}
+
+	if user.DeletedAt() != nil {
+		s.logger.InfoContext(ctx, "user must be active to login", "user_id", userID)
+		s.auditLogin(ctx, userID, auditevents.OutcomeFailure, map[string]any{"method": loginMethodTwoFactor, "reason": "account_disabled"})
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUserDisabled)
+	}
+
+	if err := s.loginGuard.Succeed(ctx, loginKey); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to reset login failures", "user_id", userID, "err", err)
+	}
+
+	return s.issueTokens(ctx, user, loginMethodTwoFactor, input.UserAgent, input.IPAddress)
 }
 
-func (s *userService) GetProfessionals(ctx context.Context) ([]*common.GetProfessionalsResponse, *exceptions.ApiError[string]) {
-	s.logger.InfoContext(ctx, "attemping to get professional users")
+// issueTokens opens a session for an authenticated user and returns its token pair.
+// method names how the user authenticated and is kept in the audit log.
+func (s *userService) issueTokens(ctx context.Context, user *User, method, userAgent, ipAddress string) (*common.LoginUserResponse, *exceptions.ApiError[string]) {
+	refreshToken, claims, err := s.tokenProvider.GenerateRefreshToken(user)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to create user refresh token", "user", user, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
 
-	professionals, err := s.repository.GetProfessionalUsers(ctx)
+	sess, err := s.sessionService.CreateSession(
+		ctx,
+		common.CreateSessionRequest{
+			UserID:    user.ID(),
+			JTI:       claims.ID,
+			UserAgent: userAgent,
+			IPAddress: ipAddress,
+		},
+	)
 	if err != nil {
-		s.logger.ErrorContext(ctx, "error while attempting to get professional users", "err", err)
+		s.logger.ErrorContext(ctx, "error while attempting to create session", "err", err)
 		return nil, exceptions.MakeGenericApiError()
 	}
 
-	if professionals == nil {
-		s.logger.WarnContext(ctx, "any professional user found")
-		return nil, nil
+	accessToken, _, err := s.tokenProvider.GenerateAccessToken(user, sess.ID())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to create user access token", "user", user, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "access token, refresh token and session created", "user_id", user.ID())
+	s.auditLogin(ctx, user.ID(), auditevents.OutcomeSuccess, map[string]any{"method": method, "session_id": sess.ID()})
+
+	return &common.LoginUserResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// upgradePasswordHash replaces a hash made with outdated settings while the plain
+// password is at hand. Failures are only logged, the old hash keeps working.
+func (s *userService) upgradePasswordHash(ctx context.Context, user *User, password string) {
+	if !security.PasswordNeedsRehash(user.PasswordHash()) {
+		return
 	}
 
-	return professionals, nil
+	passwordHash, err := security.HashPassword(password)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to rehash user password", "user_id", user.ID(), "err", err)
+		return
+	}
+
+	user.ChangePassword(passwordHash)
+	if err := s.repository.UpdatePassword(ctx, user); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to store rehashed user password", "user_id", user.ID(), "err", err)
+		return
+	}
+
+	s.logger.InfoContext(ctx, "user password hash upgraded", "user_id", user.ID())
+}
+
+// auditLogin records a login attempt. userID is empty when no account matched.
+func (s *userService) auditLogin(ctx context.Context, userID, outcome string, metadata map[string]any) {
+	s.audit.Record(ctx, common.AuditEventInput{
+		Type:         auditevents.TypeLogin,
+		Outcome:      outcome,
+		ActorUserID:  userID,
+		TargetUserID: userID,
+		Metadata:     metadata,
+	})
+}
+
+// auditUnknownLogin records a failed login that is not tied to an account. The email
+// or phone typed is handed over as the identifier, which the audit log only keeps hashed.
+func (s *userService) auditUnknownLogin(ctx context.Context, identifier string, metadata map[string]any) {
+	s.audit.Record(ctx, common.AuditEventInput{
+		Type:       auditevents.TypeLogin,
+		Outcome:    auditevents.OutcomeFailure,
+		Identifier: identifier,
+		Metadata:   metadata,
+	})
+}
+
+func (s *userService) failLogin(ctx context.Context, input common.LoginUserRequest) *exceptions.ApiError[string] {
+	if err := s.loginGuard.Fail(ctx, input.Email, input.IPAddress); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to record login failure", "email", input.Email, "err", err)
+	}
+	return exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrInvalidLoginAttempt)
+}
+
+// RemoveExpiredOIDCStates drops login flows that were abandoned at the provider.
+func (s *userService) RemoveExpiredOIDCStates(ctx context.Context) {
+	if err := s.oidcStates.DeleteExpired(ctx, time.Now()); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to remove expired oidc login states", "err", err)
+	}
+}
+
+func (s *userService) OIDCAuthorize(ctx context.Context, input common.OIDCAuthorizeRequest) (*common.OIDCAuthorizeResponse, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to start oidc login", "role", input.Role)
+
+	if s.oidcProvider == nil {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrOIDCDisabled)
+	}
+
+	role := input.Role
+	if role == "" {
+		role = valueobjects.Client
+	}
+	if !role.IsSelfAssignable() {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidRole)
+	}
+
+	state, plainState, err := oidcstates.New(role)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to create oidc login state", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	authorizationURL, err := s.oidcProvider.AuthCodeURL(ctx, plainState, state.Nonce(), state.CodeChallenge())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to build oidc authorization url", "err", err)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadGateway, exceptions.ErrOIDCProviderUnavailable)
+	}
+
+	if err := s.oidcStates.Create(ctx, state); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to store oidc login state", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	return &common.OIDCAuthorizeResponse{AuthorizationURL: authorizationURL}, nil
+}
+
+// OIDCLogin finishes the authorization code flow. The provider identity is matched
+// to an existing link first, then to an account whose email was already confirmed, and
+// otherwise a new account is created with the role chosen when the flow started.
+func (s *userService) OIDCLogin(ctx context.Context, input common.OIDCCallbackRequest) (*common.LoginUserResponse, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to finish oidc login")
+
+	if s.oidcProvider == nil {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrOIDCDisabled)
+	}
+
+	state, err := s.oidcStates.Consume(ctx, security.HashToken(input.State))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to consume oidc login state", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if state == nil || state.IsExpired() {
+		s.logger.InfoContext(ctx, "oidc login state is unknown or expired")
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrInvalidOIDCState)
+	}
+
+	identity, err := s.oidcProvider.Exchange(ctx, input.Code, state.CodeVerifier(), state.Nonce())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to exchange oidc authorization code", "err", err)
+		s.auditLogin(ctx, "", auditevents.OutcomeFailure, map[string]any{"method": loginMethodOIDC, "reason": "code_exchange_failed"})
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrOIDCLoginFailed)
+	}
+
+	user, apiErr := s.resolveOIDCUser(ctx, identity, state.Role())
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	if user.DeletedAt() != nil {
+		s.logger.InfoContext(ctx, "user must be active to login", "user_id", user.ID())
+		s.auditLogin(ctx, user.ID(), auditevents.OutcomeFailure, map[string]any{"method": loginMethodOIDC, "reason": "account_disabled"})
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUserDisabled)
+	}
+
+	return s.completeLogin(ctx, user, loginMethodOIDC, "", input.UserAgent, input.IPAddress)
+}
+
+func (s *userService) resolveOIDCUser(ctx context.Context, identity *oidc.Identity, role valueobjects.Role) (*User, *exceptions.ApiError[string]) {
+	linked, err := s.identities.GetByIssuerAndSubject(ctx, identity.Issuer, identity.Subject)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get user identity", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	if linked != nil {
+		userModel, err := s.repository.GetModelByID(ctx, linked.UserID())
+		if err != nil || userModel == nil {
+			s.logger.ErrorContext(ctx, "error while attempting to get user of linked identity", "identity_id", linked.ID(), "err", err)
+			return nil, exceptions.MakeGenericApiError()
+		}
+		if err := s.identities.TouchLastLogin(ctx, linked.ID()); err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to update identity last login", "identity_id", linked.ID(), "err", err)
+		}
+		return NewFromModel(*userModel), nil
+	}
+
+	// Without a verified email the provider account could belong to anyone typing
+	// that address, so it is neither linked nor used to create an account.
+	if identity.Email == "" || !identity.EmailVerified {
+		s.logger.InfoContext(ctx, "oidc identity has no verified email", "issuer", identity.Issuer)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusForbidden, exceptions.ErrOIDCEmailNotVerified)
+	}
+
+	existingUser, err := s.repository.GetByEmail(ctx, identity.Email)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to query for existing users", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	// An account whose email was never confirmed may have been registered by someone
+	// else ahead of the real owner. Linking it would hand the owner an account the other
+	// person still holds the password, phone and sessions of, so it is refused.
+	if existingUser != nil && existingUser.EmailVerifiedAt == nil {
+		s.logger.InfoContext(ctx, "oidc email matches an unverified account, refusing to link", "user_id", existingUser.ID)
+		s.auditLogin(ctx, existingUser.ID, auditevents.OutcomeFailure, map[string]any{"method": loginMethodOIDC, "reason": "account_not_verified"})
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusConflict, exceptions.ErrOIDCAccountNotVerified)
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while starting transaction", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	defer tx.Rollback()
+
+	var user *User
+	if existingUser != nil {
+		user = NewFromModel(*existingUser)
+	} else {
+		user, err = s.createOIDCUserTx(ctx, tx, identity, role)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to create oidc user", "err", err)
+			return nil, exceptions.MakeGenericApiError()
+		}
+	}
+
+	link, err := identities.New(user.ID(), identity.Issuer, identity.Subject, identity.Email)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to process user identity entity", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	if err := s.identities.CreateTx(ctx, tx, link); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to link user identity", "user_id", user.ID(), "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.ErrorContext(ctx, "error while commiting oidc login transaction", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "oidc identity linked", "user_id", user.ID(), "created", existingUser == nil)
+	return user, nil
+}
+
+// createOIDCUserTx registers an account whose email was verified by the provider.
+// It gets a random password nobody knows, a real one can be set through the reset flow.
+func (s *userService) createOIDCUserTx(ctx context.Context, tx *sqlx.Tx, identity *oidc.Identity, role valueobjects.Role) (*User, error) {
+	randomPassword, err := security.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := security.HashPassword(randomPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := New(identity.Email, passwordHash, role)
+	if err != nil {
+		return nil, err
+	}
+	user.VerifyEmail()
+
+	if err := s.repository.Register(ctx, tx, user); err != nil {
+		return nil, err
+	}
+
+	fullName := identity.Name
+	if fullName == "" {
+		fullName, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	userProfile, err := userprofiles.New(user.ID(), fullName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userProfilesRepo.CreateInitialProfileTx(ctx, tx, userProfile); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// RequestPhoneCode texts a one-time code to the number, used to register, log in or
+// verify a phone. It answers the same way whether or not the number has an account.
+func (s *userService) RequestPhoneCode(ctx context.Context, input common.PhoneCodeRequest) *exceptions.ApiError[string] {
+	phone, ok := valueobjects.SanitizePhoneNumber(input.Phone)
+	if !ok {
+		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidPhone)
+	}
+
+	s.logger.InfoContext(ctx, "attempting to send phone code")
+
+	latest, err := s.phoneOTPs.GetLatestByPhone(ctx, phone)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get latest phone code", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if latest != nil && time.Since(latest.CreatedAt()) < phoneCodeResendInterval {
+		s.logger.WarnContext(ctx, "phone code requested too soon")
+		return exceptions.MakeApiErrorWithStatus(http.StatusTooManyRequests, exceptions.ErrTooManyRequests)
+	}
+
+	sentLastHour, err := s.phoneOTPs.CountCreatedSince(ctx, phone, time.Now().Add(-time.Hour))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to count phone codes", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if sentLastHour >= phoneCodeHourlyLimit {
+		s.logger.WarnContext(ctx, "phone code hourly limit reached")
+		return exceptions.MakeApiErrorWithStatus(http.StatusTooManyRequests, exceptions.ErrTooManyRequests)
+	}
+
+	otp, plainCode, err := phoneotps.New(phone)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to process phone code entity", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	if err := s.phoneOTPs.Create(ctx, otp); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to create phone code", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	text := fmt.Sprintf("Conecta Maré: seu código é %s. Ele vale por 10 minutos. Não compartilhe com ninguém.", plainCode)
+	if err := s.smsSender.Send(ctx, phone, text); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to send phone code", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "phone code sent")
+	return nil
+}
+
+func (s *userService) PhoneRegister(ctx context.Context, input common.PhoneRegisterRequest) (*common.LoginUserResponse, *exceptions.ApiError[string]) {
+	phone, ok := valueobjects.SanitizePhoneNumber(input.Phone)
+	if !ok {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidPhone)
+	}
+
+	s.logger.InfoContext(ctx, "attempting to register user by phone")
+
+	if !input.Role.IsSelfAssignable() {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidRole)
+	}
+
+	// The code is checked before the number is looked up, so only its owner can learn
+	// whether it already has an account.
+	if apiErr := s.verifyPhoneCode(ctx, phone, input.Code); apiErr != nil {
+		return nil, apiErr
+	}
+
+	existingUser, err := s.repository.GetByPhone(ctx, phone)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to query for existing users", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if existingUser != nil {
+		s.logger.InfoContext(ctx, "phone is taken")
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusConflict, exceptions.ErrPhoneTaken)
+	}
+
+	// Accounts created by phone get a random password nobody knows, until an email
+	// is added there is no password login for them.
+	randomPassword, err := security.GenerateToken()
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to generate password", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	passwordHash, err := security.HashPassword(randomPassword)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to hash user password", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	user, err := NewWithPhone(phone, passwordHash, input.Role)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to process user entity", "err", err)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnprocessableEntity, err)
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while starting transaction", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	defer tx.Rollback()
+
+	if err := s.repository.Register(ctx, tx, user); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting create user", "err", err)
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusConflict, exceptions.ErrPhoneTaken)
+		}
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	userProfile, err := userprofiles.New(user.ID(), input.FullName)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to process user profile entity", "err", err)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnprocessableEntity, err)
+	}
+
+	if err := s.userProfilesRepo.CreateInitialProfileTx(ctx, tx, userProfile); err != nil {
+		s.logger.ErrorContext(ctx, "error while creating initial user profile", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.ErrorContext(ctx, "error while commiting user registration transaction", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "user and initial profile successfully created by phone", "user_id", user.ID())
+
+	return s.issueTokens(ctx, user, loginMethodPhone, input.UserAgent, input.IPAddress)
+}
+
+func (s *userService) PhoneLogin(ctx context.Context, input common.PhoneLoginRequest) (*common.LoginUserResponse, *exceptions.ApiError[string]) {
+	phone, ok := valueobjects.SanitizePhoneNumber(input.Phone)
+	if !ok {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidPhone)
+	}
+
+	s.logger.InfoContext(ctx, "attempting to login user by phone, checking for lockout")
+
+	if err := s.loginGuard.Check(ctx, phone, input.IPAddress); err != nil {
+		var locked *lockout.LockedError
+		if errors.As(err, &locked) {
+			s.logger.WarnContext(ctx, "phone login attempt while locked", "ip", input.IPAddress, "retry_after", locked.RetryAfter)
+			s.auditUnknownLogin(ctx, phone, map[string]any{"method": loginMethodPhone, "reason": "locked"})
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusTooManyRequests, locked)
+		}
+		s.logger.ErrorContext(ctx, "error while attempting to check login lockout", "err", err)
+	}
+
+	if apiErr := s.verifyPhoneCode(ctx, phone, input.Code); apiErr != nil {
+		if apiErr.Code == http.StatusUnauthorized {
+			if err := s.loginGuard.Fail(ctx, phone, input.IPAddress); err != nil {
+				s.logger.ErrorContext(ctx, "error while attempting to record failed login", "err", err)
+			}
+			s.auditUnknownLogin(ctx, phone, map[string]any{"method": loginMethodPhone, "reason": "wrong_code"})
+		}
+		return nil, apiErr
+	}
+
+	existingUser, err := s.repository.GetByPhone(ctx, phone)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to query for existing users", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	// Numbers without an account get the same answer as a wrong code.
+	if existingUser == nil || existingUser.PhoneVerifiedAt == nil {
+		s.logger.InfoContext(ctx, "no user with this verified phone")
+		s.auditUnknownLogin(ctx, phone, map[string]any{"method": loginMethodPhone, "reason": "unknown_account"})
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrInvalidPhoneCode)
+	}
+
+	user := NewFromModel(*existingUser)
+
+	if user.DeletedAt() != nil {
+		s.logger.InfoContext(ctx, "user must be active to login", "user_id", user.ID())
+		s.auditLogin(ctx, user.ID(), auditevents.OutcomeFailure, map[string]any{"method": loginMethodPhone, "reason": "account_disabled"})
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUserDisabled)
+	}
+
+	return s.completeLogin(ctx, user, loginMethodPhone, phone, input.UserAgent, input.IPAddress)
+}
+
+// VerifyPhone adds a verified login phone to the signed user's account.
+func (s *userService) VerifyPhone(ctx context.Context, input common.PhoneVerifyRequest) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to verify user phone")
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	phone, ok := valueobjects.SanitizePhoneNumber(input.Phone)
+	if !ok {
+		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidPhone)
+	}
+
+	// As on registration, whether the number is taken is only told to its owner.
+	if apiErr := s.verifyPhoneCode(ctx, phone, input.Code); apiErr != nil {
+		return apiErr
+	}
+
+	owner, err := s.repository.GetByPhone(ctx, phone)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to query for existing users", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if owner != nil && owner.ID != c.UserID {
+		s.logger.InfoContext(ctx, "phone is taken", "user_id", c.UserID)
+		return exceptions.MakeApiErrorWithStatus(http.StatusConflict, exceptions.ErrPhoneTaken)
+	}
+
+	existingUser, err := s.repository.GetModelByID(ctx, c.UserID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get user by id", "user_id", c.UserID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if existingUser == nil {
+		return exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrUserNotFound)
+	}
+
+	user := NewFromModel(*existingUser)
+	user.VerifyPhone(phone)
+
+	if err := s.repository.UpdatePhone(ctx, user); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to update user phone", "user_id", user.ID(), "err", err)
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return exceptions.MakeApiErrorWithStatus(http.StatusConflict, exceptions.ErrPhoneTaken)
+		}
+		return exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "user phone verified", "user_id", user.ID())
+	return nil
+}
+
+// verifyPhoneCode checks the code against the latest one sent to the phone and
+// consumes it on success.
+func (s *userService) verifyPhoneCode(ctx context.Context, phone, code string) *exceptions.ApiError[string] {
+	otp, err := s.phoneOTPs.GetLatestByPhone(ctx, phone)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get latest phone code", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if otp == nil || !otp.IsUsable() {
+		s.logger.InfoContext(ctx, "no usable phone code")
+		return exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrInvalidPhoneCode)
+	}
+
+	reserved, err := s.phoneOTPs.ReserveAttempt(ctx, otp.ID())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to count phone code attempt", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if !reserved || !otp.Matches(code) {
+		s.logger.InfoContext(ctx, "wrong phone code")
+		return exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrInvalidPhoneCode)
+	}
+
+	used, err := s.phoneOTPs.MarkUsed(ctx, otp.ID())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to consume phone code", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if !used {
+		return exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrInvalidPhoneCode)
+	}
+
+	return nil
+}
+
+func (s *userService) Logout(ctx context.Context) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to logout user")
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	s.logger.InfoContext(ctx, "destructured data from context, logging out user", "user_id", c.UserID)
+
+	currentSession, apiErr := s.getCurrentSession(ctx, c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	if currentSession == nil || !currentSession.IsActive() {
+		s.logger.WarnContext(ctx, "active session not found for user", "user_id", c.UserID)
+		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrActiveSessionNotFound)
+	}
+
+	currentSession.Deactivate()
+
+	sess, err := s.sessionService.UpdateSession(ctx, currentSession)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to update user session", "user_id", c.UserID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	s.audit.Record(ctx, common.AuditEventInput{
+		Type:         auditevents.TypeLogout,
+		Outcome:      auditevents.OutcomeSuccess,
+		ActorUserID:  c.UserID,
+		TargetUserID: c.UserID,
+		Metadata:     map[string]any{"session_id": sess.ID()},
+	})
+
+	s.logger.InfoContext(ctx, "user logged out with success", "user_id", c.UserID, "session_id", sess.ID())
+	return nil
+}
+
+func (s *userService) GetSessions(ctx context.Context, refreshToken string, includeInactive bool) ([]common.Session, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to list user sessions")
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	sessions, err := s.sessionService.GetSessionsByUserID(ctx, c.UserID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get user sessions", "user_id", c.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	var currentJTI string
+	if refreshClaims, err := s.tokenProvider.VerifyRefreshToken(refreshToken); err == nil {
+		currentJTI = refreshClaims.ID
+	}
+
+	response := make([]common.Session, 0, len(sessions))
+	for _, sess := range sessions {
+		active := sess.IsActive() && !sess.IsExpired()
+		if !active && !includeInactive {
+			continue
+		}
+		response = append(response, common.Session{
+			ID:         sess.ID(),
+			UserAgent:  sess.UserAgent(),
+			IPAddress:  sess.IPAddress(),
+			Active:     active,
+			Current:    currentJTI != "" && sess.JTI() == currentJTI,
+			CreatedAt:  sess.CreatedAt(),
+			LastSeenAt: sess.LastSeenAt(),
+			ExpiresAt:  sess.ExpiresAt(),
+		})
+	}
+
+	return response, nil
+}
+
+func (s *userService) RevokeSession(ctx context.Context, sessionID string) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to revoke user session", "session_id", sessionID)
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	sess, err := s.sessionService.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get session", "session_id", sessionID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	// Sessions owned by someone else are reported as missing so their IDs cannot be probed.
+	if sess == nil || sess.UserID() != c.UserID {
+		s.logger.WarnContext(ctx, "session not found for user", "user_id", c.UserID, "session_id", sessionID)
+		return exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrSessionNotFound)
+	}
+
+	if !sess.IsActive() {
+		return nil
+	}
+
+	sess.Deactivate()
+
+	if _, err := s.sessionService.UpdateSession(ctx, sess); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to revoke session", "session_id", sessionID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "session revoked with success", "user_id", c.UserID, "session_id", sessionID)
+	return nil
+}
+
+func (s *userService) RevokeAllSessions(ctx context.Context) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to revoke all user sessions")
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	if err := s.sessionService.DeactivateAllSessions(ctx, c.UserID); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to deactivate all user sessions", "user_id", c.UserID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	s.logger.InfoContext(ctx, "all user sessions revoked with success", "user_id", c.UserID)
+	return nil
+}
+
+// getCurrentSession finds the session the request was made from, through the session
+// id the access token carries. WithAuth rejects tokens without one.
+func (s *userService) getCurrentSession(ctx context.Context, c *jwt.Claims) (*session.Session, *exceptions.ApiError[string]) {
+	sess, err := s.sessionService.GetSessionByID(ctx, c.SessionID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get session by id", "user_id", c.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if sess != nil && sess.UserID() != c.UserID {
+		s.logger.WarnContext(ctx, "access token session belongs to another user", "user_id", c.UserID, "session_id", c.SessionID)
+		return nil, nil
+	}
+
+	return sess, nil
+}
+
+func (s *userService) Refresh(ctx context.Context, input common.RefreshTokenRequest) (*common.LoginUserResponse, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to refresh user tokens")
+
+	claims, err := s.tokenProvider.VerifyRefreshToken(input.RefreshToken)
+	if err != nil {
+		s.logger.WarnContext(ctx, "invalid refresh token", "err", err)
+		if strings.Contains(err.Error(), "token has expired") {
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrTokenExpired)
+		}
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrInvalidTokenHeader)
+	}
+
+	sess, err := s.sessionService.GetSessionByJTI(ctx, claims.ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get session by jti", "user_id", claims.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	// A refresh token signed by us whose JTI no longer belongs to any session
+	// was already rotated, so someone is replaying it. Revoke everything.
+	if sess == nil {
+		s.logger.WarnContext(ctx, "refresh token reuse detected, revoking all user sessions", "user_id", claims.UserID)
+		if err := s.sessionService.DeactivateAllSessions(ctx, claims.UserID); err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to deactivate all user sessions", "user_id", claims.UserID, "err", err)
+			return nil, exceptions.MakeGenericApiError()
+		}
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrRefreshTokenReused)
+	}
+
+	if !sess.IsActive() || sess.IsExpired() || sess.UserID() != claims.UserID {
+		s.logger.WarnContext(ctx, "session is not active", "user_id", claims.UserID, "session_id", sess.ID())
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrActiveSessionNotFound)
+	}
+
+	existingUser, err := s.repository.GetModelByID(ctx, claims.UserID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get user by id", "user_id", claims.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	if existingUser == nil {
+		s.logger.WarnContext(ctx, "user not found", "user_id", claims.UserID)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUserNotFound)
+	}
+	if existingUser.DeletedAt != nil {
+		s.logger.InfoContext(ctx, "user must be active to refresh tokens", "user_id", claims.UserID)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUserDisabled)
+	}
+
+	user := NewFromModel(*existingUser)
+
+	accessToken, _, err := s.tokenProvider.GenerateAccessToken(user, sess.ID())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to create user access token", "user_id", user.ID(), "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	newRefreshToken, newClaims, err := s.tokenProvider.GenerateRefreshToken(user)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to create user refresh token", "user_id", user.ID(), "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	sess.Rotate(newClaims.ID)
+	sess.Touch(input.UserAgent, input.IPAddress)
+
+	rotated, err := s.sessionService.RotateSession(ctx, sess, claims.ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to rotate session", "user_id", user.ID(), "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+	// Another refresh with the same token rotated the session first. That is a race
+	// between two tabs, not a replay, so only this request fails.
+	if !rotated {
+		s.logger.WarnContext(ctx, "session was rotated by a concurrent refresh", "user_id", user.ID(), "session_id", sess.ID())
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrActiveSessionNotFound)
+	}
+
+	s.logger.InfoContext(ctx, "tokens refreshed and session rotated", "user_id", user.ID(), "session_id", sess.ID())
+
+	return &common.LoginUserResponse{
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
+	}, nil
+}
+
+// ForgotPassword emails a single-use reset link when the address belongs to an active user.
+// It never reveals whether the email is registered.
+func (s *userService) ForgotPassword(ctx context.Context, input common.ForgotPasswordRequest) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to start password reset", "email", input.Email)
+
+	existingUser, err := s.repository.GetByEmail(ctx, input.Email)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to query for existing users", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if existingUser == nil || existingUser.DeletedAt != nil {
+		s.logger.InfoContext(ctx, "password reset requested for unknown or disabled user", "email", input.Email)
+		return nil
+	}
+
+	if err := s.passwordResets.InvalidateAllByUserID(ctx, existingUser.ID); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to invalidate previous reset tokens", "user_id", existingUser.ID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	resetToken, plainToken, err := passwordresets.New(existingUser.ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to process reset token entity", "user_id", existingUser.ID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	if err := s.passwordResets.Create(ctx, resetToken); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to create reset token", "user_id", existingUser.ID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	resetURL := fmt.Sprintf("%s/reset-password?token=%s", strings.TrimRight(s.appURL, "/"), url.QueryEscape(plainToken))
+	message := mailer.Message{
+		To:      *existingUser.Email,
+		Subject: "Redefinição de senha - Conecta Maré",
+		Text: fmt.Sprintf(
+			"Recebemos um pedido para redefinir sua senha.\n\nAcesse o link abaixo em até 1 hora:\n%s\n\nSe você não fez esse pedido, ignore este email.",
+			resetURL,
+		),
+		HTML: fmt.Sprintf(
+			`<p>Recebemos um pedido para redefinir sua senha.</p><p><a href="%s">Clique aqui para criar uma nova senha</a>. O link expira em 1 hora.</p><p>Se você não fez esse pedido, ignore este email.</p>`,
+			resetURL,
+		),
+	}
+
+	// Delivery failures are only logged so the response stays the same for every address.
+	if err := s.mailer.Send(ctx, message); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to send password reset email", "user_id", existingUser.ID, "err", err)
+		return nil
+	}
+
+	s.logger.InfoContext(ctx, "password reset email sent", "user_id", existingUser.ID)
+	return nil
+}
+
+func (s *userService) ResetPassword(ctx context.Context, input common.ResetPasswordRequest) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to reset user password")
+
+	resetToken, err := s.passwordResets.GetByTokenHash(ctx, security.HashToken(input.Token))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get reset token", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if resetToken == nil || !resetToken.IsUsable() {
+		s.logger.WarnContext(ctx, "invalid or expired reset token")
+		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidResetToken)
+	}
+
+	existingUser, err := s.repository.GetModelByID(ctx, resetToken.UserID())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get user by id", "user_id", resetToken.UserID(), "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if existingUser == nil || existingUser.DeletedAt != nil {
+		s.logger.WarnContext(ctx, "reset token belongs to unknown or disabled user", "user_id", resetToken.UserID())
+		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidResetToken)
+	}
+
+	passwordHash, err := valueobjects.NewPassword(input.Password)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to hash user password", "user_id", existingUser.ID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	user := NewFromModel(*existingUser)
+	user.ChangePassword(passwordHash.Hash)
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while starting transaction", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	defer tx.Rollback()
+
+	consumed, err := s.passwordResets.MarkUsedTx(ctx, tx, resetToken.ID())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to consume reset token", "user_id", user.ID(), "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if !consumed {
+		s.logger.WarnContext(ctx, "reset token was already used", "user_id", user.ID())
+		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidResetToken)
+	}
+
+	if err := s.repository.UpdatePasswordTx(ctx, tx, user); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to update user password", "user_id", user.ID(), "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.ErrorContext(ctx, "error while commiting password reset transaction", "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	if err := s.sessionService.DeactivateAllSessions(ctx, user.ID()); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to deactivate all user sessions", "user_id", user.ID(), "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	s.audit.Record(ctx, common.AuditEventInput{
+		Type:         auditevents.TypePasswordReset,
+		Outcome:      auditevents.OutcomeSuccess,
+		ActorUserID:  user.ID(),
+		TargetUserID: user.ID(),
+	})
+
+	s.logger.InfoContext(ctx, "user password reset with success", "user_id", user.ID())
+	return nil
+}
+
+// ChangePassword requires the current password and keeps only the session that made the change.
+func (s *userService) ChangePassword(ctx context.Context, input common.ChangePasswordRequest) *exceptions.ApiError[string] {
+	s.logger.InfoContext(ctx, "attempting to change user password")
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	existingUser, err := s.repository.GetModelByID(ctx, c.UserID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get user by id", "user_id", c.UserID, "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+	if existingUser == nil || existingUser.DeletedAt != nil {
+		s.logger.WarnContext(ctx, "user not found", "user_id", c.UserID)
+		return exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrUserNotFound)
+	}
+
+	user := NewFromModel(*existingUser)
+
+	// Guessing the current password with a stolen access token counts as failed logins.
+	if err := s.loginGuard.Check(ctx, user.LoginIdentifier(), ""); err != nil {
+		var locked *lockout.LockedError
+		if errors.As(err, &locked) {
+			s.logger.WarnContext(ctx, "password change attempt while locked", "user_id", user.ID())
+			return exceptions.MakeApiErrorWithStatus(http.StatusTooManyRequests, locked)
+		}
+		s.logger.ErrorContext(ctx, "error while attempting to check login lockout", "err", err)
+	}
+
+	if !security.PasswordMatches(input.CurrentPassword, user.PasswordHash()) {
+		s.logger.WarnContext(ctx, "wrong current password on password change", "user_id", user.ID())
+		if err := s.loginGuard.Fail(ctx, user.LoginIdentifier(), ""); err != nil {
+			s.logger.ErrorContext(ctx, "error while attempting to record login failure", "user_id", user.ID(), "err", err)
+		}
+		s.audit.Record(ctx, common.AuditEventInput{
+			Type:         auditevents.TypePasswordChange,
+			Outcome:      auditevents.OutcomeFailure,
+			ActorUserID:  user.ID(),
+			TargetUserID: user.ID(),
+			Metadata:     map[string]any{"reason": "wrong_current_password"},
+		})
+		return exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrWrongCurrentPassword)
+	}
+
+	passwordHash, err := valueobjects.NewPassword(input.Password)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to hash user password", "user_id", user.ID(), "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	user.ChangePassword(passwordHash.Hash)
+
+	if err := s.repository.UpdatePassword(ctx, user); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to update user password", "user_id", user.ID(), "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	if err := s.sessionService.DeactivateOtherSessions(ctx, user.ID(), c.SessionID); err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to deactivate other user sessions", "user_id", user.ID(), "err", err)
+		return exceptions.MakeGenericApiError()
+	}
+
+	s.audit.Record(ctx, common.AuditEventInput{
+		Type:         auditevents.TypePasswordChange,
+		Outcome:      auditevents.OutcomeSuccess,
+		ActorUserID:  user.ID(),
+		TargetUserID: user.ID(),
+	})
+
+	s.logger.InfoContext(ctx, "user password changed with success", "user_id", user.ID())
+	return nil
+}
+
+func (s *userService) GetSigned(ctx context.Context) (*common.User, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attempting to logout user")
+
+	c, ok := ctx.Value(middlewares.AuthKey{}).(*jwt.Claims)
+	if !ok {
+		s.logger.ErrorContext(ctx, "error while attempting to get auth values from context")
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+	}
+
+	user, err := s.repository.GetByID(ctx, c.UserID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get user by id", "user_id", c.UserID, "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	if user == nil {
+		s.logger.WarnContext(ctx, "user not found", "user_id", c.UserID)
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusNotFound, exceptions.ErrUserNotFound)
+	}
+
+	return user, nil
+}
+
+func (s *userService) CountUsersBySubcategoryIDs(ctx context.Context, subcategoryIDs []string) (map[string]int, error) {
+	s.logger.InfoContext(ctx, "attempting to count user by subcategory IDs")
+
+	count, err := s.repository.CountBySubcategoryIDs(ctx, subcategoryIDs)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to count user by subcategory IDs", "err", err)
+		return nil, err
+	}
+
+	return count, nil
+}
+
+// GetProfessionals lists a page of professionals. Pages are chained through next_cursor,
+// which carries the sort it was issued for.
+func (s *userService) GetProfessionals(ctx context.Context, filter common.ProfessionalsFilter) (*common.ProfessionalsPage, *exceptions.ApiError[string]) {
+	s.logger.InfoContext(ctx, "attemping to get professional users", "sort", filter.Sort)
+
+	listFilter := ProfessionalsListFilter{
+		OnlyVerified:  s.hideUnverified,
+		CategoryID:    filter.CategoryID,
+		SubcategoryID: filter.SubcategoryID,
+		CommunityID:   filter.CommunityID,
+		MinPrice:      filter.MinPrice,
+		MaxPrice:      filter.MaxPrice,
+		MinRating:     filter.MinRating,
+		Sort:          filter.Sort,
+		Limit:         filter.Limit,
+	}
+	if listFilter.Sort == "" {
+		listFilter.Sort = ProfessionalsSortNewest
+	}
+	if listFilter.Sort != ProfessionalsSortRelevance && listFilter.Sort != ProfessionalsSortNewest &&
+		listFilter.Sort != ProfessionalsSortRating && listFilter.Sort != ProfessionalsSortPrice &&
+		listFilter.Sort != ProfessionalsSortName {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidSort)
+	}
+	if listFilter.Limit <= 0 || listFilter.Limit > maxProfessionalsPageSize {
+		listFilter.Limit = defaultProfessionalsPageSize
+	}
+	if listFilter.MinPrice != nil && listFilter.MaxPrice != nil && *listFilter.MinPrice > *listFilter.MaxPrice {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidPriceRange)
+	}
+	if listFilter.MinRating != nil && (*listFilter.MinRating < 1 || *listFilter.MinRating > 5) {
+		return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidRatingFilter)
+	}
+
+	if filter.Cursor != "" {
+		sort, key, ID, ok := decodeProfessionalsCursor(filter.Cursor)
+		if !ok || sort != listFilter.Sort {
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidCursor)
+		}
+		listFilter.AfterKey = key
+		listFilter.AfterID = ID
+	}
+
+	if listFilter.Sort == ProfessionalsSortRelevance {
+		return s.getRankedProfessionals(ctx, listFilter, filter.Explain)
+	}
+
+	// One extra row tells whether another page exists.
+	requested := listFilter.Limit
+	listFilter.Limit++
+
+	professionals, total, err := s.repository.ListProfessionals(ctx, listFilter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get professional users", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	page := common.ProfessionalsPage{TotalCount: total}
+	if len(professionals) > requested {
+		professionals = professionals[:requested]
+		last := professionals[len(professionals)-1]
+		cursor := encodeProfessionalsCursor(listFilter.Sort, last.SortKey, last.UserID)
+		page.NextCursor = &cursor
+	}
+	// An empty listing is sent as an empty array, never as null.
+	if professionals == nil {
+		professionals = make([]*common.GetProfessionalsResponse, 0)
+	}
+	page.Professionals = professionals
+
+	return &page, nil
+}
+
+// getRankedProfessionals scores up to maxRankedCandidates matching professionals and
+// returns the requested page, so a relevance listing ends after that many. The cursor keeps the score of the last professional, and the clock is read to
+// the hour, so scores stay put while the client pages through.
+func (s *userService) getRankedProfessionals(ctx context.Context, filter ProfessionalsListFilter, explain bool) (*common.ProfessionalsPage, *exceptions.ApiError[string]) {
+	var afterScore float64
+	if filter.AfterID != "" {
+		score, err := strconv.ParseFloat(filter.AfterKey, 64)
+		if err != nil {
+			return nil, exceptions.MakeApiErrorWithStatus(http.StatusBadRequest, exceptions.ErrInvalidCursor)
+		}
+		afterScore = score
+	}
+
+	rows, meanRating, err := s.repository.ListProfessionalsForRanking(ctx, filter, maxRankedCandidates)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "error while attempting to get professionals for ranking", "err", err)
+		return nil, exceptions.MakeGenericApiError()
+	}
+
+	candidates := make([]ranking.Candidate, 0, len(rows))
+	byID := make(map[string]*ProfessionalRankingRow, len(rows))
+	for _, row := range rows {
+		candidate := ranking.Candidate{
+			ID:           row.UserID,
+			Rating:       row.Rating,
+			ReviewCount:  row.ReviewCount,
+			Completeness: row.Completeness,
+			LastActiveAt: row.LastActiveAt,
+		}
+		if row.CommunityFit != nil {
+			fit := ranking.CommunityFit(*row.CommunityFit)
+			candidate.CommunityFit = &fit
+		}
+		// Nothing records how professionals answer clients yet, so ResponseRate stays
+		// nil and the signal sits out.
+		candidates = append(candidates, candidate)
+		byID[row.UserID] = row
+	}
+
+	ranked := s.ranker.Rank(candidates, ranking.Context{
+		Now:        time.Now().Truncate(time.Hour),
+		MeanRating: meanRating,
+	})
+
+	start := 0
+	if filter.AfterID != "" {
+		start = sort.Search(len(ranked), func(i int) bool {
+			return ranking.Before(afterScore, filter.AfterID, ranked[i].Explanation.Score, ranked[i].Candidate.ID)
+		})
+	}
+	end := min(start+filter.Limit, len(ranked))
+
+	page := common.ProfessionalsPage{
+		Professionals: make([]*common.GetProfessionalsResponse, 0, end-start),
+		TotalCount:    len(ranked),
+	}
+	for _, item := range ranked[start:end] {
+		professional := &byID[item.Candidate.ID].GetProfessionalsResponse
+		if explain {
+			explanation := item.Explanation
+			professional.Ranking = &explanation
+		}
+		page.Professionals = append(page.Professionals, professional)
+	}
+
+	if end < len(ranked) {
+		last := ranked[end-1]
+		key := strconv.FormatFloat(last.Explanation.Score, 'f', -1, 64)
+		cursor := encodeProfessionalsCursor(filter.Sort, key, last.Candidate.ID)
+		page.NextCursor = &cursor
+	}
+
+	return &page, nil
+}
+
+func encodeProfessionalsCursor(sort, key, ID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(sort + "|" + ID + "|" + key))
+}
+
+// decodeProfessionalsCursor splits a cursor into sort, key and user id. The key goes
+// last because names may contain the separator. Cursors come from the client, so the
+// key must parse as the type of its sort before it reaches a query.
+func decodeProfessionalsCursor(cursor string) (string, string, string, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", "", false
+	}
+
+	parts := strings.SplitN(string(raw), "|", 3)
+	if len(parts) != 3 || parts[1] == "" || !isValidProfessionalsCursorKey(parts[0], parts[2]) {
+		return "", "", "", false
+	}
+
+	return parts[0], parts[2], parts[1], true
+}
+
+// professionalsCursorTimeLayout is how Postgres prints a timestamp cast to text.
+const professionalsCursorTimeLayout = "2006-01-02 15:04:05.999999"
+
+func isValidProfessionalsCursorKey(sort, key string) bool {
+	switch sort {
+	case ProfessionalsSortNewest:
+		_, err := time.Parse(professionalsCursorTimeLayout, key)
+		return err == nil
+	case ProfessionalsSortRating, ProfessionalsSortRelevance:
+		value, err := strconv.ParseFloat(key, 64)
+		return err == nil && !math.IsNaN(value) && !math.IsInf(value, 0)
+	case ProfessionalsSortPrice:
+		_, err := strconv.ParseInt(key, 10, 32)
+		return err == nil
+	case ProfessionalsSortName:
+		return true
+	default:
+		return false
+	}
 }
 
 func (s *userService) GetProfessionalByID(ctx context.Context, ID string) (*common.GetProfessionalByIDResponse, *exceptions.ApiError[string]) {
 	s.logger.InfoContext(ctx, "attemping to get professional user by ID", "id", ID)
 
-	professional, err := s.repository.GetProfessionalByID(ctx, ID)
+	professional, err := s.repository.GetProfessionalByID(ctx, ID, s.hideUnverified)
 	if err != nil {
 		s.logger.ErrorContext(ctx, "error while attempting to get professional user", "err", err)
 		return nil, exceptions.MakeGenericApiError()
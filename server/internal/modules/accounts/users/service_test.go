@@ -0,0 +1,438 @@
+package users
+
+import (
+	"conecta-mare-server/internal/common"
+	"conecta-mare-server/internal/databases/postgres/models"
+	"conecta-mare-server/internal/modules/accounts/auditevents"
+	"conecta-mare-server/internal/modules/accounts/identities"
+	"conecta-mare-server/internal/modules/accounts/oidcstates"
+	"conecta-mare-server/internal/modules/accounts/twofactor"
+	"conecta-mare-server/internal/modules/accounts/userprofiles"
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/oidc"
+	"conecta-mare-server/pkg/oidc/oidctest"
+	"conecta-mare-server/pkg/valueobjects"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// The service opens transactions itself, the fakes below ignore them, so a driver
+// that only knows how to begin and end one is enough.
+func init() {
+	sql.Register("users-test", txOnlyDriver{})
+}
+
+type (
+	txOnlyDriver struct{}
+	txOnlyConn   struct{}
+	txOnlyTx     struct{}
+)
+
+func (txOnlyDriver) Open(string) (driver.Conn, error) { return txOnlyConn{}, nil }
+
+func (txOnlyConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("statements are not supported")
+}
+func (txOnlyConn) Close() error              { return nil }
+func (txOnlyConn) Begin() (driver.Tx, error) { return txOnlyTx{}, nil }
+
+func (txOnlyTx) Commit() error   { return nil }
+func (txOnlyTx) Rollback() error { return nil }
+
+type fakeUsersRepository struct {
+	UsersRepository
+	users map[string]*models.User
+}
+
+func (r *fakeUsersRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.Email != nil && *user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUsersRepository) GetModelByID(ctx context.Context, ID string) (*models.User, error) {
+	return r.users[ID], nil
+}
+
+func (r *fakeUsersRepository) ListProfessionals(ctx context.Context, filter ProfessionalsListFilter) ([]*common.GetProfessionalsResponse, int, error) {
+	return nil, 0, nil
+}
+
+func (r *fakeUsersRepository) Register(ctx context.Context, tx *sqlx.Tx, user *User) error {
+	model := user.ToModel()
+	r.users[model.ID] = &model
+	return nil
+}
+
+type fakeUserProfilesRepository struct {
+	userprofiles.UserProfilesRepository
+}
+
+func (fakeUserProfilesRepository) CreateInitialProfileTx(ctx context.Context, tx *sqlx.Tx, userProfile *userprofiles.UserProfile) error {
+	return nil
+}
+
+type fakeIdentitiesRepository struct {
+	identities.IdentitiesRepository
+	links []*identities.UserIdentity
+}
+
+func (r *fakeIdentitiesRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, identity *identities.UserIdentity) error {
+	r.links = append(r.links, identity)
+	return nil
+}
+
+func (r *fakeIdentitiesRepository) GetByIssuerAndSubject(ctx context.Context, issuer, subject string) (*identities.UserIdentity, error) {
+	for _, link := range r.links {
+		if link.Issuer() == issuer && link.Subject() == subject {
+			return link, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeIdentitiesRepository) TouchLastLogin(ctx context.Context, ID string) error {
+	return nil
+}
+
+type fakeOIDCStatesRepository struct {
+	oidcstates.OIDCStatesRepository
+	states map[string]*oidcstates.LoginState
+}
+
+func (r *fakeOIDCStatesRepository) Create(ctx context.Context, state *oidcstates.LoginState) error {
+	r.states[state.ToModel().StateHash] = state
+	return nil
+}
+
+func (r *fakeOIDCStatesRepository) Consume(ctx context.Context, stateHash string) (*oidcstates.LoginState, error) {
+	state, ok := r.states[stateHash]
+	if !ok || state.IsExpired() {
+		return nil, nil
+	}
+	delete(r.states, stateHash)
+	return state, nil
+}
+
+// fakeTwoFactorService reports two-factor as enabled, so a successful login stops at
+// the challenge instead of issuing tokens.
+type fakeTwoFactorService struct {
+	twofactor.TwoFactorService
+}
+
+func (fakeTwoFactorService) IsEnabled(ctx context.Context, userID string) (bool, error) {
+	return true, nil
+}
+
+func (fakeTwoFactorService) StartChallenge(ctx context.Context, userID, loginKey string) (string, error) {
+	return "challenge", nil
+}
+
+type fakeAuditEventsService struct {
+	auditevents.AuditEventsService
+}
+
+func (fakeAuditEventsService) Record(ctx context.Context, input common.AuditEventInput) {}
+
+type oidcTestService struct {
+	*userService
+	issuer     *oidctest.Issuer
+	users      *fakeUsersRepository
+	identities *fakeIdentitiesRepository
+}
+
+func newOIDCTestService(t *testing.T) *oidcTestService {
+	t.Helper()
+
+	issuer, err := oidctest.NewIssuer("conecta-mare", "secret")
+	if err != nil {
+		t.Fatalf("failed to start issuer: %v", err)
+	}
+	t.Cleanup(issuer.Close)
+
+	db, err := sqlx.Open("users-test", "")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	usersRepo := &fakeUsersRepository{users: map[string]*models.User{}}
+	identitiesRepo := &fakeIdentitiesRepository{}
+
+	return &oidcTestService{
+		userService: &userService{
+			db:               db,
+			repository:       usersRepo,
+			userProfilesRepo: fakeUserProfilesRepository{},
+			identities:       identitiesRepo,
+			oidcStates:       &fakeOIDCStatesRepository{states: map[string]*oidcstates.LoginState{}},
+			twoFactor:        fakeTwoFactorService{},
+			audit:            fakeAuditEventsService{},
+			oidcProvider: oidc.NewProvider(oidc.Config{
+				Issuer:       issuer.URL,
+				ClientID:     issuer.ClientID,
+				ClientSecret: issuer.ClientSecret,
+				RedirectURL:  "http://localhost/callback",
+			}),
+			logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		},
+		issuer:     issuer,
+		users:      usersRepo,
+		identities: identitiesRepo,
+	}
+}
+
+// start begins a login flow and signs the user in at the issuer, returning the
+// callback the browser would bring back.
+func (s *oidcTestService) start(t *testing.T, claims oidctest.Claims) common.OIDCCallbackRequest {
+	t.Helper()
+
+	res, apiErr := s.OIDCAuthorize(context.Background(), common.OIDCAuthorizeRequest{Role: valueobjects.Client})
+	if apiErr != nil {
+		t.Fatalf("OIDCAuthorize() error = %v", apiErr.Err)
+	}
+
+	code, state, err := s.issuer.Authorize(res.AuthorizationURL, claims)
+	if err != nil {
+		t.Fatalf("failed to authorize: %v", err)
+	}
+
+	return common.OIDCCallbackRequest{Code: code, State: state}
+}
+
+func (s *oidcTestService) addUser(email string, verified bool) *models.User {
+	now := time.Now()
+	user := &models.User{
+		ID:        "user_existing",
+		Email:     &email,
+		Role:      valueobjects.Client,
+		CreatedAt: now,
+	}
+	if verified {
+		user.EmailVerifiedAt = &now
+	}
+	s.users.users[user.ID] = user
+	return user
+}
+
+func verifiedClaims(email string) oidctest.Claims {
+	return oidctest.Claims{Subject: "subject", Email: email, EmailVerified: true, Name: "Maria"}
+}
+
+func assertApiError(t *testing.T, apiErr *exceptions.ApiError[string], status int, want error) {
+	t.Helper()
+
+	if apiErr == nil {
+		t.Fatalf("error = nil, want %d %v", status, want)
+	}
+	if apiErr.Code != status || !errors.Is(apiErr.Err, want) {
+		t.Fatalf("error = %d %v, want %d %v", apiErr.Code, apiErr.Err, status, want)
+	}
+}
+
+func TestOIDCLoginRejectsUnknownState(t *testing.T) {
+	s := newOIDCTestService(t)
+	callback := s.start(t, verifiedClaims("maria@example.com"))
+	callback.State = "forged"
+
+	_, apiErr := s.OIDCLogin(context.Background(), callback)
+	assertApiError(t, apiErr, http.StatusUnauthorized, exceptions.ErrInvalidOIDCState)
+}
+
+func TestOIDCLoginRejectsStateOfAnotherFlow(t *testing.T) {
+	s := newOIDCTestService(t)
+	first := s.start(t, verifiedClaims("maria@example.com"))
+	second := s.start(t, verifiedClaims("maria@example.com"))
+
+	// The code of one flow redeemed with the state, and so the PKCE verifier, of another.
+	_, apiErr := s.OIDCLogin(context.Background(), common.OIDCCallbackRequest{Code: first.Code, State: second.State})
+	assertApiError(t, apiErr, http.StatusUnauthorized, exceptions.ErrOIDCLoginFailed)
+}
+
+func TestOIDCLoginRejectsReusedState(t *testing.T) {
+	s := newOIDCTestService(t)
+	callback := s.start(t, verifiedClaims("maria@example.com"))
+
+	if _, apiErr := s.OIDCLogin(context.Background(), callback); apiErr != nil {
+		t.Fatalf("OIDCLogin() error = %v", apiErr.Err)
+	}
+
+	_, apiErr := s.OIDCLogin(context.Background(), callback)
+	assertApiError(t, apiErr, http.StatusUnauthorized, exceptions.ErrInvalidOIDCState)
+}
+
+func TestOIDCLoginRejectsNonceMismatch(t *testing.T) {
+	s := newOIDCTestService(t)
+	claims := verifiedClaims("maria@example.com")
+	claims.Nonce = "replayed"
+	callback := s.start(t, claims)
+
+	_, apiErr := s.OIDCLogin(context.Background(), callback)
+	assertApiError(t, apiErr, http.StatusUnauthorized, exceptions.ErrOIDCLoginFailed)
+	if len(s.identities.links) != 0 {
+		t.Errorf("identities linked = %d, want none", len(s.identities.links))
+	}
+}
+
+func TestOIDCLoginRejectsUnverifiedEmail(t *testing.T) {
+	for _, emailVerified := range []any{false, "false", nil} {
+		s := newOIDCTestService(t)
+		claims := verifiedClaims("maria@example.com")
+		claims.EmailVerified = emailVerified
+		callback := s.start(t, claims)
+
+		_, apiErr := s.OIDCLogin(context.Background(), callback)
+		assertApiError(t, apiErr, http.StatusForbidden, exceptions.ErrOIDCEmailNotVerified)
+		if len(s.users.users) != 0 || len(s.identities.links) != 0 {
+			t.Errorf("email_verified=%v: account or link created for an unverified email", emailVerified)
+		}
+	}
+}
+
+func TestOIDCLoginCreatesAccount(t *testing.T) {
+	s := newOIDCTestService(t)
+	callback := s.start(t, verifiedClaims("maria@example.com"))
+
+	res, apiErr := s.OIDCLogin(context.Background(), callback)
+	if apiErr != nil {
+		t.Fatalf("OIDCLogin() error = %v", apiErr.Err)
+	}
+	if !res.TwoFactorRequired {
+		t.Fatalf("OIDCLogin() did not reach the end of the login")
+	}
+
+	created, _ := s.users.GetByEmail(context.Background(), "maria@example.com")
+	if created == nil {
+		t.Fatal("no account was created")
+	}
+	if created.EmailVerifiedAt == nil {
+		t.Error("created account email is not verified")
+	}
+	if created.Role != valueobjects.Client {
+		t.Errorf("created account role = %q, want %q", created.Role, valueobjects.Client)
+	}
+	if len(s.identities.links) != 1 || s.identities.links[0].UserID() != created.ID {
+		t.Errorf("identity was not linked to the created account")
+	}
+}
+
+func TestOIDCLoginLinksVerifiedAccount(t *testing.T) {
+	s := newOIDCTestService(t)
+	existing := s.addUser("maria@example.com", true)
+	callback := s.start(t, verifiedClaims("maria@example.com"))
+
+	if _, apiErr := s.OIDCLogin(context.Background(), callback); apiErr != nil {
+		t.Fatalf("OIDCLogin() error = %v", apiErr.Err)
+	}
+
+	if len(s.users.users) != 1 {
+		t.Errorf("accounts = %d, want the existing one only", len(s.users.users))
+	}
+	if len(s.identities.links) != 1 || s.identities.links[0].UserID() != existing.ID {
+		t.Errorf("identity was not linked to the existing account")
+	}
+}
+
+func TestOIDCLoginRefusesUnverifiedAccount(t *testing.T) {
+	s := newOIDCTestService(t)
+	s.addUser("maria@example.com", false)
+	callback := s.start(t, verifiedClaims("maria@example.com"))
+
+	_, apiErr := s.OIDCLogin(context.Background(), callback)
+	assertApiError(t, apiErr, http.StatusConflict, exceptions.ErrOIDCAccountNotVerified)
+	if len(s.identities.links) != 0 {
+		t.Errorf("identity was linked to an account whose email was never confirmed")
+	}
+}
+
+func TestOIDCLoginUsesExistingLink(t *testing.T) {
+	s := newOIDCTestService(t)
+	first := s.start(t, verifiedClaims("maria@example.com"))
+	if _, apiErr := s.OIDCLogin(context.Background(), first); apiErr != nil {
+		t.Fatalf("OIDCLogin() error = %v", apiErr.Err)
+	}
+
+	// The provider email no longer matters once the identity is linked.
+	claims := verifiedClaims("maria@example.org")
+	claims.EmailVerified = false
+	second := s.start(t, claims)
+
+	if _, apiErr := s.OIDCLogin(context.Background(), second); apiErr != nil {
+		t.Fatalf("OIDCLogin() error = %v", apiErr.Err)
+	}
+	if len(s.users.users) != 1 || len(s.identities.links) != 1 {
+		t.Errorf("accounts = %d, links = %d, want one of each", len(s.users.users), len(s.identities.links))
+	}
+}
+
+func TestDecodeProfessionalsCursorValidatesKey(t *testing.T) {
+	tests := []struct {
+		sort  string
+		key   string
+		valid bool
+	}{
+		{sort: ProfessionalsSortNewest, key: "2026-10-18 14:03:27.123456", valid: true},
+		{sort: ProfessionalsSortNewest, key: "2026-10-18 14:03:27", valid: true},
+		{sort: ProfessionalsSortNewest, key: "yesterday", valid: false},
+		{sort: ProfessionalsSortRating, key: "4.75", valid: true},
+		{sort: ProfessionalsSortRating, key: "NaN", valid: false},
+		{sort: ProfessionalsSortRating, key: "'; DROP", valid: false},
+		{sort: ProfessionalsSortRelevance, key: "0.8125", valid: true},
+		{sort: ProfessionalsSortRelevance, key: "high", valid: false},
+		{sort: ProfessionalsSortPrice, key: "2147483647", valid: true},
+		{sort: ProfessionalsSortPrice, key: "2147483648", valid: false},
+		{sort: ProfessionalsSortPrice, key: "10.5", valid: false},
+		{sort: ProfessionalsSortName, key: "maria | silva", valid: true},
+		{sort: "popularity", key: "1", valid: false},
+	}
+
+	for _, tt := range tests {
+		cursor := encodeProfessionalsCursor(tt.sort, tt.key, "user_1")
+
+		sort, key, ID, ok := decodeProfessionalsCursor(cursor)
+		if ok != tt.valid {
+			t.Errorf("decodeProfessionalsCursor(%s, %q) ok = %v, want %v", tt.sort, tt.key, ok, tt.valid)
+			continue
+		}
+		if ok && (sort != tt.sort || key != tt.key || ID != "user_1") {
+			t.Errorf("decodeProfessionalsCursor(%s, %q) = %s, %q, %s", tt.sort, tt.key, sort, key, ID)
+		}
+	}
+}
+
+func TestGetProfessionalsSendsEmptyListAsArray(t *testing.T) {
+	s := &userService{
+		repository: &fakeUsersRepository{users: map[string]*models.User{}},
+		logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	page, apiErr := s.GetProfessionals(context.Background(), common.ProfessionalsFilter{})
+	if apiErr != nil {
+		t.Fatalf("GetProfessionals() error = %v", apiErr.Err)
+	}
+
+	body, err := json.Marshal(page)
+	if err != nil {
+		t.Fatalf("failed to encode page: %v", err)
+	}
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("failed to decode page: %v", err)
+	}
+	if got := string(decoded["professionals"]); got != "[]" {
+		t.Errorf("professionals = %s, want []", got)
+	}
+}
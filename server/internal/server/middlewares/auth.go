@@ -5,23 +5,54 @@ import (
 	"conecta-mare-server/pkg/httphelpers"
 	"conecta-mare-server/pkg/jwt"
 	"context"
+	"log/slog"
 	"net/http"
 	"strings"
 )
 
 type AuthKey struct{}
 
-type middleware struct {
-	accessKey string
+// SessionValidator reports whether the session behind an access token is still usable,
+// meaning it was not revoked and its user was not disabled.
+type SessionValidator interface {
+	IsSessionValid(ctx context.Context, sessionID, userID string) (bool, error)
 }
 
-func NewWithAuth(accessKey string) *middleware {
-	return &middleware{
-		accessKey: accessKey,
+// AccessTokenVerifier checks the signature and expiry of access tokens.
+type AccessTokenVerifier interface {
+	VerifyAccessToken(tokenStr string) (*jwt.Claims, error)
+}
+
+type AuthMiddleware struct {
+	tokenVerifier        AccessTokenVerifier
+	sessionValidator     SessionValidator
+	emailVerifier        EmailVerificationChecker
+	verifiedEmailActions map[string]struct{}
+	logger               *slog.Logger
+}
+
+func NewWithAuth(
+	tokenVerifier AccessTokenVerifier,
+	sessionValidator SessionValidator,
+	emailVerifier EmailVerificationChecker,
+	verifiedEmailActions []string,
+	logger *slog.Logger,
+) *AuthMiddleware {
+	actions := make(map[string]struct{}, len(verifiedEmailActions))
+	for _, action := range verifiedEmailActions {
+		actions[action] = struct{}{}
+	}
+
+	return &AuthMiddleware{
+		tokenVerifier:        tokenVerifier,
+		sessionValidator:     sessionValidator,
+		emailVerifier:        emailVerifier,
+		verifiedEmailActions: actions,
+		logger:               logger,
 	}
 }
 
-func (m *middleware) WithAuth(next http.Handler) http.Handler {
+func (m *AuthMiddleware) WithAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		accessToken := r.Header.Get("Authorization")
 
@@ -31,7 +62,7 @@ func (m *middleware) WithAuth(next http.Handler) http.Handler {
 			return
 		}
 
-		claims, err := jwt.Verify(m.accessKey, accessToken)
+		claims, err := m.tokenVerifier.VerifyAccessToken(accessToken)
 		if err != nil {
 			if strings.Contains(err.Error(), "token has expired") {
 				apiError := exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrTokenExpired)
@@ -43,6 +74,27 @@ func (m *middleware) WithAuth(next http.Handler) http.Handler {
 			return
 		}
 
+		// Tokens issued before sessions and roles were bound to access tokens carry
+		// neither, so they are rejected and the client falls back to the refresh flow.
+		if claims.SessionID == "" || claims.Role == "" {
+			apiError := exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrTokenExpired)
+			httphelpers.WriteJSON(w, apiError.Code, apiError)
+			return
+		}
+
+		valid, err := m.sessionValidator.IsSessionValid(r.Context(), claims.SessionID, claims.UserID)
+		if err != nil {
+			m.logger.ErrorContext(r.Context(), "error while attempting to validate session", "session_id", claims.SessionID, "err", err)
+			apiError := exceptions.MakeGenericApiError()
+			httphelpers.WriteJSON(w, apiError.Code, apiError)
+			return
+		}
+		if !valid {
+			apiError := exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrSessionRevoked)
+			httphelpers.WriteJSON(w, apiError.Code, apiError)
+			return
+		}
+
 		ctx := context.WithValue(r.Context(), AuthKey{}, claims)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"conecta-mare-server/pkg/httphelpers"
+	"context"
+	"net/http"
+)
+
+type ClientInfoKey struct{}
+
+// ClientInfo identifies where a request came from so services can record it
+// without every handler threading it through.
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+func WithClientInfo(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := ClientInfo{
+			IPAddress: httphelpers.ReadClientIP(r),
+			UserAgent: r.UserAgent(),
+		}
+
+		ctx := context.WithValue(r.Context(), ClientInfoKey{}, info)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ClientInfoFromContext returns the client info stored by WithClientInfo, or an
+// empty value for contexts that did not come from a request.
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(ClientInfoKey{}).(ClientInfo)
+	return info
+}
@@ -0,0 +1,34 @@
+package middlewares
+
+import (
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/httphelpers"
+	"conecta-mare-server/pkg/jwt"
+	"conecta-mare-server/pkg/valueobjects"
+	"net/http"
+	"slices"
+)
+
+// RequireRole only lets through users whose token carries one of the given roles.
+// It must run after WithAuth.
+func (m *AuthMiddleware) RequireRole(roles ...valueobjects.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c, ok := r.Context().Value(AuthKey{}).(*jwt.Claims)
+			if !ok {
+				apiError := exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+				httphelpers.WriteJSON(w, apiError.Code, apiError)
+				return
+			}
+
+			if !slices.Contains(roles, c.Role) {
+				m.logger.WarnContext(r.Context(), "user role not allowed for route", "user_id", c.UserID, "role", c.Role, "path", r.URL.Path)
+				apiError := exceptions.MakeApiErrorWithStatus(http.StatusForbidden, exceptions.ErrForbidden)
+				httphelpers.WriteJSON(w, apiError.Code, apiError)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
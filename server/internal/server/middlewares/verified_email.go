@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	"conecta-mare-server/pkg/exceptions"
+	"conecta-mare-server/pkg/httphelpers"
+	"conecta-mare-server/pkg/jwt"
+	"context"
+	"net/http"
+)
+
+// Actions that can be configured, through EMAIL_VERIFICATION_REQUIRED_FOR, to demand a verified email.
+const (
+	ActionCompleteOnboarding = "onboarding"
+	ActionLeaveReview        = "review"
+)
+
+type EmailVerificationChecker interface {
+	IsEmailVerified(ctx context.Context, userID string) (bool, error)
+}
+
+// RequireVerifiedEmail blocks the action for users that did not confirm their email yet,
+// but only when the action is listed in the configuration. It must run after WithAuth.
+func (m *AuthMiddleware) RequireVerifiedEmail(action string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, required := m.verifiedEmailActions[action]; !required {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			c, ok := r.Context().Value(AuthKey{}).(*jwt.Claims)
+			if !ok {
+				apiError := exceptions.MakeApiErrorWithStatus(http.StatusUnauthorized, exceptions.ErrUnauthorized)
+				httphelpers.WriteJSON(w, apiError.Code, apiError)
+				return
+			}
+
+			verified, err := m.emailVerifier.IsEmailVerified(r.Context(), c.UserID)
+			if err != nil {
+				m.logger.ErrorContext(r.Context(), "error while attempting to check email verification", "user_id", c.UserID, "err", err)
+				apiError := exceptions.MakeGenericApiError()
+				httphelpers.WriteJSON(w, apiError.Code, apiError)
+				return
+			}
+			if !verified {
+				apiError := exceptions.MakeApiErrorWithStatus(http.StatusForbidden, exceptions.ErrEmailNotVerified)
+				httphelpers.WriteJSON(w, apiError.Code, apiError)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
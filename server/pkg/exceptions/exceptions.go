@@ -12,36 +12,92 @@ import (
 )
 
 var (
-	ErrClientCannotContainSubcat = errors.New("client cannot have subcategory")
-	ErrProfessinalWithoutSubcat  = errors.New("professional cannot have empty subcategory")
-	ErrRoleEmpty                 = errors.New("role cannot be empty")
-	ErrNameEmpty                 = errors.New("name cannot be empty")
-	ErrEmailEmpty                = errors.New("email cannot be empty")
-	ErrEmailInvalid              = errors.New("email is invalid")
-	ErrPasswordEmpty             = errors.New("password cannot be empty")
-	ErrPasswordMatch             = errors.New("password and confirm password doesnt match")
-	ErrInvalidLoginAttempt       = errors.New("email or password is invalid")
-	ErrUserNotFound              = errors.New("user was not found")
-	ErrUserDisabled              = errors.New("user not active")
-	ErrEmailTaken                = errors.New("email already exists")
-	ErrInvalidSigningMethod      = errors.New("invalid token signing method")
-	ErrUnauthorized              = errors.New("unauthorized attempt to access resource")
-	ErrInternalServerError       = errors.New("unexpected error occurred")
-	ErrInvalidRequestBody        = errors.New("request body is invalid")
-	ErrInvalidLimitOrOffsetValue = errors.New("limit or offset value is not a valid integer")
-	ErrInvalidTokenHeader        = errors.New("authorization token is malformed")
-	ErrInvalidRole               = errors.New("role is invalid")
-	ErrCannotFollowSelf          = errors.New("cannot follow or unfollow self")
-	ErrNilInput                  = errors.New("cannot pass nil value")
-	ErrAvatarEmpty               = errors.New("avatar cannot be empty")
-	ErrAvatarTooLarge            = errors.New("avatar needs to be max 5mb")
-	ErrActiveSessionNotFound     = errors.New("active session not found")
-	ErrAccesTokenNotFound        = errors.New("access token not found")
-	ErrTokenExpired              = errors.New("token expired")
-	ErrCategoriesNotFound        = errors.New("any category found")
-	ErrSubcategoriesNotFound     = errors.New("any subcategory found")
-	ErrInvalidJSON               = errors.New("invalid json")
-	ErrUserIDRequired            = errors.New("user_id is required")
+	ErrClientCannotContainSubcat     = errors.New("client cannot have subcategory")
+	ErrProfessinalWithoutSubcat      = errors.New("professional cannot have empty subcategory")
+	ErrRoleEmpty                     = errors.New("role cannot be empty")
+	ErrNameEmpty                     = errors.New("name cannot be empty")
+	ErrEmailEmpty                    = errors.New("email cannot be empty")
+	ErrEmailInvalid                  = errors.New("email is invalid")
+	ErrPasswordEmpty                 = errors.New("password cannot be empty")
+	ErrPasswordMatch                 = errors.New("password and confirm password doesnt match")
+	ErrInvalidLoginAttempt           = errors.New("email or password is invalid")
+	ErrUserNotFound                  = errors.New("user was not found")
+	ErrUserDisabled                  = errors.New("user not active")
+	ErrEmailTaken                    = errors.New("email already exists")
+	ErrInvalidSigningMethod          = errors.New("invalid token signing method")
+	ErrUnauthorized                  = errors.New("unauthorized attempt to access resource")
+	ErrInternalServerError           = errors.New("unexpected error occurred")
+	ErrInvalidRequestBody            = errors.New("request body is invalid")
+	ErrInvalidLimitOrOffsetValue     = errors.New("limit or offset value is not a valid integer")
+	ErrInvalidTokenHeader            = errors.New("authorization token is malformed")
+	ErrInvalidRole                   = errors.New("role is invalid")
+	ErrForbidden                     = errors.New("user role is not allowed to access resource")
+	ErrWrongCurrentPassword          = errors.New("current password is incorrect")
+	ErrCannotChangeOwnRole           = errors.New("cannot change own role")
+	ErrCannotFollowSelf              = errors.New("cannot follow or unfollow self")
+	ErrNilInput                      = errors.New("cannot pass nil value")
+	ErrAvatarEmpty                   = errors.New("avatar cannot be empty")
+	ErrAvatarTooLarge                = errors.New("avatar needs to be max 5mb")
+	ErrActiveSessionNotFound         = errors.New("active session not found")
+	ErrAccesTokenNotFound            = errors.New("access token not found")
+	ErrTokenExpired                  = errors.New("token expired")
+	ErrCategoriesNotFound            = errors.New("any category found")
+	ErrSubcategoriesNotFound         = errors.New("any subcategory found")
+	ErrInvalidJSON                   = errors.New("invalid json")
+	ErrUserIDRequired                = errors.New("user_id is required")
+	ErrRefreshTokenNotFound          = errors.New("refresh token not found")
+	ErrSessionNotFound               = errors.New("session not found")
+	ErrSessionRevoked                = errors.New("session was revoked")
+	ErrInvalidResetToken             = errors.New("password reset token is invalid or expired")
+	ErrInvalidVerificationToken      = errors.New("email verification token is invalid or expired")
+	ErrEmailNotVerified              = errors.New("email must be verified to perform this action")
+	ErrEmailAlreadyVerified          = errors.New("email already verified")
+	ErrTooManyRequests               = errors.New("too many requests, try again later")
+	ErrDataExportInProgress          = errors.New("a data export is already being prepared")
+	ErrDataExportNotFound            = errors.New("data export not found")
+	ErrInvalidDownloadLink           = errors.New("download link is invalid or expired")
+	ErrOIDCDisabled                  = errors.New("single sign-on is not enabled")
+	ErrOIDCProviderUnavailable       = errors.New("identity provider is unavailable, try again later")
+	ErrInvalidOIDCState              = errors.New("login session is invalid or expired, start again")
+	ErrOIDCLoginFailed               = errors.New("could not sign in with the identity provider")
+	ErrOIDCEmailNotVerified          = errors.New("the identity provider did not confirm this email")
+	ErrOIDCAccountNotVerified        = errors.New("an account with this email exists but was never confirmed, sign in with its password or reset it")
+	ErrTwoFactorAlreadyEnabled       = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled          = errors.New("two-factor authentication is not set up")
+	ErrInvalidTwoFactorCode          = errors.New("invalid two-factor code")
+	ErrInvalidTwoFactorChallenge     = errors.New("login challenge is invalid or expired, sign in again")
+	ErrEmailOrPhoneRequired          = errors.New("email or phone is required")
+	ErrInvalidPhone                  = errors.New("phone is invalid. use the 219887654321 format")
+	ErrPhoneTaken                    = errors.New("phone already in use")
+	ErrInvalidPhoneCode              = errors.New("invalid or expired code")
+	ErrNoEmailOnAccount              = errors.New("this account has no email address")
+	ErrInvalidCursor                 = errors.New("cursor is invalid")
+	ErrInvalidDateFilter             = errors.New("date filters must use the RFC 3339 format")
+	ErrUserProfileNotFound           = errors.New("user profile not found")
+	ErrSubcategoryNotFound           = errors.New("subcategory not found")
+	ErrOnboardingPending             = errors.New("complete the onboarding before editing the professional profile")
+	ErrServiceNotFound               = errors.New("service not found")
+	ErrServiceImageNotFound          = errors.New("service image not found")
+	ErrProjectNotFound               = errors.New("project not found")
+	ErrProjectImageNotFound          = errors.New("project image not found")
+	ErrInvalidOrdering               = errors.New("ids must list every item exactly once")
+	ErrInvalidImage                  = errors.New("images must be jpeg, png or webp files")
+	ErrTooManyImages                 = errors.New("image limit reached")
+	ErrImagesRequired                = errors.New("at least one image is required")
+	ErrCertificationNotFound         = errors.New("certification not found")
+	ErrInvalidCertificationDocument  = errors.New("proof documents must be pdf, jpeg, png or webp files up to 10MB")
+	ErrCertificationDocumentNotFound = errors.New("certification has no proof document")
+	ErrInvalidCertificationStatus    = errors.New("status must be pending, verified or rejected")
+	ErrCommunityNotFound             = errors.New("community not found")
+	ErrDuplicateServiceArea          = errors.New("each community can only be listed once")
+	ErrInvalidSort                   = errors.New("sort must be newest, rating, price or name")
+	ErrInvalidPriceRange             = errors.New("min_price cannot be greater than max_price")
+	ErrInvalidRatingFilter           = errors.New("min_rating must be between 1 and 5")
+	ErrInvalidSearchQuery            = errors.New("q is required and must have at most 100 characters")
+	ErrSearchTermNotFound            = errors.New("search term not found")
+	ErrSearchTermTaken               = errors.New("term is already linked to these subcategories")
+	ErrInvalidImportFile             = errors.New("import file must be a csv with term and subcategory columns, up to 1000 rows")
+	ErrRefreshTokenReused            = errors.New("refresh token reuse detected, all sessions were revoked")
 )
 
 func IsValidSqlErr(err error) bool {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -92,6 +93,33 @@ func ReadQueryString(qs url.Values, key, defval string) string {
 	return val
 }
 
+// ReadClientIP returns the address of the client that originated the request.
+// Since the API runs behind nginx, which overwrites X-Real-IP, that header is trusted first,
+// then the first X-Forwarded-For entry, falling back to the connection remote address.
+//
+// Example:
+//
+//	ip := util.ReadClientIP(r)
+func ReadClientIP(r *http.Request) string {
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+		return realIP
+	}
+
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		first, _, _ := strings.Cut(forwarded, ",")
+		if ip := strings.TrimSpace(first); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
 // ReadRequestBody reads and parses the JSON body of an HTTP request into the provided destination struct.
 // It limits the size of the request body to 1MB and returns detailed error messages for various parsing issues.
 //
@@ -0,0 +1,24 @@
+// Package jobs runs background work inside the API process.
+package jobs
+
+import (
+	"context"
+	"time"
+)
+
+// Every runs fn right away and then once per interval until ctx is cancelled.
+// Runs never overlap: a slow run delays the next one.
+func Every(ctx context.Context, interval time.Duration, fn func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fn(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"conecta-mare-server/pkg/uid"
+	"conecta-mare-server/pkg/valueobjects"
 	"fmt"
 	"strings"
 	"time"
@@ -10,8 +11,10 @@ import (
 )
 
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
+	UserID    string            `json:"user_id"`
+	Email     string            `json:"email"`
+	Role      valueobjects.Role `json:"role"`
+	SessionID string            `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -21,12 +24,14 @@ func GenerateUserToken(secretKey string, claims *Claims) (string, error) {
 	return jwtToken.SignedString([]byte(secretKey))
 }
 
-func GenerateClaims(id, email string, duration time.Duration) *Claims {
+func GenerateClaims(id, email string, role valueobjects.Role, sessionID string, duration time.Duration) *Claims {
 	jti := uid.New("jti")
 
 	return &Claims{
-		UserID: id,
-		Email:  email,
+		UserID:    id,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
@@ -0,0 +1,248 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	hmacKeyID = "hs256"
+)
+
+type verificationKey struct {
+	method jwt.SigningMethod
+	key    any
+}
+
+// KeySet signs tokens with a single active key and verifies them with any known key,
+// picked by the "kid" header. Keeping retired keys around for verification lets keys
+// rotate without invalidating tokens that were already issued.
+type KeySet struct {
+	signingKeyID  string
+	signingMethod jwt.SigningMethod
+	signingKey    any
+	keys          map[string]verificationKey
+}
+
+// NewHMACKeySet keeps the shared secret behaviour. Nothing is published in the JWKS.
+func NewHMACKeySet(secret string) *KeySet {
+	return &KeySet{
+		signingKeyID:  hmacKeyID,
+		signingMethod: jwt.SigningMethodHS256,
+		signingKey:    []byte(secret),
+		keys: map[string]verificationKey{
+			hmacKeyID: {method: jwt.SigningMethodHS256, key: []byte(secret)},
+		},
+	}
+}
+
+// LoadKeySet reads every PEM file in dir, using the file name without extension as
+// the key ID. Private keys ("<kid>.pem") can sign and verify, public keys
+// ("<kid>.pub.pem") only verify, which is how a retired key is kept until the
+// tokens it signed expire. The key named activeKeyID signs new tokens and must
+// match algorithm.
+func LoadKeySet(algorithm, dir, activeKeyID string) (*KeySet, error) {
+	if algorithm != AlgorithmRS256 && algorithm != AlgorithmEdDSA {
+		return nil, fmt.Errorf("unsupported asymmetric algorithm %q", algorithm)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys in %s: %w", dir, err)
+	}
+
+	ks := &KeySet{keys: make(map[string]verificationKey)}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key %s: %w", file, err)
+		}
+
+		keyID := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(file), ".pem"), ".pub")
+		private, public, err := parsePEMKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key %s: %w", file, err)
+		}
+
+		method, err := methodForKey(public)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", file, err)
+		}
+		ks.keys[keyID] = verificationKey{method: method, key: public}
+
+		if keyID == activeKeyID {
+			if private == nil {
+				return nil, fmt.Errorf("active key %s has no private key", keyID)
+			}
+			if method.Alg() != algorithm {
+				return nil, fmt.Errorf("active key %s is %s, expected %s", keyID, method.Alg(), algorithm)
+			}
+			ks.signingKeyID = keyID
+			ks.signingMethod = method
+			ks.signingKey = private
+		}
+	}
+
+	if ks.signingKey == nil {
+		return nil, fmt.Errorf("active key %q not found in %s", activeKeyID, dir)
+	}
+
+	return ks, nil
+}
+
+func parsePEMKey(data []byte) (private any, public any, err error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, nil, fmt.Errorf("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch k := key.(type) {
+		case ed25519.PrivateKey:
+			return k, k.Public(), nil
+		case *rsa.PrivateKey:
+			return k, &k.PublicKey, nil
+		}
+		return nil, nil, fmt.Errorf("unsupported private key type %T", key)
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		return key, &key.PublicKey, nil
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, key, nil
+	}
+
+	return nil, nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+}
+
+func methodForKey(public any) (jwt.SigningMethod, error) {
+	switch public.(type) {
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	}
+	return nil, fmt.Errorf("unsupported public key type %T", public)
+}
+
+func (ks *KeySet) Sign(claims *Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signingMethod, claims)
+	token.Header["kid"] = ks.signingKeyID
+
+	return token.SignedString(ks.signingKey)
+}
+
+func (ks *KeySet) Verify(value string) (*Claims, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	value = strings.TrimPrefix(value, "Bearer ")
+
+	keyFunc := func(token *jwt.Token) (any, error) {
+		keyID, _ := token.Header["kid"].(string)
+		// Tokens signed before key IDs existed fall back to the active key.
+		if keyID == "" {
+			keyID = ks.signingKeyID
+		}
+
+		key, ok := ks.keys[keyID]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", keyID)
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("invalid token signing method")
+		}
+		return key.key, nil
+	}
+
+	token, err := jwt.ParseWithClaims(value, &Claims{}, keyFunc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token claims")
+	}
+
+	return claims, nil
+}
+
+// JWK is the public part of a verification key as described by RFC 7517.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS lists the public verification keys. Shared secrets are never exposed.
+func (ks *KeySet) JWKS() JWKS {
+	keyIDs := make([]string, 0, len(ks.keys))
+	for keyID := range ks.keys {
+		keyIDs = append(keyIDs, keyID)
+	}
+	sort.Strings(keyIDs)
+
+	jwks := JWKS{Keys: []JWK{}}
+	for _, keyID := range keyIDs {
+		key := ks.keys[keyID]
+		switch k := key.key.(type) {
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				KeyType:   "OKP",
+				KeyID:     keyID,
+				Use:       "sig",
+				Algorithm: AlgorithmEdDSA,
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(k),
+			})
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				KeyType:   "RSA",
+				KeyID:     keyID,
+				Use:       "sig",
+				Algorithm: AlgorithmRS256,
+				N:         base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+			})
+		}
+	}
+
+	return jwks
+}
@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"conecta-mare-server/pkg/valueobjects"
 	"time"
 )
 
@@ -9,41 +10,52 @@ const (
 	RefreshTokenDuration = 720 * time.Hour
 )
 
+// JWTProvider signs access tokens with a KeySet, so they can be verified by other
+// services through the JWKS. Refresh tokens are only ever read by this server and
+// keep using a shared secret.
 type JWTProvider struct {
-	accessKey  string
+	accessKeys *KeySet
 	refreshKey string
 }
 
 type TokenUser interface {
 	ID() string
 	Email() string
+	Role() valueobjects.Role
 }
 
-func NewProvider(accessKey, refreshKey string) *JWTProvider {
-	return &JWTProvider{accessKey, refreshKey}
+func NewProvider(accessKeys *KeySet, refreshKey string) *JWTProvider {
+	return &JWTProvider{accessKeys, refreshKey}
 }
 
-func (j *JWTProvider) generateToken(user TokenUser, key string, duration time.Duration) (*string, *Claims, error) {
-	claims := GenerateClaims(user.ID(), user.Email(), duration)
-	token, err := GenerateUserToken(key, claims)
+// GenerateAccessToken issues an access token bound to the session that owns it,
+// so the session can be checked on every authenticated request.
+func (j *JWTProvider) GenerateAccessToken(user TokenUser, sessionID string) (*string, *Claims, error) {
+	claims := GenerateClaims(user.ID(), user.Email(), user.Role(), sessionID, AccessTokenDuration)
+	token, err := j.accessKeys.Sign(claims)
 	if err != nil {
 		return nil, nil, err
 	}
 	return &token, claims, nil
 }
 
-func (j *JWTProvider) GenerateAccessToken(user TokenUser) (*string, *Claims, error) {
-	return j.generateToken(user, j.accessKey, AccessTokenDuration)
-}
-
 func (j *JWTProvider) GenerateRefreshToken(user TokenUser) (*string, *Claims, error) {
-	return j.generateToken(user, j.refreshKey, RefreshTokenDuration)
+	claims := GenerateClaims(user.ID(), user.Email(), user.Role(), "", RefreshTokenDuration)
+	token, err := GenerateUserToken(j.refreshKey, claims)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &token, claims, nil
 }
 
 func (j *JWTProvider) VerifyAccessToken(tokenStr string) (*Claims, error) {
-	return Verify(j.accessKey, tokenStr)
+	return j.accessKeys.Verify(tokenStr)
 }
 
 func (j *JWTProvider) VerifyRefreshToken(tokenStr string) (*Claims, error) {
 	return Verify(j.refreshKey, tokenStr)
 }
+
+func (j *JWTProvider) JWKS() JWKS {
+	return j.accessKeys.JWKS()
+}
@@ -0,0 +1,137 @@
+// Package lockout throttles repeated failures, such as wrong passwords, with an
+// exponential backoff followed by a temporary lock.
+package lockout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Store keeps failure counters and locks. MemoryStore fits a single node,
+// RedisStore shares the state between several nodes.
+type Store interface {
+	// Increment adds a failure to key and returns the total. The counter is
+	// forgotten when no failure happens for ttl.
+	Increment(ctx context.Context, key string, ttl time.Duration) (int, error)
+	Lock(ctx context.Context, key string, duration time.Duration) error
+	// LockedFor returns how long key remains locked, zero when it is not locked.
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	// Reset clears both the counter and the lock of key.
+	Reset(ctx context.Context, key string) error
+}
+
+// Policy describes how many failures are tolerated before the backoff starts
+// and how it grows. Each failure past FreeAttempts doubles the lock, up to MaxLock.
+type Policy struct {
+	FreeAttempts int
+	BaseLock     time.Duration
+	MaxLock      time.Duration
+	Window       time.Duration
+}
+
+func (p Policy) lockFor(failures int) time.Duration {
+	if failures <= p.FreeAttempts {
+		return 0
+	}
+
+	lock := p.BaseLock
+	for i := p.FreeAttempts + 1; i < failures && lock < p.MaxLock; i++ {
+		lock *= 2
+	}
+
+	return min(lock, p.MaxLock)
+}
+
+var (
+	// DefaultEmailPolicy targets guessing against a single account.
+	DefaultEmailPolicy = Policy{FreeAttempts: 5, BaseLock: time.Minute, MaxLock: time.Hour, Window: 24 * time.Hour}
+	// DefaultIPPolicy is looser because many users in the same community may share an address.
+	DefaultIPPolicy = Policy{FreeAttempts: 20, BaseLock: time.Minute, MaxLock: time.Hour, Window: 24 * time.Hour}
+)
+
+// LockedError is returned while an email or an address is locked.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return "too many failed attempts, try again later"
+}
+
+// LoginGuard tracks failed logins per email and per client IP.
+type LoginGuard struct {
+	store       Store
+	emailPolicy Policy
+	ipPolicy    Policy
+}
+
+func NewLoginGuard(store Store, emailPolicy, ipPolicy Policy) *LoginGuard {
+	return &LoginGuard{
+		store:       store,
+		emailPolicy: emailPolicy,
+		ipPolicy:    ipPolicy,
+	}
+}
+
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check returns a *LockedError when either the email or the IP is locked.
+func (g *LoginGuard) Check(ctx context.Context, email, ip string) error {
+	keys := []string{emailKey(email)}
+	if ip != "" {
+		keys = append(keys, ipKey(ip))
+	}
+
+	var retryAfter time.Duration
+	for _, key := range keys {
+		lockedFor, err := g.store.LockedFor(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to read lock for %s: %w", key, err)
+		}
+		retryAfter = max(retryAfter, lockedFor)
+	}
+
+	if retryAfter > 0 {
+		return &LockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// Fail records a failed attempt and locks the email or the IP once their policy allows no more tries.
+func (g *LoginGuard) Fail(ctx context.Context, email, ip string) error {
+	if err := g.fail(ctx, emailKey(email), g.emailPolicy); err != nil {
+		return err
+	}
+	if ip == "" {
+		return nil
+	}
+	return g.fail(ctx, ipKey(ip), g.ipPolicy)
+}
+
+func (g *LoginGuard) fail(ctx context.Context, key string, policy Policy) error {
+	failures, err := g.store.Increment(ctx, key, policy.Window)
+	if err != nil {
+		return fmt.Errorf("failed to record failure for %s: %w", key, err)
+	}
+
+	if lock := policy.lockFor(failures); lock > 0 {
+		if err := g.store.Lock(ctx, key, lock); err != nil {
+			return fmt.Errorf("failed to lock %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Succeed clears the failures of the email. The IP counter is kept so one valid
+// account cannot be used to keep guessing others from the same address.
+func (g *LoginGuard) Succeed(ctx context.Context, email string) error {
+	return g.store.Reset(ctx, emailKey(email))
+}
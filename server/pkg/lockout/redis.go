@@ -0,0 +1,54 @@
+package lockout
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "lockout:"
+
+// RedisStore shares counters and locks between every instance of the server.
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func failuresKey(key string) string { return redisKeyPrefix + "failures:" + key }
+func lockKey(key string) string     { return redisKeyPrefix + "lock:" + key }
+
+func (s *RedisStore) Increment(ctx context.Context, key string, ttl time.Duration) (int, error) {
+	pipe := s.client.TxPipeline()
+	incr := pipe.Incr(ctx, failuresKey(key))
+	pipe.Expire(ctx, failuresKey(key), ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	return int(incr.Val()), nil
+}
+
+func (s *RedisStore) Lock(ctx context.Context, key string, duration time.Duration) error {
+	return s.client.Set(ctx, lockKey(key), 1, duration).Err()
+}
+
+func (s *RedisStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.client.PTTL(ctx, lockKey(key)).Result()
+	if err != nil {
+		return 0, err
+	}
+	// PTTL answers with negative values when the key is missing or has no expiry.
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func (s *RedisStore) Reset(ctx context.Context, key string) error {
+	return s.client.Del(ctx, failuresKey(key), lockKey(key)).Err()
+}
@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LogMailer never delivers emails. It logs them and, when an outbox directory is
+// configured, writes each message to a file so links can be picked up locally.
+type LogMailer struct {
+	outboxDir string
+	logger    *slog.Logger
+}
+
+func NewLogMailer(outboxDir string, logger *slog.Logger) *LogMailer {
+	return &LogMailer{
+		outboxDir: outboxDir,
+		logger:    logger,
+	}
+}
+
+func (m *LogMailer) Send(ctx context.Context, message Message) error {
+	m.logger.InfoContext(ctx, "email not delivered, logging it instead", "to", message.To, "subject", message.Subject, "text", message.Text)
+
+	if m.outboxDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(m.outboxDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+
+	recipient := strings.NewReplacer("@", "_at_", "/", "_").Replace(message.To)
+	fileName := fmt.Sprintf("%d_%s.txt", time.Now().UnixNano(), recipient)
+	content := fmt.Sprintf("To: %s\nSubject: %s\n\n%s\n", message.To, message.Subject, message.Text)
+
+	if err := os.WriteFile(filepath.Join(m.outboxDir, fileName), []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write email to outbox: %w", err)
+	}
+
+	return nil
+}
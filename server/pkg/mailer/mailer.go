@@ -0,0 +1,17 @@
+package mailer
+
+import "context"
+
+// Message is a transactional email ready to be delivered.
+type Message struct {
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Mailer delivers transactional emails. Resend is used in production while the
+// log mailer keeps messages local for development and tests.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
@@ -0,0 +1,68 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const resendEndpoint = "https://api.resend.com/emails"
+
+type ResendMailer struct {
+	apiKey string
+	from   string
+	client *http.Client
+}
+
+func NewResendMailer(apiKey, from string) *ResendMailer {
+	return &ResendMailer{
+		apiKey: apiKey,
+		from:   from,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type resendRequest struct {
+	From    string   `json:"from"`
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	HTML    string   `json:"html,omitempty"`
+	Text    string   `json:"text,omitempty"`
+}
+
+func (m *ResendMailer) Send(ctx context.Context, message Message) error {
+	payload, err := json.Marshal(resendRequest{
+		From:    m.from,
+		To:      []string{message.To},
+		Subject: message.Subject,
+		HTML:    message.HTML,
+		Text:    message.Text,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode email payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, resendEndpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build resend request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call resend: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("resend responded with status %d: %s", res.StatusCode, body)
+	}
+
+	return nil
+}
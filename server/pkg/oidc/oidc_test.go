@@ -0,0 +1,137 @@
+package oidc_test
+
+import (
+	"conecta-mare-server/pkg/oidc"
+	"conecta-mare-server/pkg/oidc/oidctest"
+	"context"
+	"errors"
+	"testing"
+)
+
+func newProvider(t *testing.T) (*oidc.Provider, *oidctest.Issuer) {
+	t.Helper()
+
+	issuer, err := oidctest.NewIssuer("conecta-mare", "secret")
+	if err != nil {
+		t.Fatalf("failed to start issuer: %v", err)
+	}
+	t.Cleanup(issuer.Close)
+
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:       issuer.URL,
+		ClientID:     issuer.ClientID,
+		ClientSecret: issuer.ClientSecret,
+		RedirectURL:  "http://localhost/callback",
+	})
+
+	return provider, issuer
+}
+
+// authorize starts a flow with a fresh verifier and returns the code the issuer
+// sent back together with that verifier.
+func authorize(t *testing.T, provider *oidc.Provider, issuer *oidctest.Issuer, nonce string, claims oidctest.Claims) (string, string) {
+	t.Helper()
+
+	codeVerifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		t.Fatalf("failed to create code verifier: %v", err)
+	}
+
+	authorizationURL, err := provider.AuthCodeURL(context.Background(), "state", nonce, oidc.CodeChallenge(codeVerifier))
+	if err != nil {
+		t.Fatalf("failed to build authorization url: %v", err)
+	}
+
+	code, state, err := issuer.Authorize(authorizationURL, claims)
+	if err != nil {
+		t.Fatalf("failed to authorize: %v", err)
+	}
+	if state != "state" {
+		t.Fatalf("state = %q, want it sent back unchanged", state)
+	}
+
+	return code, codeVerifier
+}
+
+func TestExchangeReturnsIdentity(t *testing.T) {
+	provider, issuer := newProvider(t)
+	code, codeVerifier := authorize(t, provider, issuer, "nonce", oidctest.Claims{
+		Subject:       "subject",
+		Email:         " maria@example.com ",
+		EmailVerified: true,
+		Name:          "Maria",
+	})
+
+	identity, err := provider.Exchange(context.Background(), code, codeVerifier, "nonce")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+
+	want := oidc.Identity{
+		Issuer:        issuer.URL,
+		Subject:       "subject",
+		Email:         "maria@example.com",
+		EmailVerified: true,
+		Name:          "Maria",
+	}
+	if *identity != want {
+		t.Errorf("Exchange() = %+v, want %+v", *identity, want)
+	}
+}
+
+func TestExchangeRejectsAnotherFlowsVerifier(t *testing.T) {
+	provider, issuer := newProvider(t)
+	code, _ := authorize(t, provider, issuer, "nonce", oidctest.Claims{Subject: "subject"})
+
+	otherVerifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		t.Fatalf("failed to create code verifier: %v", err)
+	}
+
+	if _, err := provider.Exchange(context.Background(), code, otherVerifier, "nonce"); err == nil {
+		t.Fatal("Exchange() succeeded with a code verifier that does not match the challenge")
+	}
+}
+
+func TestExchangeRejectsNonceMismatch(t *testing.T) {
+	provider, issuer := newProvider(t)
+	code, codeVerifier := authorize(t, provider, issuer, "nonce", oidctest.Claims{Subject: "subject", Nonce: "replayed"})
+
+	_, err := provider.Exchange(context.Background(), code, codeVerifier, "nonce")
+	if !errors.Is(err, oidc.ErrNonceMismatch) {
+		t.Fatalf("Exchange() error = %v, want %v", err, oidc.ErrNonceMismatch)
+	}
+}
+
+func TestExchangeReadsEmailVerified(t *testing.T) {
+	tests := []struct {
+		name          string
+		emailVerified any
+		want          bool
+	}{
+		{name: "boolean true", emailVerified: true, want: true},
+		{name: "string true", emailVerified: "true", want: true},
+		{name: "boolean false", emailVerified: false, want: false},
+		{name: "string false", emailVerified: "false", want: false},
+		{name: "missing", emailVerified: nil, want: false},
+	}
+
+	provider, issuer := newProvider(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, codeVerifier := authorize(t, provider, issuer, "nonce", oidctest.Claims{
+				Subject:       "subject",
+				Email:         "maria@example.com",
+				EmailVerified: tt.emailVerified,
+			})
+
+			identity, err := provider.Exchange(context.Background(), code, codeVerifier, "nonce")
+			if err != nil {
+				t.Fatalf("Exchange() error = %v", err)
+			}
+			if identity.EmailVerified != tt.want {
+				t.Errorf("EmailVerified = %v, want %v", identity.EmailVerified, tt.want)
+			}
+		})
+	}
+}
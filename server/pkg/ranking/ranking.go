@@ -0,0 +1,159 @@
+// Package ranking orders professionals by a weighted sum of signals, each scored
+// from 0 to 1, and explains how every score was reached.
+package ranking
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Names of the built-in signals, also used as keys in Weights.
+const (
+	SignalRating       = "rating"
+	SignalCompleteness = "completeness"
+	SignalResponseRate = "response_rate"
+	SignalActivity     = "activity"
+	SignalProximity    = "proximity"
+)
+
+// CommunityFit tells how a professional relates to the community the client picked.
+type CommunityFit int
+
+const (
+	// LivesInCommunity is a professional whose home address is in the community.
+	LivesInCommunity CommunityFit = iota
+	// ServesCommunity is a professional that lists the community as a service area.
+	ServesCommunity
+	// ServesCommunityWithSurcharge also charges extra to travel there.
+	ServesCommunityWithSurcharge
+)
+
+type (
+	// Candidate holds what the signals know about a professional. Optional facts are
+	// nil when they are unknown or do not apply to the request.
+	Candidate struct {
+		ID           string
+		Rating       float64
+		ReviewCount  int
+		Completeness float64
+		ResponseRate *float64
+		LastActiveAt *time.Time
+		CommunityFit *CommunityFit
+	}
+
+	// Context is shared by every candidate of a ranking.
+	Context struct {
+		Now time.Time
+		// MeanRating is the average of every review, the prior of the Bayesian rating.
+		MeanRating float64
+	}
+
+	// Signal scores one aspect of a candidate. ok is false when the signal has nothing
+	// to say about the candidate, and its weight is then left out of the total.
+	Signal interface {
+		Name() string
+		Score(candidate Candidate, rc Context) (score float64, ok bool)
+	}
+
+	// Weights maps signal names to their weights. Only the ratio between weights
+	// matters; a signal without weight does not count.
+	Weights map[string]float64
+
+	// Contribution is the part of the score that came from one signal.
+	Contribution struct {
+		Signal  string  `json:"signal"`
+		Score   float64 `json:"score"`
+		Weight  float64 `json:"weight"`
+		Points  float64 `json:"points"`
+		Applies bool    `json:"applies"`
+	}
+
+	// Explanation is a score and the contributions it was added up from.
+	Explanation struct {
+		Score         float64        `json:"score"`
+		Contributions []Contribution `json:"contributions"`
+	}
+
+	Ranked struct {
+		Candidate   Candidate
+		Explanation Explanation
+	}
+
+	Engine struct {
+		signals []Signal
+		weights Weights
+	}
+)
+
+// New builds an engine from any signals. Use it to plug signals of your own next to
+// or instead of the built-in ones.
+func New(weights Weights, signals ...Signal) *Engine {
+	return &Engine{signals: signals, weights: weights}
+}
+
+// Score adds up the weighted signals of the candidate. The total is divided by the
+// weights of the signals that applied, so it stays between 0 and 1 and candidates
+// are not punished for facts nobody has yet.
+func (e *Engine) Score(candidate Candidate, rc Context) Explanation {
+	explanation := Explanation{Contributions: make([]Contribution, 0, len(e.signals))}
+
+	var total, applied float64
+	for _, signal := range e.signals {
+		weight := e.weights[signal.Name()]
+		score, ok := signal.Score(candidate, rc)
+		contribution := Contribution{Signal: signal.Name(), Weight: weight, Applies: ok && weight > 0}
+		if contribution.Applies {
+			contribution.Score = round(clamp(score))
+			total += weight * clamp(score)
+			applied += weight
+		}
+		explanation.Contributions = append(explanation.Contributions, contribution)
+	}
+
+	if applied == 0 {
+		return explanation
+	}
+
+	for i := range explanation.Contributions {
+		c := &explanation.Contributions[i]
+		if c.Applies {
+			c.Points = round(c.Weight * c.Score / applied)
+		}
+	}
+	explanation.Score = round(total / applied)
+
+	return explanation
+}
+
+// Rank scores every candidate and sorts them from best to worst. Ties go to the
+// greater id, so the order is stable between pages.
+func (e *Engine) Rank(candidates []Candidate, rc Context) []Ranked {
+	ranked := make([]Ranked, 0, len(candidates))
+	for _, candidate := range candidates {
+		ranked = append(ranked, Ranked{Candidate: candidate, Explanation: e.Score(candidate, rc)})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		return Before(ranked[i].Explanation.Score, ranked[i].Candidate.ID, ranked[j].Explanation.Score, ranked[j].Candidate.ID)
+	})
+
+	return ranked
+}
+
+// Before tells whether (scoreA, idA) comes before (scoreB, idB) in a ranking.
+func Before(scoreA float64, idA string, scoreB float64, idB string) bool {
+	if scoreA != scoreB {
+		return scoreA > scoreB
+	}
+	return idA > idB
+}
+
+func clamp(score float64) float64 {
+	return math.Max(0, math.Min(1, score))
+}
+
+// round keeps scores short in explanations and exact when they go through a cursor.
+func round(value float64) float64 {
+	return math.Round(value*1e6) / 1e6
+}
@@ -0,0 +1,145 @@
+package ranking
+
+import (
+	"math"
+	"time"
+)
+
+// Config sets the weights and tuning of the built-in signals.
+type Config struct {
+	Weights Weights
+	// RatingConfidence is how many reviews of the average rating every professional
+	// starts with. Higher values keep newcomers closer to the average for longer.
+	RatingConfidence float64
+	// ActivityHalfLife is how long it takes an inactive professional to lose half of
+	// the activity score.
+	ActivityHalfLife time.Duration
+}
+
+// DefaultConfig favors the rating while leaving room for complete, active profiles.
+var DefaultConfig = Config{
+	Weights: Weights{
+		SignalRating:       0.35,
+		SignalCompleteness: 0.2,
+		SignalResponseRate: 0.15,
+		SignalActivity:     0.15,
+		SignalProximity:    0.15,
+	},
+	RatingConfidence: 5,
+	ActivityHalfLife: 30 * 24 * time.Hour,
+}
+
+// NewEngine builds an engine with the built-in signals. Settings left at zero take
+// their value from DefaultConfig; weights only do so when none is set at all.
+func NewEngine(cfg Config) *Engine {
+	weights := cfg.Weights
+	configured := false
+	for _, weight := range weights {
+		if weight != 0 {
+			configured = true
+		}
+	}
+	if !configured {
+		weights = DefaultConfig.Weights
+	}
+	if cfg.RatingConfidence <= 0 {
+		cfg.RatingConfidence = DefaultConfig.RatingConfidence
+	}
+	if cfg.ActivityHalfLife <= 0 {
+		cfg.ActivityHalfLife = DefaultConfig.ActivityHalfLife
+	}
+
+	return New(
+		weights,
+		BayesianRating{Confidence: cfg.RatingConfidence},
+		Completeness{},
+		ResponseRate{},
+		Activity{HalfLife: cfg.ActivityHalfLife},
+		Proximity{},
+	)
+}
+
+// BayesianRating blends the reviews of a professional with the average of everyone,
+// so a single five-star review does not beat a long record of good ones.
+type BayesianRating struct {
+	Confidence float64
+}
+
+func (BayesianRating) Name() string { return SignalRating }
+
+func (s BayesianRating) Score(candidate Candidate, rc Context) (float64, bool) {
+	prior := rc.MeanRating
+	if prior <= 0 {
+		// Without any review yet, start everyone in the middle of the scale.
+		prior = 3
+	}
+
+	reviews := float64(candidate.ReviewCount)
+	rating := (s.Confidence*prior + reviews*candidate.Rating) / (s.Confidence + reviews)
+
+	return (rating - 1) / 4, true
+}
+
+// Completeness rewards profiles that give clients enough to decide.
+type Completeness struct{}
+
+func (Completeness) Name() string { return SignalCompleteness }
+
+func (Completeness) Score(candidate Candidate, _ Context) (float64, bool) {
+	return candidate.Completeness, true
+}
+
+// ResponseRate rewards professionals that answer clients. It only applies to
+// candidates whose rate is known.
+type ResponseRate struct{}
+
+func (ResponseRate) Name() string { return SignalResponseRate }
+
+func (ResponseRate) Score(candidate Candidate, _ Context) (float64, bool) {
+	if candidate.ResponseRate == nil {
+		return 0, false
+	}
+	return *candidate.ResponseRate, true
+}
+
+// Activity decays with the time since the professional last used the app or
+// changed their profile.
+type Activity struct {
+	HalfLife time.Duration
+}
+
+func (Activity) Name() string { return SignalActivity }
+
+func (s Activity) Score(candidate Candidate, rc Context) (float64, bool) {
+	if candidate.LastActiveAt == nil {
+		return 0, true
+	}
+
+	idle := rc.Now.Sub(*candidate.LastActiveAt)
+	if idle <= 0 {
+		return 1, true
+	}
+
+	return math.Pow(0.5, float64(idle)/float64(s.HalfLife)), true
+}
+
+// Proximity prefers professionals living in the community the client picked over
+// the ones traveling there. It only applies when a community was picked.
+type Proximity struct{}
+
+func (Proximity) Name() string { return SignalProximity }
+
+func (Proximity) Score(candidate Candidate, _ Context) (float64, bool) {
+	if candidate.CommunityFit == nil {
+		return 0, false
+	}
+
+	switch *candidate.CommunityFit {
+	case LivesInCommunity:
+		return 1, true
+	case ServesCommunity:
+		return 0.75, true
+	default:
+		return 0.5, true
+	}
+}
@@ -0,0 +1,209 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	PasswordAlgorithmBcrypt   = "bcrypt"
+	PasswordAlgorithmArgon2id = "argon2id"
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// PasswordParams decides how new password hashes are produced. Hashes made with
+// other settings keep working and are reported by PasswordNeedsRehash.
+type PasswordParams struct {
+	Algorithm         string
+	BcryptCost        int
+	Argon2Memory      uint32 // KiB
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+}
+
+var DefaultPasswordParams = PasswordParams{
+	Algorithm:         PasswordAlgorithmBcrypt,
+	BcryptCost:        bcrypt.DefaultCost,
+	Argon2Memory:      64 * 1024,
+	Argon2Iterations:  3,
+	Argon2Parallelism: 2,
+}
+
+var (
+	paramsMu       sync.RWMutex
+	passwordParams = DefaultPasswordParams
+
+	// dummyPasswordHash is compared against when there is no user to check, so a
+	// login for an unknown email costs as much time as a wrong password.
+	dummyPasswordHash string
+	dummyOnce         sync.Once
+)
+
+var ErrInvalidPasswordHash = errors.New("password hash is malformed")
+
+// SetPasswordParams replaces the hashing settings, filling zero values with the defaults.
+// It is meant to be called once at startup.
+func SetPasswordParams(params PasswordParams) error {
+	if params.Algorithm == "" {
+		params.Algorithm = DefaultPasswordParams.Algorithm
+	}
+	if params.BcryptCost == 0 {
+		params.BcryptCost = DefaultPasswordParams.BcryptCost
+	}
+	if params.Argon2Memory == 0 {
+		params.Argon2Memory = DefaultPasswordParams.Argon2Memory
+	}
+	if params.Argon2Iterations == 0 {
+		params.Argon2Iterations = DefaultPasswordParams.Argon2Iterations
+	}
+	if params.Argon2Parallelism == 0 {
+		params.Argon2Parallelism = DefaultPasswordParams.Argon2Parallelism
+	}
+
+	if params.Algorithm != PasswordAlgorithmBcrypt && params.Algorithm != PasswordAlgorithmArgon2id {
+		return fmt.Errorf("unsupported password algorithm %q", params.Algorithm)
+	}
+	if params.BcryptCost < bcrypt.MinCost || params.BcryptCost > bcrypt.MaxCost {
+		return fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+
+	paramsMu.Lock()
+	passwordParams = params
+	paramsMu.Unlock()
+
+	return nil
+}
+
+func currentPasswordParams() PasswordParams {
+	paramsMu.RLock()
+	defer paramsMu.RUnlock()
+	return passwordParams
+}
+
+func HashPassword(password string) (string, error) {
+	params := currentPasswordParams()
+
+	if params.Algorithm == PasswordAlgorithmArgon2id {
+		return hashArgon2id(password, params)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), params.BcryptCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hashedPassword), nil
+}
+
+func PasswordMatches(password, encrypted string) bool {
+	if strings.HasPrefix(encrypted, "$argon2id$") {
+		ok, err := verifyArgon2id(password, encrypted)
+		return err == nil && ok
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(encrypted), []byte(password))
+	return err == nil
+}
+
+// PasswordNeedsRehash reports whether the hash was made with another algorithm or
+// weaker settings than the current ones, and should be replaced on the next login.
+func PasswordNeedsRehash(encrypted string) bool {
+	params := currentPasswordParams()
+
+	if strings.HasPrefix(encrypted, "$argon2id$") {
+		if params.Algorithm != PasswordAlgorithmArgon2id {
+			return true
+		}
+		hashParams, _, _, err := decodeArgon2id(encrypted)
+		if err != nil {
+			return true
+		}
+		return hashParams.Argon2Memory != params.Argon2Memory ||
+			hashParams.Argon2Iterations != params.Argon2Iterations ||
+			hashParams.Argon2Parallelism != params.Argon2Parallelism
+	}
+
+	if params.Algorithm != PasswordAlgorithmBcrypt {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(encrypted))
+	if err != nil {
+		return true
+	}
+	return cost != params.BcryptCost
+}
+
+func SimulatePasswordCheck(password string) {
+	dummyOnce.Do(func() {
+		dummyPasswordHash, _ = HashPassword("conecta-mare-dummy-password")
+	})
+	PasswordMatches(password, dummyPasswordHash)
+}
+
+func hashArgon2id(password string, params PasswordParams) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, params.Argon2Iterations, params.Argon2Memory, params.Argon2Parallelism, argon2KeyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		params.Argon2Memory,
+		params.Argon2Iterations,
+		params.Argon2Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func verifyArgon2id(password, encrypted string) (bool, error) {
+	params, salt, key, err := decodeArgon2id(encrypted)
+	if err != nil {
+		return false, err
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, params.Argon2Iterations, params.Argon2Memory, params.Argon2Parallelism, uint32(len(key)))
+
+	return subtle.ConstantTimeCompare(key, otherKey) == 1, nil
+}
+
+// decodeArgon2id parses the PHC string format: $argon2id$v=19$m=65536,t=3,p=2$salt$key
+func decodeArgon2id(encrypted string) (PasswordParams, []byte, []byte, error) {
+	parts := strings.Split(encrypted, "$")
+	if len(parts) != 6 || parts[1] != PasswordAlgorithmArgon2id {
+		return PasswordParams{}, nil, nil, ErrInvalidPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return PasswordParams{}, nil, nil, ErrInvalidPasswordHash
+	}
+
+	params := PasswordParams{Algorithm: PasswordAlgorithmArgon2id}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Argon2Memory, &params.Argon2Iterations, &params.Argon2Parallelism); err != nil {
+		return PasswordParams{}, nil, nil, ErrInvalidPasswordHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return PasswordParams{}, nil, nil, ErrInvalidPasswordHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return PasswordParams{}, nil, nil, ErrInvalidPasswordHash
+	}
+
+	return params, salt, key, nil
+}
@@ -1,25 +1,15 @@
 package security
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"unicode"
-
-	"golang.org/x/crypto/bcrypt"
 )
 
-func HashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", nil
-	}
-	return string(hashedPassword), nil
-}
-
-func PasswordMatches(password, encrypted string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(encrypted), []byte(password))
-	return err == nil
-}
-
 func ValidatePassword(password string) error {
 	if len(password) < 8 {
 		return fmt.Errorf("password must be at least 8 characters")
@@ -43,3 +33,27 @@ func ValidatePassword(password string) error {
 	}
 	return nil
 }
+
+// GenerateToken returns a random URL-safe token meant to be sent to users by email or SMS.
+// Only its hash, from HashToken, should ever be persisted.
+func GenerateToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// KeyedHash returns an HMAC-SHA256 of value. Unlike HashToken it is meant for values
+// that can be guessed, such as emails or phones, which cannot be recovered from the
+// hash by trying every candidate unless the key is known.
+func KeyedHash(key, value string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
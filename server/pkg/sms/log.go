@@ -0,0 +1,45 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LogSender never delivers messages. It logs them and, when an outbox directory is
+// configured, writes each one to a file so codes can be picked up locally.
+type LogSender struct {
+	outboxDir string
+	logger    *slog.Logger
+}
+
+func NewLogSender(outboxDir string, logger *slog.Logger) *LogSender {
+	return &LogSender{
+		outboxDir: outboxDir,
+		logger:    logger,
+	}
+}
+
+func (s *LogSender) Send(ctx context.Context, to, text string) error {
+	s.logger.InfoContext(ctx, "sms not delivered, logging it instead", "to", to, "text", text)
+
+	if s.outboxDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(s.outboxDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create sms outbox directory: %w", err)
+	}
+
+	fileName := fmt.Sprintf("%d_%s.txt", time.Now().UnixNano(), to)
+	content := fmt.Sprintf("To: %s\n\n%s\n", to, text)
+
+	if err := os.WriteFile(filepath.Join(s.outboxDir, fileName), []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write sms to outbox: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,9 @@
+package sms
+
+import "context"
+
+// SMSSender delivers text messages to Brazilian phone numbers in the digits-only
+// format returned by valueobjects.SanitizePhoneNumber.
+type SMSSender interface {
+	Send(ctx context.Context, to, text string) error
+}
@@ -3,8 +3,10 @@ package storage
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"mime/multipart"
+	"strings"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -78,3 +80,156 @@ func (c *StorageClient) UploadFile(
   objectURL := fmt.Sprintf("%s://%s/%s/%s", scheme, c.endpoint, c.bucketName, objectName)
   return objectURL, nil
 }
+
+// RemoveByPrefix deletes every object whose name starts with prefix.
+func (c *StorageClient) RemoveByPrefix(ctx context.Context, prefix string) error {
+  objects := c.client.ListObjects(ctx, c.bucketName, minio.ListObjectsOptions{
+    Prefix:    prefix,
+    Recursive: true,
+  })
+
+  var listErr error
+  toRemove := make(chan minio.ObjectInfo)
+  go func() {
+    defer close(toRemove)
+    for object := range objects {
+      if object.Err != nil {
+        listErr = object.Err
+        return
+      }
+      toRemove <- object
+    }
+  }()
+
+  // The error channel is drained completely so the listing goroutine never blocks.
+  var removeErr error
+  for result := range c.client.RemoveObjects(ctx, c.bucketName, toRemove, minio.RemoveObjectsOptions{}) {
+    if result.Err != nil && removeErr == nil {
+      removeErr = fmt.Errorf("failed to remove object %s: %w", result.ObjectName, result.Err)
+    }
+  }
+
+  if listErr != nil {
+    return fmt.Errorf("failed to list objects under %s: %w", prefix, listErr)
+  }
+  return removeErr
+}
+
+// ListObjectNames returns the names of every object whose name starts with prefix.
+func (c *StorageClient) ListObjectNames(ctx context.Context, prefix string) ([]string, error) {
+  var names []string
+  for object := range c.client.ListObjects(ctx, c.bucketName, minio.ListObjectsOptions{
+    Prefix:    prefix,
+    Recursive: true,
+  }) {
+    if object.Err != nil {
+      return nil, fmt.Errorf("failed to list objects under %s: %w", prefix, object.Err)
+    }
+    names = append(names, object.Key)
+  }
+  return names, nil
+}
+
+// GetObject opens an object for reading. The caller must close it.
+func (c *StorageClient) GetObject(ctx context.Context, objectName string) (io.ReadCloser, error) {
+  object, err := c.client.GetObject(ctx, c.bucketName, objectName, minio.GetObjectOptions{})
+  if err != nil {
+    return nil, fmt.Errorf("failed to get object %s: %w", objectName, err)
+  }
+  return object, nil
+}
+
+// PutObject stores content generated by the server itself, as opposed to UploadFile
+// which takes a file sent by a user.
+func (c *StorageClient) PutObject(
+  ctx context.Context,
+  objectName string,
+  reader io.Reader,
+  size int64,
+  contentType string,
+) error {
+  _, err := c.client.PutObject(ctx, c.bucketName, objectName, reader, size, minio.PutObjectOptions{
+    ContentType: contentType,
+  })
+  if err != nil {
+    return fmt.Errorf("failed to upload object %s: %w", objectName, err)
+  }
+  return nil
+}
+
+// RemoveObject deletes a single object. Removing a missing object is not an error.
+func (c *StorageClient) RemoveObject(ctx context.Context, objectName string) error {
+  if err := c.client.RemoveObject(ctx, c.bucketName, objectName, minio.RemoveObjectOptions{}); err != nil {
+    return fmt.Errorf("failed to remove object %s: %w", objectName, err)
+  }
+  return nil
+}
+
+// ObjectNameFromURL returns the object name behind a URL built by UploadFile, or
+// false when the URL points somewhere else.
+func (c *StorageClient) ObjectNameFromURL(objectURL string) (string, bool) {
+  _, rest, found := strings.Cut(objectURL, "://")
+  if !found {
+    return "", false
+  }
+
+  objectName, found := strings.CutPrefix(rest, fmt.Sprintf("%s/%s/", c.endpoint, c.bucketName))
+  if !found || objectName == "" {
+    return "", false
+  }
+  return objectName, true
+}
+
+// imageContentTypes are the image formats accepted from users.
+var imageContentTypes = map[string]struct{}{
+  "image/jpeg": {},
+  "image/png":  {},
+  "image/webp": {},
+}
+
+// IsImage reports whether an uploaded file declares one of the accepted image formats.
+func IsImage(fileHeader *multipart.FileHeader) bool {
+  _, ok := imageContentTypes[fileHeader.Header.Get("Content-Type")]
+  return ok
+}
+
+// documentContentTypes are the formats accepted for documents, such as certification proofs.
+var documentContentTypes = map[string]struct{}{
+  "application/pdf": {},
+  "image/jpeg":      {},
+  "image/png":       {},
+  "image/webp":      {},
+}
+
+// IsDocument reports whether an uploaded file declares one of the accepted document formats.
+func IsDocument(fileHeader *multipart.FileHeader) bool {
+  _, ok := documentContentTypes[fileHeader.Header.Get("Content-Type")]
+  return ok
+}
+
+// UserObjectPrefixes lists where the files uploaded by a user end up in the public bucket.
+func UserObjectPrefixes(userID string) []string {
+  return []string{
+    fmt.Sprintf("profiles/profile_%s", userID),
+    fmt.Sprintf("projects/%s/", userID),
+    fmt.Sprintf("services/%s/", userID),
+  }
+}
+
+// CertificationPrefix is where the proof documents of a user are kept. They belong in
+// the private bucket, which the server reads to stream them to the users allowed to see them.
+func CertificationPrefix(userID string) string {
+  return fmt.Sprintf("certifications/%s/", userID)
+}
+
+// PrivateUserObjectPrefixes lists where the files uploaded by a user end up in the
+// private bucket.
+func PrivateUserObjectPrefixes(userID string) []string {
+  return []string{CertificationPrefix(userID)}
+}
+
+// ExportPrefix is where the data export archives of a user are kept. They belong in
+// the private bucket.
+func ExportPrefix(userID string) string {
+  return fmt.Sprintf("exports/%s/", userID)
+}
@@ -0,0 +1,22 @@
+package utils
+
+// IsPermutation reports whether IDs lists every key of items exactly once, as
+// expected from a request that reorders a whole collection.
+func IsPermutation[T any](IDs []string, items map[string]T) bool {
+	if len(IDs) != len(items) {
+		return false
+	}
+
+	seen := make(map[string]struct{}, len(IDs))
+	for _, ID := range IDs {
+		if _, ok := items[ID]; !ok {
+			return false
+		}
+		if _, ok := seen[ID]; ok {
+			return false
+		}
+		seen[ID] = struct{}{}
+	}
+
+	return true
+}
@@ -5,9 +5,25 @@ type Role string
 const (
 	Client       Role = "client"
 	Professional Role = "professional"
+	Moderator    Role = "moderator"
+	Admin        Role = "admin"
 )
 
+// AnyRole lists every role, for routes open to any authenticated user.
+var AnyRole = []Role{Client, Professional, Moderator, Admin}
+
 func (r Role) IsValid() bool {
+	switch r {
+	case Client, Professional, Moderator, Admin:
+		return true
+	}
+
+	return false
+}
+
+// IsSelfAssignable reports whether the role can be picked at registration.
+// Staff roles are only granted by an admin.
+func (r Role) IsSelfAssignable() bool {
 	switch r {
 	case Client, Professional:
 		return true
@@ -115,6 +115,8 @@ create_s3_bucket() {
     echo "Criando bucket '$BUCKET_NAME'..."
     docker compose -p "$DOCKER_COMPOSE_PROJECT_NAME" exec -T localstack aws --endpoint-url="$S3_ENDPOINT" s3 mb "s3://$BUCKET_NAME"
 
+    # Só este bucket é público. O bucket de STORAGE_PRIVATE_BUCKET_NAME é criado pelo
+    # servidor sem ACL e nunca deve receber leitura pública.
     echo "Tornando o bucket '$BUCKET_NAME' público para leitura..."
     docker compose -p "$DOCKER_COMPOSE_PROJECT_NAME" exec -T localstack aws --endpoint-url="$S3_ENDPOINT" s3api put-bucket-acl --bucket "$BUCKET_NAME" --acl public-read
 